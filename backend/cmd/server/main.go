@@ -2,18 +2,31 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
-	"fmt"
 	"log"
+	"math"
+	"net"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
+	"github.com/akarsh-2004/aether/internal/channels"
+	"github.com/akarsh-2004/aether/internal/chat"
 	"github.com/akarsh-2004/aether/internal/config"
 	"github.com/akarsh-2004/aether/internal/engine"
+	"github.com/akarsh-2004/aether/internal/engine/tick"
 	"github.com/akarsh-2004/aether/internal/gateway"
+	"github.com/akarsh-2004/aether/internal/lobby"
 	"github.com/akarsh-2004/aether/internal/observability"
+	"github.com/akarsh-2004/aether/internal/persistence/outbox"
+	"github.com/akarsh-2004/aether/internal/persistence/postgres"
+	"github.com/akarsh-2004/aether/internal/persistence/redis"
+	"github.com/akarsh-2004/aether/internal/persistence/snapshot"
+	"github.com/akarsh-2004/aether/internal/scripting"
+	"github.com/akarsh-2004/aether/internal/shard"
+	"github.com/akarsh-2004/aether/internal/world"
 	"go.uber.org/zap"
 )
 
@@ -32,19 +45,202 @@ func main() {
 		logger.Fatal("Failed to load configuration", zap.Error(err))
 	}
 
+	if flag.Arg(0) == "migrate" {
+		if err := runMigrate(cfg.Postgres, logger, flag.Args()[1:]); err != nil {
+			logger.Fatal("Migration failed", zap.Error(err))
+		}
+		return
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	spatialEngine := engine.NewSpatialEngine(cfg.Engine, logger)
-	if err := spatialEngine.Start(ctx); err != nil {
-		logger.Fatal("Failed to start spatial engine", zap.Error(err))
+	pgClient, err := postgres.NewPostgresClient(cfg.Postgres, logger)
+	if err != nil {
+		logger.Fatal("Failed to connect to PostgreSQL", zap.Error(err))
+	}
+	defer pgClient.Close()
+
+	redisClient, err := redis.NewRedisClient(cfg.Redis, logger)
+	if err != nil {
+		logger.Fatal("Failed to connect to Redis", zap.Error(err))
+	}
+	defer redisClient.Close()
+
+	metrics := observability.NewMetrics(logger)
+	if err := metrics.Register(); err != nil {
+		logger.Fatal("Failed to register metrics", zap.Error(err))
 	}
 
-	wsGateway := gateway.NewWebSocketGateway(cfg.Gateway, spatialEngine, logger)
-	if err := wsGateway.Start(ctx); err != nil {
-		logger.Fatal("Failed to start WebSocket gateway", zap.Error(err))
+	if cfg.Metrics.BindAddr != "" {
+		go func() {
+			if err := metrics.StartMetricsServer(cfg.Metrics.BindAddr); err != nil {
+				logger.Error("Metrics server stopped", zap.Error(err))
+			}
+		}()
 	}
 
+	outboxProcessor := outbox.NewOutboxProcessor(pgClient, cfg.Outbox, metrics, logger)
+	outboxProcessor.RegisterBuiltInHandlers()
+	for _, sinkCfg := range cfg.Outbox.Sinks {
+		sink, err := outbox.NewSink(sinkCfg, logger)
+		if err != nil {
+			logger.Fatal("Failed to create outbox sink", zap.String("type", sinkCfg.Type), zap.Error(err))
+		}
+		outboxProcessor.AddSink(sink)
+	}
+	if err := outboxProcessor.Start(ctx); err != nil {
+		logger.Fatal("Failed to start outbox processor", zap.Error(err))
+	}
+
+	snapshotStore := snapshot.NewStore(pgClient, logger)
+
+	// With sharding enabled this process simulates one region of the world
+	var regionNode *shard.Node
+	var spatialEngine *engine.SpatialEngine
+	if cfg.Sharding.Enabled {
+		coordinator := redis.NewRegionCoordinator(
+			redisClient,
+			instanceID(cfg.Gateway),
+			time.Duration(cfg.Sharding.LeaseMs)*time.Millisecond,
+			logger,
+		)
+
+		regionNode, err = shard.NewNode(cfg.Sharding, cfg.Engine, coordinator, logger)
+		if err != nil {
+			logger.Fatal("Failed to create region node", zap.Error(err))
+		}
+		spatialEngine = regionNode.Engine()
+		snapshotStore.SetRegion(regionNode.Region().ID)
+	} else {
+		spatialEngine = engine.NewSpatialEngine(cfg.Engine, logger)
+	}
+	// Engine events go out with the snapshot that persists their state
+	spatialEngine.EnableOutbox(cfg.Outbox.MaxPendingEvents)
+	flushInterval := time.Duration(cfg.Outbox.FlushIntervalMs) * time.Millisecond
+
+	// Pick up where the previous process left off before accepting clients
+	if _, err := snapshotStore.RestoreWorld(ctx, spatialEngine); err != nil {
+		logger.Error("Failed to restore world state, starting empty", zap.Error(err))
+	}
+
+	spatialEngine.AddTickHandler(snapshot.NewSnapshotter(
+		snapshotStore,
+		spatialEngine,
+		cfg.Engine.SnapshotInterval,
+		time.Duration(cfg.Postgres.SnapshotRetention)*time.Second,
+		logger,
+	))
+	if cfg.Engine.SnapshotInterval == 0 {
+		spatialEngine.AddTickHandler(snapshot.NewEventFlusher(pgClient, spatialEngine, flushInterval, logger))
+	}
+
+	// The configured engine is the default world; rooms are created on demand
+	worlds := world.NewManager(cfg.Worlds, cfg.Engine, logger)
+	worlds.SetEventFlusher(func(eng *engine.SpatialEngine) tick.TickHandler {
+		eng.EnableOutbox(cfg.Outbox.MaxPendingEvents)
+		return snapshot.NewEventFlusher(pgClient, eng, flushInterval, logger)
+	})
+
+	// Game logic scripts; each world gets its own Lua state
+	if cfg.Engine.Scripting.Enabled {
+		newScripts := func(ctx context.Context, worldID string) (engine.ScriptHost, error) {
+			runtime, err := scripting.NewRuntime(cfg.Engine.Scripting, logger.With(zap.String("world_id", worldID)))
+			if err != nil {
+				return nil, err
+			}
+			runtime.Start(ctx)
+			return runtime, nil
+		}
+
+		scripts, err := newScripts(ctx, cfg.Worlds.Default)
+		if err != nil {
+			logger.Fatal("Failed to load scripts", zap.Error(err))
+		}
+		spatialEngine.SetScriptHost(scripts)
+		worlds.SetScriptFactory(newScripts)
+	}
+	if err := worlds.AddWorld(cfg.Worlds.Default, spatialEngine, cfg.Engine); err != nil {
+		logger.Fatal("Failed to register default world", zap.Error(err))
+	}
+	worlds.Start(ctx)
+
+	// Reload the config file on change or SIGHUP; worlds apply what they can
+	// between ticks
+	watcher := config.NewWatcher(*configPath, cfg, logger)
+	watcher.Subscribe(func(change config.Change) {
+		worlds.ApplyConfig(change.New.Engine)
+	})
+	watcher.Start(ctx)
+
+	wsGateway := gateway.NewWebSocketGateway(cfg.Gateway, worlds, logger)
+	wsGateway.SetSessionStore(pgClient)
+	wsGateway.SetMetrics(metrics)
+	worlds.SetControlListener(wsGateway.OnControlChanged)
+	worlds.SetSender(wsGateway.SendToClient)
+	wsGateway.SetChannels(channels.NewBroker(cfg.Channels, logger))
+
+	// Chat is audited to Postgres; mute and block lists live in Redis sessions
+	var chatService *chat.Service
+	if cfg.Chat.Enabled {
+		chatService = chat.NewService(cfg.Chat, pgClient, redisClient, logger)
+		chatService.Start(ctx)
+		wsGateway.SetChat(chatService)
+	}
+
+	// Map the whole world into about one degree so GEO distances stay accurate
+	bounds := cfg.Engine.WorldBounds
+	presence := redis.NewPresenceDirectory(
+		redisClient,
+		instanceID(cfg.Gateway),
+		math.Max(bounds.MaxX-bounds.MinX, bounds.MaxY-bounds.MinY),
+		logger,
+	)
+	presence.Start(ctx)
+	wsGateway.SetPresence(presence)
+
+	// Matchmaking queues are shared with every gateway through Redis
+	var lobbyService *lobby.Service
+	if cfg.Lobby.Enabled {
+		lobbyService = lobby.NewService(
+			cfg.Lobby,
+			instanceID(cfg.Gateway),
+			redis.NewLobbyStore(redisClient, logger),
+			worlds,
+			logger,
+		)
+		lobbyService.SetNotifier(wsGateway.NotifyLobby)
+		lobbyService.Start(ctx)
+		wsGateway.SetLobby(lobbyService)
+	}
+
+	if regionNode != nil {
+		regionNode.SetHandoffNotifier(wsGateway.HandOff)
+		if err := regionNode.Start(ctx); err != nil {
+			logger.Fatal("Failed to start region node", zap.Error(err))
+		}
+	}
+
+	// A region engine stops as soon as another instance takes the region
+	engineCtx := ctx
+	var regionLost <-chan struct{}
+	if regionNode != nil {
+		engineCtx = regionNode.Context()
+		regionLost = regionNode.Lost()
+	}
+
+	go func() {
+		if err := spatialEngine.Start(engineCtx); err != nil && !errors.Is(err, context.Canceled) {
+			logger.Fatal("Failed to start spatial engine", zap.Error(err))
+		}
+	}()
+
+	go func() {
+		if err := wsGateway.Start(ctx); err != nil {
+			logger.Error("WebSocket gateway stopped with error", zap.Error(err))
+		}
+	}()
+
 	logger.Info("Aether server started successfully",
 		zap.String("version", "1.0.0"),
 		zap.String("bind_addr", cfg.Gateway.BindAddr),
@@ -54,8 +250,21 @@ func main() {
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
-	<-sigChan
-	logger.Info("Shutdown signal received, initiating graceful shutdown...")
+	lostRegion := false
+	select {
+	case <-sigChan:
+		logger.Info("Shutdown signal received, draining gateway...")
+	case <-regionLost:
+		lostRegion = true
+		logger.Error("Region lost to another instance, draining gateway...")
+	}
+
+	// Stop accepting clients and point existing ones at the next process
+	drainCtx, drainCancel := context.WithTimeout(context.Background(), time.Duration(cfg.Gateway.DrainTimeout)*time.Second)
+	if err := wsGateway.Drain(drainCtx, cfg.Gateway.ReconnectTo); err != nil {
+		logger.Warn("Gateway drain incomplete", zap.Error(err))
+	}
+	drainCancel()
 
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer shutdownCancel()
@@ -64,9 +273,54 @@ func main() {
 		logger.Error("Error during WebSocket gateway shutdown", zap.Error(err))
 	}
 
+	if err := worlds.Shutdown(shutdownCtx); err != nil {
+		logger.Error("Error during world manager shutdown", zap.Error(err))
+	}
+
 	if err := spatialEngine.Shutdown(shutdownCtx); err != nil {
 		logger.Error("Error during spatial engine shutdown", zap.Error(err))
 	}
 
+	// Persist the stopped world so the next process restores the same entities.
+	// A lost region's state belongs to its new owner and must not be overwritten.
+	if !lostRegion {
+		if err := snapshotStore.SaveWorld(shutdownCtx, spatialEngine); err != nil {
+			logger.Error("Failed to snapshot world state", zap.Error(err))
+		}
+	}
+
+	if regionNode != nil {
+		regionNode.Stop(shutdownCtx)
+	}
+
+	if lobbyService != nil {
+		lobbyService.Stop(shutdownCtx)
+	}
+
+	if chatService != nil {
+		chatService.Stop(shutdownCtx)
+	}
+
+	presence.Stop(shutdownCtx)
+	outboxProcessor.Stop()
+
 	logger.Info("Aether server shutdown complete")
 }
+
+// instanceID returns the configured instance ID, or hostname:port.
+func instanceID(cfg config.GatewayConfig) string {
+	if cfg.InstanceID != "" {
+		return cfg.InstanceID
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "localhost"
+	}
+
+	_, port, err := net.SplitHostPort(cfg.BindAddr)
+	if err != nil {
+		return hostname
+	}
+	return net.JoinHostPort(hostname, port)
+}
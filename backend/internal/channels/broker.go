@@ -0,0 +1,263 @@
+package channels
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/akarsh-2004/aether/internal/config"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrUnknownChannel       = errors.New("unknown channel")
+	ErrNotAllowed           = errors.New("not allowed on this channel")
+	ErrNotSubscribed        = errors.New("not subscribed")
+	ErrTooManySubscriptions = errors.New("too many subscriptions")
+	ErrPayloadTooLarge      = errors.New("payload too large")
+	ErrRateLimited          = errors.New("rate limited")
+)
+
+// Channel kinds.
+const (
+	KindGlobal    = "global"
+	KindTeam      = "team"
+	KindProximity = "proximity"
+)
+
+// Channel ACLs.
+const (
+	AccessAll     = "all"
+	AccessMembers = "members"
+	AccessNone    = "none"
+)
+
+// Delivery lists who a published message goes to. For proximity channels the
+// caller narrows Recipients down to the publisher's AOI neighbours.
+type Delivery struct {
+	Channel    string
+	Kind       string
+	Recipients []string
+}
+
+// Broker tracks channel subscriptions and team membership for the clients
+// connected to this gateway, and enforces channel ACLs and rate limits.
+type Broker struct {
+	config      config.ChannelsConfig
+	logger      *zap.Logger
+	subscribers map[string]map[string]struct{} // channel -> client IDs
+	clients     map[string]map[string]struct{} // client ID -> channels
+	teams       map[string]string              // client ID -> team
+	buckets     map[string]*bucket             // client ID + rule -> publish budget
+	mu          sync.RWMutex
+}
+
+func NewBroker(cfg config.ChannelsConfig, logger *zap.Logger) *Broker {
+	return &Broker{
+		config:      cfg,
+		logger:      logger,
+		subscribers: make(map[string]map[string]struct{}),
+		clients:     make(map[string]map[string]struct{}),
+		teams:       make(map[string]string),
+		buckets:     make(map[string]*bucket),
+	}
+}
+
+// resolve splits a channel name into its rule and scope. Team channels need a
+// scope; other kinds must not have one.
+func (b *Broker) resolve(channel string) (string, config.ChannelRule, string, error) {
+	name, scope, _ := strings.Cut(channel, ":")
+
+	rule, ok := b.config.Rules[name]
+	if !ok || (rule.Kind == KindTeam) != (scope != "") {
+		return "", config.ChannelRule{}, "", fmt.Errorf("%w: %s", ErrUnknownChannel, channel)
+	}
+	return name, rule, scope, nil
+}
+
+func (b *Broker) allowedLocked(acl, clientID, scope string) bool {
+	switch acl {
+	case AccessAll:
+		return true
+	case AccessMembers:
+		return b.teams[clientID] == scope
+	default:
+		return false
+	}
+}
+
+func (b *Broker) Subscribe(clientID, channel string) error {
+	_, rule, scope, err := b.resolve(channel)
+	if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.allowedLocked(rule.Subscribe, clientID, scope) {
+		return ErrNotAllowed
+	}
+
+	joined := b.clients[clientID]
+	if _, exists := joined[channel]; exists {
+		return nil
+	}
+	if len(joined) >= b.config.MaxSubscriptions {
+		return ErrTooManySubscriptions
+	}
+
+	if joined == nil {
+		joined = make(map[string]struct{})
+		b.clients[clientID] = joined
+	}
+	joined[channel] = struct{}{}
+
+	if b.subscribers[channel] == nil {
+		b.subscribers[channel] = make(map[string]struct{})
+	}
+	b.subscribers[channel][clientID] = struct{}{}
+	return nil
+}
+
+func (b *Broker) Unsubscribe(clientID, channel string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, exists := b.clients[clientID][channel]; !exists {
+		return ErrNotSubscribed
+	}
+	b.unsubscribeLocked(clientID, channel)
+	return nil
+}
+
+func (b *Broker) unsubscribeLocked(clientID, channel string) {
+	delete(b.clients[clientID], channel)
+	if len(b.clients[clientID]) == 0 {
+		delete(b.clients, clientID)
+	}
+
+	delete(b.subscribers[channel], clientID)
+	if len(b.subscribers[channel]) == 0 {
+		delete(b.subscribers, channel)
+	}
+}
+
+// Publish checks that clientID may publish size bytes to channel now and
+// returns its subscribers.
+func (b *Broker) Publish(clientID, channel string, size int) (*Delivery, error) {
+	name, rule, scope, err := b.resolve(channel)
+	if err != nil {
+		return nil, err
+	}
+	if size > b.config.MaxPayloadBytes {
+		return nil, ErrPayloadTooLarge
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.allowedLocked(rule.Publish, clientID, scope) {
+		return nil, ErrNotAllowed
+	}
+	if rule.RatePerSec > 0 && !b.takeLocked(clientID+"|"+name, rule, time.Now()) {
+		return nil, ErrRateLimited
+	}
+
+	return b.deliveryLocked(channel, rule.Kind), nil
+}
+
+// Broadcast returns the subscribers of channel for a message sent by the
+// server, which bypasses ACLs and rate limits.
+func (b *Broker) Broadcast(channel string) (*Delivery, error) {
+	_, rule, _, err := b.resolve(channel)
+	if err != nil {
+		return nil, err
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return b.deliveryLocked(channel, rule.Kind), nil
+}
+
+func (b *Broker) deliveryLocked(channel, kind string) *Delivery {
+	recipients := make([]string, 0, len(b.subscribers[channel]))
+	for clientID := range b.subscribers[channel] {
+		recipients = append(recipients, clientID)
+	}
+	sort.Strings(recipients)
+
+	return &Delivery{
+		Channel:    channel,
+		Kind:       kind,
+		Recipients: recipients,
+	}
+}
+
+// SetTeam assigns a client to a team, or removes it from its team when team
+// is empty. Team channels the client may no longer use are unsubscribed and
+// returned.
+func (b *Broker) SetTeam(clientID, team string) []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if team == "" {
+		delete(b.teams, clientID)
+	} else {
+		b.teams[clientID] = team
+	}
+
+	var dropped []string
+	for channel := range b.clients[clientID] {
+		_, rule, scope, err := b.resolve(channel)
+		if err == nil && b.allowedLocked(rule.Subscribe, clientID, scope) {
+			continue
+		}
+		b.unsubscribeLocked(clientID, channel)
+		dropped = append(dropped, channel)
+	}
+	sort.Strings(dropped)
+
+	b.logger.Debug("Client team changed",
+		zap.String("client_id", clientID),
+		zap.String("team", team),
+		zap.Int("dropped_channels", len(dropped)),
+	)
+	return dropped
+}
+
+// RemoveClient forgets a disconnected client's subscriptions, team and
+// publish budgets.
+func (b *Broker) RemoveClient(clientID string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for channel := range b.clients[clientID] {
+		b.unsubscribeLocked(clientID, channel)
+	}
+	delete(b.teams, clientID)
+	for name := range b.config.Rules {
+		delete(b.buckets, clientID+"|"+name)
+	}
+}
+
+func (b *Broker) GetStats() map[string]interface{} {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	subscriptions := 0
+	for _, joined := range b.clients {
+		subscriptions += len(joined)
+	}
+
+	return map[string]interface{}{
+		"channels":      len(b.subscribers),
+		"subscribers":   len(b.clients),
+		"subscriptions": subscriptions,
+		"teams":         len(b.teams),
+	}
+}
@@ -0,0 +1,32 @@
+package channels
+
+import (
+	"math"
+	"time"
+
+	"github.com/akarsh-2004/aether/internal/config"
+)
+
+// bucket is a token bucket refilled at the rule's rate up to its burst.
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func (b *Broker) takeLocked(key string, rule config.ChannelRule, now time.Time) bool {
+	bkt, exists := b.buckets[key]
+	if !exists {
+		bkt = &bucket{tokens: float64(rule.Burst), last: now}
+		b.buckets[key] = bkt
+	}
+
+	elapsed := now.Sub(bkt.last).Seconds()
+	bkt.tokens = math.Min(float64(rule.Burst), bkt.tokens+elapsed*rule.RatePerSec)
+	bkt.last = now
+
+	if bkt.tokens < 1 {
+		return false
+	}
+	bkt.tokens--
+	return true
+}
@@ -0,0 +1,89 @@
+package chat
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrEmptyMessage   = errors.New("message is empty")
+	ErrMessageTooLong = errors.New("message too long")
+	ErrRejected       = errors.New("message rejected")
+	ErrUnknownAction  = errors.New("unknown mute action")
+)
+
+// Mute list actions.
+const (
+	ActionMute    = "mute"
+	ActionUnmute  = "unmute"
+	ActionBlock   = "block"
+	ActionUnblock = "unblock"
+)
+
+// Message is one chat line. Filters may rewrite Text; OriginalText keeps what
+// the sender typed for the audit log.
+type Message struct {
+	ID             int64     `json:"id"`
+	WorldID        string    `json:"world_id"`
+	SenderClientID string    `json:"sender_client_id"`
+	SenderEntityID uint32    `json:"sender_entity_id"`
+	X              float64   `json:"x"`
+	Y              float64   `json:"y"`
+	Text           string    `json:"text"`
+	OriginalText   string    `json:"original_text"`
+	RejectedBy     string    `json:"rejected_by,omitempty"` // Filter that refused the message
+	Recipients     int       `json:"recipients"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// HistoryQuery selects audited messages, newest first. Empty fields match
+// everything.
+type HistoryQuery struct {
+	WorldID        string
+	SenderClientID string
+	Before         time.Time
+	Limit          int
+}
+
+// Store persists messages for audit.
+type Store interface {
+	SaveChatMessages(ctx context.Context, messages []*Message) error
+	ChatHistory(ctx context.Context, query HistoryQuery) ([]*Message, error)
+}
+
+// Lists holds whom a client has muted (it stops hearing them) and blocked
+// (neither hears the other).
+type Lists struct {
+	Muted   map[string]bool `json:"muted,omitempty"`
+	Blocked map[string]bool `json:"blocked,omitempty"`
+}
+
+// ListStore keeps mute and block lists with a client's session so they
+// survive a reconnect to another instance.
+type ListStore interface {
+	LoadChatLists(ctx context.Context, clientID string) (*Lists, error)
+	SaveChatLists(ctx context.Context, clientID string, lists *Lists) error
+}
+
+func (l *Lists) apply(action, target string) error {
+	switch action {
+	case ActionMute:
+		if l.Muted == nil {
+			l.Muted = make(map[string]bool)
+		}
+		l.Muted[target] = true
+	case ActionUnmute:
+		delete(l.Muted, target)
+	case ActionBlock:
+		if l.Blocked == nil {
+			l.Blocked = make(map[string]bool)
+		}
+		l.Blocked[target] = true
+	case ActionUnblock:
+		delete(l.Blocked, target)
+	default:
+		return ErrUnknownAction
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package chat
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Filter inspects a message before delivery. It may rewrite msg.Text;
+// returning an error rejects the message. Filters run in order, each seeing
+// the previous one's rewrites.
+type Filter interface {
+	Name() string
+	Apply(msg *Message) error
+}
+
+// WordFilter masks or rejects banned words, matched case-insensitively as
+// whole words.
+type WordFilter struct {
+	pattern *regexp.Regexp
+	reject  bool
+}
+
+func NewWordFilter(words []string, reject bool) *WordFilter {
+	quoted := make([]string, 0, len(words))
+	for _, word := range words {
+		if word = strings.TrimSpace(word); word != "" {
+			quoted = append(quoted, regexp.QuoteMeta(word))
+		}
+	}
+
+	f := &WordFilter{reject: reject}
+	if len(quoted) > 0 {
+		f.pattern = regexp.MustCompile(`(?i)\b(` + strings.Join(quoted, "|") + `)\b`)
+	}
+	return f
+}
+
+func (f *WordFilter) Name() string {
+	return "banned_words"
+}
+
+func (f *WordFilter) Apply(msg *Message) error {
+	if f.pattern == nil || !f.pattern.MatchString(msg.Text) {
+		return nil
+	}
+	if f.reject {
+		return fmt.Errorf("%w: contains banned words", ErrRejected)
+	}
+
+	msg.Text = f.pattern.ReplaceAllStringFunc(msg.Text, func(word string) string {
+		return strings.Repeat("*", utf8.RuneCountInString(word))
+	})
+	return nil
+}
+
+// DuplicateFilter rejects a sender repeating its last message within window.
+type DuplicateFilter struct {
+	window time.Duration
+	last   map[string]lastMessage // sender client ID -> its last message
+	mu     sync.Mutex
+}
+
+type lastMessage struct {
+	text string
+	at   time.Time
+}
+
+func NewDuplicateFilter(window time.Duration) *DuplicateFilter {
+	return &DuplicateFilter{
+		window: window,
+		last:   make(map[string]lastMessage),
+	}
+}
+
+func (f *DuplicateFilter) Name() string {
+	return "duplicate"
+}
+
+func (f *DuplicateFilter) Apply(msg *Message) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	text := strings.ToLower(msg.Text)
+	prev, exists := f.last[msg.SenderClientID]
+	f.last[msg.SenderClientID] = lastMessage{text: text, at: msg.CreatedAt}
+
+	if exists && prev.text == text && msg.CreatedAt.Sub(prev.at) < f.window {
+		return fmt.Errorf("%w: repeated message", ErrRejected)
+	}
+	return nil
+}
+
+// Forget drops a disconnected sender's state.
+func (f *DuplicateFilter) Forget(clientID string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	delete(f.last, clientID)
+}
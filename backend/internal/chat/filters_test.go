@@ -0,0 +1,73 @@
+package chat
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWordFilter(t *testing.T) {
+	words := []string{"darn", " heck ", "", "a.b"}
+
+	tests := []struct {
+		name    string
+		reject  bool
+		text    string
+		want    string
+		wantErr error
+	}{
+		{"clean text", false, "hello there", "hello there", nil},
+		{"masks whole words", false, "darn it, HECK", "**** it, ****", nil},
+		{"ignores parts of words", false, "darned heckle", "darned heckle", nil},
+		{"quotes metacharacters", false, "a.b axb", "*** axb", nil},
+		{"rejects", true, "oh darn", "oh darn", ErrRejected},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := &Message{Text: tt.text}
+			err := NewWordFilter(words, tt.reject).Apply(msg)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if msg.Text != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, msg.Text)
+			}
+		})
+	}
+
+	if err := NewWordFilter(nil, true).Apply(&Message{Text: "anything"}); err != nil {
+		t.Errorf("expected an empty word list to allow everything, got %v", err)
+	}
+}
+
+func TestDuplicateFilter(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+
+	tests := []struct {
+		name    string
+		sender  string
+		text    string
+		after   time.Duration
+		wantErr error
+	}{
+		{"first message", "alice", "hello", 0, nil},
+		{"repeat within the window", "alice", "HELLO", time.Second, ErrRejected},
+		{"other sender", "bob", "hello", time.Second, nil},
+		{"different text", "alice", "bye", 2 * time.Second, nil},
+		{"repeat after the window", "alice", "bye", 10 * time.Second, nil},
+	}
+
+	f := NewDuplicateFilter(5 * time.Second)
+	for _, tt := range tests {
+		msg := &Message{SenderClientID: tt.sender, Text: tt.text, CreatedAt: start.Add(tt.after)}
+		if err := f.Apply(msg); !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.wantErr, err)
+		}
+	}
+
+	f.Forget("alice")
+	if err := f.Apply(&Message{SenderClientID: "alice", Text: "bye", CreatedAt: start.Add(11 * time.Second)}); err != nil {
+		t.Errorf("expected a forgotten sender to start over, got %v", err)
+	}
+}
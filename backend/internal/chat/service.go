@@ -0,0 +1,281 @@
+package chat
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/akarsh-2004/aether/internal/config"
+	"go.uber.org/zap"
+)
+
+// auditBatchSize caps how many messages are written to the store at once.
+const auditBatchSize = 100
+
+// Service moderates chat messages, applies mute and block lists and writes
+// every message, delivered or rejected, to the audit store in the background.
+type Service struct {
+	config   config.ChatConfig
+	store    Store
+	lists    ListStore
+	logger   *zap.Logger
+	filters  []Filter
+	dedupe   *DuplicateFilter
+	cache    map[string]*Lists // client ID -> lists, for clients connected here
+	audit    chan *Message
+	pending  []*Message // left unwritten when the audit writer stopped
+	sent     int64
+	rejected int64
+	dropped  int64
+	mu       sync.RWMutex
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+func NewService(cfg config.ChatConfig, store Store, lists ListStore, logger *zap.Logger) *Service {
+	s := &Service{
+		config: cfg,
+		store:  store,
+		lists:  lists,
+		logger: logger,
+		cache:  make(map[string]*Lists),
+		audit:  make(chan *Message, cfg.AuditBufferSize),
+	}
+
+	s.filters = append(s.filters, NewWordFilter(cfg.BannedWords, cfg.BannedWordAction == "reject"))
+	if cfg.DuplicateWindowSec > 0 {
+		s.dedupe = NewDuplicateFilter(time.Duration(cfg.DuplicateWindowSec) * time.Second)
+		s.filters = append(s.filters, s.dedupe)
+	}
+
+	return s
+}
+
+// AddFilter appends a moderation filter to the chain, after the built-in
+// ones. It should be called before Start.
+func (s *Service) AddFilter(f Filter) {
+	s.filters = append(s.filters, f)
+}
+
+func (s *Service) Start(ctx context.Context) {
+	ctx, s.cancel = context.WithCancel(ctx)
+
+	s.wg.Add(1)
+	go s.auditLoop(ctx)
+}
+
+// Stop flushes queued audit records and stops the writer.
+func (s *Service) Stop(ctx context.Context) {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+
+	batch := s.pending
+	s.pending = nil
+	for {
+		select {
+		case msg := <-s.audit:
+			batch = append(batch, msg)
+		default:
+			s.flush(ctx, batch)
+			return
+		}
+	}
+}
+
+// Radius is how far a message carries from the speaker's entities.
+func (s *Service) Radius() float64 {
+	return s.config.Radius
+}
+
+// Send moderates msg and returns which of audience should receive it. The
+// message is audited whether or not a filter rejects it.
+func (s *Service) Send(msg *Message, audience map[string]struct{}) ([]string, error) {
+	msg.Text = strings.TrimSpace(msg.Text)
+	if msg.Text == "" {
+		return nil, ErrEmptyMessage
+	}
+	if utf8.RuneCountInString(msg.Text) > s.config.MaxLength {
+		return nil, ErrMessageTooLong
+	}
+
+	msg.OriginalText = msg.Text
+	msg.CreatedAt = time.Now()
+
+	for _, f := range s.filters {
+		if err := f.Apply(msg); err != nil {
+			msg.RejectedBy = f.Name()
+			s.mu.Lock()
+			s.rejected++
+			s.mu.Unlock()
+			s.record(msg)
+
+			if !errors.Is(err, ErrRejected) {
+				s.logger.Warn("Chat filter failed", zap.String("filter", f.Name()), zap.Error(err))
+				return nil, ErrRejected
+			}
+			return nil, err
+		}
+	}
+
+	s.mu.Lock()
+	recipients := make([]string, 0, len(audience))
+	for clientID := range audience {
+		if s.hearsLocked(clientID, msg.SenderClientID) {
+			recipients = append(recipients, clientID)
+		}
+	}
+	s.sent++
+	s.mu.Unlock()
+	sort.Strings(recipients)
+
+	msg.Recipients = len(recipients)
+	s.record(msg)
+	return recipients, nil
+}
+
+func (s *Service) hearsLocked(listener, speaker string) bool {
+	if listener == speaker {
+		return true
+	}
+	if l := s.cache[listener]; l != nil && (l.Muted[speaker] || l.Blocked[speaker]) {
+		return false
+	}
+	if l := s.cache[speaker]; l != nil && l.Blocked[listener] {
+		return false
+	}
+	return true
+}
+
+// UpdateLists mutes, unmutes, blocks or unblocks target for clientID and
+// saves the lists with the client's session.
+func (s *Service) UpdateLists(ctx context.Context, clientID, action, target string) error {
+	s.mu.Lock()
+	lists := s.cache[clientID]
+	if lists == nil {
+		lists = &Lists{}
+		s.cache[clientID] = lists
+	}
+	if err := lists.apply(action, target); err != nil {
+		s.mu.Unlock()
+		return err
+	}
+	saved := &Lists{Muted: copySet(lists.Muted), Blocked: copySet(lists.Blocked)}
+	s.mu.Unlock()
+
+	return s.lists.SaveChatLists(ctx, clientID, saved)
+}
+
+// Resume carries the mute and block lists of a previous connection over to
+// clientID.
+func (s *Service) Resume(ctx context.Context, prevClientID, clientID string) error {
+	lists, err := s.lists.LoadChatLists(ctx, prevClientID)
+	if err != nil || lists == nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.cache[clientID] = lists
+	s.mu.Unlock()
+
+	return s.lists.SaveChatLists(ctx, clientID, lists)
+}
+
+// Disconnect forgets a client's cached lists; they stay in its session.
+func (s *Service) Disconnect(clientID string) {
+	s.mu.Lock()
+	delete(s.cache, clientID)
+	s.mu.Unlock()
+
+	if s.dedupe != nil {
+		s.dedupe.Forget(clientID)
+	}
+}
+
+// History returns audited messages, newest first.
+func (s *Service) History(ctx context.Context, query HistoryQuery) ([]*Message, error) {
+	if query.Limit <= 0 || query.Limit > s.config.HistoryLimit {
+		query.Limit = s.config.HistoryLimit
+	}
+	return s.store.ChatHistory(ctx, query)
+}
+
+func (s *Service) record(msg *Message) {
+	select {
+	case s.audit <- msg:
+	default:
+		s.mu.Lock()
+		s.dropped++
+		s.mu.Unlock()
+		s.logger.Warn("Chat audit buffer full, dropping message", zap.String("client_id", msg.SenderClientID))
+	}
+}
+
+func (s *Service) auditLoop(ctx context.Context) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	batch := make([]*Message, 0, auditBatchSize)
+	for {
+		select {
+		case <-ctx.Done():
+			// Stop flushes what is still queued
+			s.pending = batch
+			return
+		case msg := <-s.audit:
+			batch = append(batch, msg)
+			if len(batch) >= auditBatchSize {
+				s.flush(ctx, batch)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			s.flush(ctx, batch)
+			batch = batch[:0]
+		}
+	}
+}
+
+func (s *Service) flush(ctx context.Context, batch []*Message) {
+	for len(batch) > 0 {
+		n := len(batch)
+		if n > auditBatchSize {
+			n = auditBatchSize
+		}
+
+		if err := s.store.SaveChatMessages(ctx, batch[:n]); err != nil {
+			s.logger.Error("Failed to write chat audit log", zap.Int("messages", n), zap.Error(err))
+		}
+		batch = batch[n:]
+	}
+}
+
+func (s *Service) GetStats() map[string]interface{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return map[string]interface{}{
+		"messages_sent":     s.sent,
+		"messages_rejected": s.rejected,
+		"audit_dropped":     s.dropped,
+		"audit_queued":      len(s.audit),
+		"cached_lists":      len(s.cache),
+	}
+}
+
+func copySet(set map[string]bool) map[string]bool {
+	if len(set) == 0 {
+		return nil
+	}
+	copied := make(map[string]bool, len(set))
+	for k, v := range set {
+		copied[k] = v
+	}
+	return copied
+}
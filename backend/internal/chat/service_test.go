@@ -0,0 +1,131 @@
+package chat
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/akarsh-2004/aether/internal/config"
+	"go.uber.org/zap"
+)
+
+// memLists keeps chat lists in memory, as a session store would.
+type memLists map[string]*Lists
+
+func (m memLists) LoadChatLists(ctx context.Context, clientID string) (*Lists, error) {
+	return m[clientID], nil
+}
+
+func (m memLists) SaveChatLists(ctx context.Context, clientID string, lists *Lists) error {
+	m[clientID] = lists
+	return nil
+}
+
+func testService(cfg config.ChatConfig) *Service {
+	cfg.MaxLength = 100
+	cfg.AuditBufferSize = 100
+	return NewService(cfg, nil, memLists{}, zap.NewNop())
+}
+
+func audience(clientIDs ...string) map[string]struct{} {
+	set := make(map[string]struct{}, len(clientIDs))
+	for _, id := range clientIDs {
+		set[id] = struct{}{}
+	}
+	return set
+}
+
+func TestSend_MuteAndBlock(t *testing.T) {
+	type update struct{ client, action, target string }
+
+	tests := []struct {
+		name    string
+		updates []update
+		sender  string
+		want    string
+	}{
+		{"everyone hears by default", nil, "alice", "alice,bob,carol"},
+		{"mute silences the speaker for the muter", []update{{"bob", ActionMute, "alice"}}, "alice", "alice,carol"},
+		{"mute is one way", []update{{"bob", ActionMute, "alice"}}, "bob", "alice,bob,carol"},
+		{"block silences the blocked speaker", []update{{"bob", ActionBlock, "alice"}}, "alice", "alice,carol"},
+		{"block works both ways", []update{{"bob", ActionBlock, "alice"}}, "bob", "bob,carol"},
+		{"unmute", []update{{"bob", ActionMute, "alice"}, {"bob", ActionUnmute, "alice"}}, "alice", "alice,bob,carol"},
+		{"unblock", []update{{"bob", ActionBlock, "alice"}, {"bob", ActionUnblock, "alice"}}, "bob", "alice,bob,carol"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := testService(config.ChatConfig{})
+			for _, u := range tt.updates {
+				if err := s.UpdateLists(context.Background(), u.client, u.action, u.target); err != nil {
+					t.Fatalf("update lists: %v", err)
+				}
+			}
+
+			recipients, err := s.Send(&Message{SenderClientID: tt.sender, Text: "hi"}, audience("alice", "bob", "carol"))
+			if err != nil {
+				t.Fatalf("send: %v", err)
+			}
+			if got := strings.Join(recipients, ","); got != tt.want {
+				t.Errorf("expected recipients %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestUpdateLists_UnknownAction(t *testing.T) {
+	s := testService(config.ChatConfig{})
+
+	if err := s.UpdateLists(context.Background(), "bob", "ignore", "alice"); !errors.Is(err, ErrUnknownAction) {
+		t.Errorf("expected ErrUnknownAction, got %v", err)
+	}
+}
+
+func TestResume_CarriesLists(t *testing.T) {
+	s := testService(config.ChatConfig{})
+	ctx := context.Background()
+
+	if err := s.UpdateLists(ctx, "bob-old", ActionBlock, "alice"); err != nil {
+		t.Fatalf("update lists: %v", err)
+	}
+	s.Disconnect("bob-old")
+
+	if err := s.Resume(ctx, "bob-old", "bob"); err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	recipients, _ := s.Send(&Message{SenderClientID: "alice", Text: "hi"}, audience("alice", "bob"))
+	if got := strings.Join(recipients, ","); got != "alice" {
+		t.Errorf("expected the block to survive the reconnect, got recipients %s", got)
+	}
+}
+
+func TestSend_Rejections(t *testing.T) {
+	s := testService(config.ChatConfig{BannedWords: []string{"darn"}, BannedWordAction: "reject"})
+
+	tests := []struct {
+		text string
+		want error
+	}{
+		{"   ", ErrEmptyMessage},
+		{strings.Repeat("a", 101), ErrMessageTooLong},
+		{"well DARN it", ErrRejected},
+	}
+
+	for _, tt := range tests {
+		msg := &Message{SenderClientID: "alice", Text: tt.text}
+		if _, err := s.Send(msg, audience("alice")); !errors.Is(err, tt.want) {
+			t.Errorf("%q: expected %v, got %v", tt.text, tt.want, err)
+		}
+	}
+
+	// Rejected messages are still audited, with the filter that refused them
+	select {
+	case msg := <-s.audit:
+		if msg.RejectedBy != "banned_words" {
+			t.Errorf("expected the audit record to name the filter, got %q", msg.RejectedBy)
+		}
+	default:
+		t.Error("expected the rejected message to be audited")
+	}
+}
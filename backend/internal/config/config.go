@@ -3,6 +3,9 @@ package config
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
@@ -12,6 +15,14 @@ type Config struct {
 	Gateway GatewayConfig `yaml:"gateway"`
 	Redis   RedisConfig   `yaml:"redis"`
 	Postgres PostgresConfig `yaml:"postgres"`
+	Outbox   OutboxConfig   `yaml:"outbox"`
+	Metrics  MetricsConfig  `yaml:"metrics"`
+	Sharding ShardingConfig `yaml:"sharding"`
+	Worlds   WorldsConfig   `yaml:"worlds"`
+	Lobby    LobbyConfig    `yaml:"lobby"`
+	Channels ChannelsConfig `yaml:"channels"`
+	Chat     ChatConfig     `yaml:"chat"`
+	Reload   ReloadConfig   `yaml:"reload"`
 }
 
 type EngineConfig struct {
@@ -22,6 +33,132 @@ type EngineConfig struct {
 	AOIRadius     float64 `yaml:"aoi_radius"`      // Area of Interest radius
 	QuadtreeDepth int     `yaml:"quadtree_depth"`  // Maximum quadtree depth
 	QuadtreeCapacity int  `yaml:"quadtree_capacity"` // Entities per quadtree node
+	ResumeGraceSec   int  `yaml:"resume_grace_sec"`  // How long restored entities wait for their client
+	SnapshotInterval int  `yaml:"snapshot_interval"` // Ticks between world snapshots, 0 disables
+	PossessRadius    float64 `yaml:"possess_radius"`  // How close a client's entity must be to possess another, 0 disables the check
+	EntityTypes      map[string]EntitySchema `yaml:"entity_types"` // Replicated attributes per entity type
+	SpatialIndex     string  `yaml:"spatial_index"`  // quadtree (2.5D, queries ignore height) or octree
+	Gravity          float64 `yaml:"gravity"`        // Downward speed gained per tick; 0 lets clients move vertically
+	JumpSpeed        float64 `yaml:"jump_speed"`     // Max upward speed a grounded entity may jump with
+	Input            InputConfig `yaml:"input"`      // Input-command movement for client-controlled entities
+	InterpolationDelayMs int  `yaml:"interpolation_delay_ms"` // Render delay suggested to clients in TimeSync
+	HistoryTicks         int  `yaml:"history_ticks"`          // Ticks of entity positions kept for PositionAt, 0 disables
+	Projectiles map[string]ProjectileType `yaml:"projectiles"` // Projectiles clients may fire, keyed by entity type
+	Scripting   ScriptingConfig           `yaml:"scripting"`   // Lua game logic hooks
+	Zones       []ZoneConfig              `yaml:"zones"`       // Named areas that raise enter and exit events
+	ZoneFiles   []string                  `yaml:"zone_files"`  // Map files with more zones, relative to the config file
+	Spawn       SpawnConfig               `yaml:"spawn"`       // Where clients' entities appear
+	Limits      LimitsConfig              `yaml:"limits"`      // Entity caps and quotas below MaxEntities
+}
+
+// LimitsConfig caps how many entities a world holds. MaxEntities is the hard
+// cap that no spawn may exceed. Client spawns stop earlier, at the soft cap,
+// so projectiles and scripted entities keep working in a full world.
+type LimitsConfig struct {
+	SoftCapPercent int            `yaml:"soft_cap_percent"` // Client spawns are refused at this share of max_entities
+	PerClient      int            `yaml:"per_client"`       // Entities one client may control, 0 for no limit
+	PerType        map[string]int `yaml:"per_type"`         // Most entities of a type, by entity type
+}
+
+// SoftMaxEntities is the entity count at which client spawns are refused.
+func (e EngineConfig) SoftMaxEntities() int {
+	soft := e.MaxEntities * e.Limits.SoftCapPercent / 100
+	if soft < 1 {
+		soft = 1
+	}
+	return soft
+}
+
+// SpawnConfig is the server's spawn policy for client entities:
+//
+//   - "client": at the coordinates in the SpawnRequest
+//   - "points": at one of Points, the one with the most room unless the client names one
+//   - "zone": at a random position inside Zone
+//   - "farthest": at the candidate farthest from other entities, inside Zone if set
+type SpawnConfig struct {
+	Policy      string       `yaml:"policy"`
+	Points      []SpawnPoint `yaml:"points"`       // Named spawn points, which clients may also request under other policies
+	Zone        string       `yaml:"zone"`         // Zone for the zone and farthest policies
+	AllowClient bool         `yaml:"allow_client"` // Accept client coordinates under the other policies
+	Clearance   float64      `yaml:"clearance"`    // Nothing may stand this close to a spawn position, 0 disables
+	Attempts    int          `yaml:"attempts"`     // Random candidates tried per spawn
+}
+
+// SpawnPoint is a named spawn location. Entities appear at a random spot
+// within Radius of it.
+type SpawnPoint struct {
+	Name   string  `yaml:"name"`
+	X      float64 `yaml:"x"`
+	Y      float64 `yaml:"y"`
+	Z      float64 `yaml:"z"`
+	Radius float64 `yaml:"radius"`
+}
+
+// ZoneConfig is a named area of the world: the rectangle MinX..MaxX,
+// MinY..MaxY, or the polygon Points if given. Zones span the world's full
+// height and may overlap.
+type ZoneConfig struct {
+	Name            string  `yaml:"name"`
+	MinX            float64 `yaml:"min_x"`
+	MinY            float64 `yaml:"min_y"`
+	MaxX            float64 `yaml:"max_x"`
+	MaxY            float64 `yaml:"max_y"`
+	Points          []Point `yaml:"points"`           // Polygon vertices in order
+	Safe            bool    `yaml:"safe"`             // Projectiles do not hit entities inside
+	SpeedMultiplier float64 `yaml:"speed_multiplier"` // Scales horizontal movement inside, 0 for unchanged
+}
+
+type Point struct {
+	X float64 `yaml:"x"`
+	Y float64 `yaml:"y"`
+}
+
+// zoneFile is the format of a map file listed in zone_files.
+type zoneFile struct {
+	Zones []ZoneConfig `yaml:"zones"`
+}
+
+// ScriptingConfig loads Lua scripts from Dir into every world's engine.
+type ScriptingConfig struct {
+	Enabled          bool   `yaml:"enabled"`
+	Dir              string `yaml:"dir"`                // *.lua files, loaded in name order
+	ReloadIntervalMs int    `yaml:"reload_interval_ms"` // How often Dir is checked for changes, 0 disables hot reload
+	HookTimeoutMs    int    `yaml:"hook_timeout_ms"`    // Scripts running longer than this in one hook are stopped
+}
+
+// ProjectileType describes a server-simulated projectile. Projectiles fly in
+// a straight line and despawn when they hit an entity or the ground, leave
+// the world, or outlive their TTL.
+type ProjectileType struct {
+	Speed      float64 `yaml:"speed"`       // Units per tick
+	TTLMs      int     `yaml:"ttl_ms"`      // Lifetime before despawning as expired
+	HitRadius  float64 `yaml:"hit_radius"`  // Entities this close to the flight path are hit
+	Damage     int     `yaml:"damage"`      // Subtracted from the target's int "health" attribute, if it has one
+	CooldownMs int     `yaml:"cooldown_ms"` // Minimum time between shots of this type from one entity
+}
+
+// InputConfig switches client-controlled entities from raw movement deltas to
+// input commands, each simulated once with the shared movement step.
+type InputConfig struct {
+	Enabled          bool    `yaml:"enabled"`           // Accept INPUT_COMMAND and reject MOVEMENT_DELTA
+	MoveSpeed        float64 `yaml:"move_speed"`        // Units per tick at full stick
+	SprintMultiplier float64 `yaml:"sprint_multiplier"` // Speed factor while sprint is held
+	MaxPerTick       int     `yaml:"max_per_tick"`      // Commands applied per entity per tick; extras wait for the next
+	MaxBuffered      int     `yaml:"max_buffered"`      // Commands queued per entity before new ones are dropped
+}
+
+// EntitySchema lists the replicated attributes of an entity type. Entities
+// of types without a schema have no attributes.
+type EntitySchema struct {
+	Attributes  []AttributeDef `yaml:"attributes"`
+	AllowCustom bool           `yaml:"allow_custom"` // Accept unlisted attributes as custom key/values
+}
+
+// AttributeDef declares one attribute and the value new entities start with.
+type AttributeDef struct {
+	Name    string `yaml:"name"`
+	Kind    string `yaml:"kind"`    // int, float, string or bool
+	Default string `yaml:"default"` // Parsed as Kind; empty for the zero value
 }
 
 type GatewayConfig struct {
@@ -33,6 +170,10 @@ type GatewayConfig struct {
 	WriteWait        int    `yaml:"write_wait"`         // Write wait timeout in seconds
 	MaxMessageSize   int64  `yaml:"max_message_size"`   // Maximum message size
 	EnableCompression bool  `yaml:"enable_compression"` // Enable WebSocket compression
+	DrainTimeout     int    `yaml:"drain_timeout"`      // Seconds to wait for clients to leave while draining
+	ReconnectTo      string `yaml:"reconnect_to"`       // Address advertised to clients while draining
+	InstanceID       string `yaml:"instance_id"`        // Identifies this instance in the presence directory; defaults to hostname:port
+	AdminBindAddr    string `yaml:"admin_bind_addr"`    // Listener for operator routes; keep it off the public network, empty disables them
 }
 
 type RedisConfig struct {
@@ -40,6 +181,8 @@ type RedisConfig struct {
 	Password string `yaml:"password"`  // Redis password
 	DB       int    `yaml:"db"`        // Redis database number
 	PoolSize int    `yaml:"pool_size"` // Connection pool size
+	PresenceTimeoutSec int `yaml:"presence_timeout_sec"` // Client is offline after this long without a heartbeat
+	InstanceTimeoutSec int `yaml:"instance_timeout_sec"` // Instance and its clients are removed after this long without a heartbeat
 }
 
 type PostgresConfig struct {
@@ -51,6 +194,141 @@ type PostgresConfig struct {
 	MaxOpenConns    int    `yaml:"max_open_conns"`    // Maximum open connections
 	MaxIdleConns    int    `yaml:"max_idle_conns"`    // Maximum idle connections
 	ConnMaxLifetime int    `yaml:"conn_max_lifetime"` // Connection max lifetime in seconds
+	SnapshotRetention int  `yaml:"snapshot_retention"` // Seconds to keep world snapshots, 0 keeps all
+	SkipMigrations  bool   `yaml:"skip_migrations"`   // Don't apply pending migrations on connect
+}
+
+type OutboxConfig struct {
+	BatchSize      int `yaml:"batch_size"`       // Events claimed per poll
+	PollIntervalMs int `yaml:"poll_interval_ms"` // Delay between polls
+	LeaseMs        int `yaml:"lease_ms"`         // How long a claimed event stays locked to this worker
+	MaxAttempts    int `yaml:"max_attempts"`     // Attempts before an event is dead-lettered
+	BaseBackoffMs  int `yaml:"base_backoff_ms"`  // Retry delay after the first failure, doubled per attempt
+	MaxBackoffMs   int `yaml:"max_backoff_ms"`   // Upper bound for the retry delay
+	MaxPendingEvents int `yaml:"max_pending_events"` // Engine events a world keeps while the database is unreachable
+	FlushIntervalMs  int `yaml:"flush_interval_ms"`  // How often worlds without snapshots write their events
+	Sinks          []SinkConfig `yaml:"sinks"`   // External destinations for processed events
+}
+
+type SinkConfig struct {
+	Type      string `yaml:"type"`       // file, nats, kafka or webhook
+	URL       string `yaml:"url"`        // nats://host:4222, Kafka REST proxy base URL or webhook URL
+	Path      string `yaml:"path"`       // JSONL file path for the file sink
+	Subject   string `yaml:"subject"`    // NATS subject prefix, event type is appended
+	Topic     string `yaml:"topic"`      // Kafka topic
+	Secret    string `yaml:"secret"`     // Webhook HMAC signing secret
+	TimeoutMs int    `yaml:"timeout_ms"` // Per-delivery timeout
+}
+
+type MetricsConfig struct {
+	BindAddr string `yaml:"bind_addr"` // Prometheus /metrics bind address, empty disables
+}
+
+// ShardingConfig splits WorldBounds into a Columns x Rows grid of regions.
+// Each process simulates one region and hands entities to its neighbours.
+type ShardingConfig struct {
+	Enabled       bool    `yaml:"enabled"`
+	Columns       int     `yaml:"columns"`        // Regions along X
+	Rows          int     `yaml:"rows"`           // Regions along Y
+	Region        string  `yaml:"region"`         // Region this process owns, e.g. "r0-1" (column 0, row 1)
+	AdvertiseAddr string  `yaml:"advertise_addr"` // Address clients are sent to when handed into this region
+	GhostMargin   float64 `yaml:"ghost_margin"`   // Width of the border band mirrored to neighbours, 0 uses aoi_radius
+	GhostInterval int     `yaml:"ghost_interval"` // Ticks between ghost updates
+	LeaseMs       int     `yaml:"lease_ms"`       // Region ownership lease in Redis
+}
+
+// WorldsConfig controls the worlds hosted by one process. The default world
+// is described by the engine section; others are created from templates on
+// demand and torn down once empty.
+type WorldsConfig struct {
+	Default        string                   `yaml:"default"`          // ID of the world that always exists and is snapshotted
+	MaxWorlds      int                      `yaml:"max_worlds"`       // Worlds this process may host, including the default
+	IdleTimeoutSec int                      `yaml:"idle_timeout_sec"` // Empty on-demand worlds are removed after this long
+	Templates      map[string]WorldTemplate `yaml:"templates"`        // Presets for on-demand worlds, keyed by name
+}
+
+// WorldTemplate overrides parts of EngineConfig. Zero values inherit the
+// engine section.
+type WorldTemplate struct {
+	TickRateMs  int     `yaml:"tick_rate_ms"`
+	MaxEntities int     `yaml:"max_entities"`
+	WorldBounds *Bounds `yaml:"world_bounds"`
+	AOIRadius   float64 `yaml:"aoi_radius"`
+}
+
+// Apply returns base with the template's overrides applied.
+func (t WorldTemplate) Apply(base EngineConfig) EngineConfig {
+	cfg := base
+	if t.TickRateMs > 0 {
+		cfg.TickRateMs = t.TickRateMs
+	}
+	if t.MaxEntities > 0 {
+		cfg.MaxEntities = t.MaxEntities
+	}
+	if t.WorldBounds != nil {
+		cfg.WorldBounds = *t.WorldBounds
+	}
+	if t.AOIRadius > 0 {
+		cfg.AOIRadius = t.AOIRadius
+	}
+	// On-demand worlds are not persisted
+	cfg.SnapshotInterval = 0
+	return cfg
+}
+
+// LobbyConfig controls matchmaking. Queues are shared through Redis by every
+// gateway; whichever instance forms a match hosts its world.
+type LobbyConfig struct {
+	Enabled        bool                 `yaml:"enabled"`
+	AdvertiseAddr  string               `yaml:"advertise_addr"`   // Sent to matched clients connected to other instances
+	PollIntervalMs int                  `yaml:"poll_interval_ms"` // Delay between matching passes
+	TicketTTLSec   int                  `yaml:"ticket_ttl_sec"`   // How long a join ticket and its reserved place stay valid
+	Queues         map[string]QueueRule `yaml:"queues"`           // Matchmaking rules, keyed by queue name
+}
+
+// ChannelsConfig controls the gateway's pub/sub channels. A channel is named
+// after its rule, optionally followed by ":<scope>" (e.g. "team:red").
+type ChannelsConfig struct {
+	MaxPayloadBytes  int                    `yaml:"max_payload_bytes"` // Largest payload a client may publish
+	MaxSubscriptions int                    `yaml:"max_subscriptions"` // Channels a client may join at once
+	Rules            map[string]ChannelRule `yaml:"rules"`             // Keyed by channel name prefix
+}
+
+// ChannelRule describes who may use a channel and how often. Subscribe and
+// Publish are one of "all", "members" (clients on the channel's team) or
+// "none"; the server may always publish.
+type ChannelRule struct {
+	Kind       string  `yaml:"kind"`         // global, team or proximity
+	Subscribe  string  `yaml:"subscribe"`    // ACL for subscribing
+	Publish    string  `yaml:"publish"`      // ACL for publishing
+	RatePerSec float64 `yaml:"rate_per_sec"` // Sustained publishes per client, 0 for no limit
+	Burst      int     `yaml:"burst"`        // Publishes allowed at once
+}
+
+// ChatConfig controls proximity text chat. Messages pass through the filter
+// chain before delivery and are written to Postgres for audit.
+type ChatConfig struct {
+	Enabled            bool     `yaml:"enabled"`
+	Radius             float64  `yaml:"radius"`               // Distance a message carries from the speaker's entities
+	MaxLength          int      `yaml:"max_length"`           // Longest message in characters
+	BannedWords        []string `yaml:"banned_words"`         // Matched case-insensitively as whole words
+	BannedWordAction   string   `yaml:"banned_word_action"`   // mask or reject
+	DuplicateWindowSec int      `yaml:"duplicate_window_sec"` // Reject a repeat of the sender's last message within this window, 0 to allow
+	AuditBufferSize    int      `yaml:"audit_buffer_size"`    // Messages queued for the audit writer
+	HistoryLimit       int      `yaml:"history_limit"`        // Largest page returned by the history endpoint
+}
+
+// QueueRule describes how a queue forms groups. The allowed skill spread
+// starts at MaxSkillSpread and widens by SkillSpreadPerSec while the oldest
+// player in a group waits.
+type QueueRule struct {
+	Template          string  `yaml:"template"`             // World template reserved for each match
+	MinPlayers        int     `yaml:"min_players"`          // Smallest group that may start
+	MaxPlayers        int     `yaml:"max_players"`          // Largest group, parties included
+	SameRegion        bool    `yaml:"same_region"`          // Only group players from the same region
+	MaxSkillSpread    float64 `yaml:"max_skill_spread"`     // Initial max skill difference, 0 ignores skill
+	SkillSpreadPerSec float64 `yaml:"skill_spread_per_sec"` // Widening per second waited
+	MaxWaitSec        int     `yaml:"max_wait_sec"`         // Players are removed from the queue after this long
 }
 
 type Bounds struct {
@@ -58,6 +336,8 @@ type Bounds struct {
 	MinY float64 `yaml:"min_y"`
 	MaxX float64 `yaml:"max_x"`
 	MaxY float64 `yaml:"max_y"`
+	MinZ float64 `yaml:"min_z"` // Floor; terrain never goes below it
+	MaxZ float64 `yaml:"max_z"` // Ceiling; equal to MinZ for a flat world
 }
 
 func Load(path string) (*Config, error) {
@@ -71,6 +351,14 @@ func Load(path string) (*Config, error) {
 		return nil, fmt.Errorf("failed to parse config file: %w", err)
 	}
 
+	if err := cfg.Engine.loadZoneFiles(filepath.Dir(path)); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.applyEnv(); err != nil {
+		return nil, fmt.Errorf("invalid environment override: %w", err)
+	}
+
 	if err := cfg.validate(); err != nil {
 		return nil, fmt.Errorf("invalid configuration: %w", err)
 	}
@@ -78,6 +366,63 @@ func Load(path string) (*Config, error) {
 	return &cfg, nil
 }
 
+func (s *SpawnConfig) validate(zones map[string]bool) error {
+	switch s.Policy {
+	case "client", "farthest":
+	case "points":
+		if len(s.Points) == 0 {
+			return fmt.Errorf("engine.spawn.points is required for the points policy")
+		}
+	case "zone":
+		if s.Zone == "" {
+			return fmt.Errorf("engine.spawn.zone is required for the zone policy")
+		}
+	default:
+		return fmt.Errorf("engine.spawn.policy must be client, points, zone or farthest, got %q", s.Policy)
+	}
+
+	if s.Zone != "" && !zones[s.Zone] {
+		return fmt.Errorf("engine.spawn.zone: unknown zone %q", s.Zone)
+	}
+
+	names := make(map[string]bool, len(s.Points))
+	for _, p := range s.Points {
+		if p.Name == "" || names[p.Name] {
+			return fmt.Errorf("engine.spawn.points: every point needs a unique name, got %q", p.Name)
+		}
+		names[p.Name] = true
+		if p.Radius < 0 {
+			return fmt.Errorf("engine.spawn.points.%s: radius cannot be negative", p.Name)
+		}
+	}
+
+	if s.Clearance < 0 || s.Attempts < 1 {
+		return fmt.Errorf("engine.spawn.clearance cannot be negative and attempts must be at least 1")
+	}
+	return nil
+}
+
+// loadZoneFiles appends the zones from every map file in ZoneFiles.
+func (e *EngineConfig) loadZoneFiles(dir string) error {
+	for _, name := range e.ZoneFiles {
+		if !filepath.IsAbs(name) {
+			name = filepath.Join(dir, name)
+		}
+
+		data, err := os.ReadFile(name)
+		if err != nil {
+			return fmt.Errorf("failed to read zone file: %w", err)
+		}
+
+		var file zoneFile
+		if err := yaml.Unmarshal(data, &file); err != nil {
+			return fmt.Errorf("failed to parse zone file %s: %w", name, err)
+		}
+		e.Zones = append(e.Zones, file.Zones...)
+	}
+	return nil
+}
+
 func (c *Config) validate() error {
 	if c.Engine.TickRateMs < 10 || c.Engine.TickRateMs > 100 {
 		return fmt.Errorf("engine.tick_rate_ms must be between 10-100ms, got %d", c.Engine.TickRateMs)
@@ -95,10 +440,129 @@ func (c *Config) validate() error {
 		return fmt.Errorf("engine.aoi_radius must be positive, got %f", c.Engine.AOIRadius)
 	}
 
+	if c.Engine.ResumeGraceSec < 0 {
+		return fmt.Errorf("engine.resume_grace_sec cannot be negative, got %d", c.Engine.ResumeGraceSec)
+	}
+
+	for entityType, schema := range c.Engine.EntityTypes {
+		seen := make(map[string]bool, len(schema.Attributes))
+		for _, attr := range schema.Attributes {
+			if attr.Name == "" || seen[attr.Name] {
+				return fmt.Errorf("engine.entity_types.%s: attribute names must be unique and non-empty", entityType)
+			}
+			seen[attr.Name] = true
+			if !validAttributeDefault(attr.Kind, attr.Default) {
+				return fmt.Errorf("engine.entity_types.%s.%s: invalid kind %q or default %q", entityType, attr.Name, attr.Kind, attr.Default)
+			}
+		}
+	}
+
+	if c.Engine.WorldBounds.MaxZ < c.Engine.WorldBounds.MinZ {
+		return fmt.Errorf("engine.world_bounds.max_z cannot be below min_z")
+	}
+
+	switch c.Engine.SpatialIndex {
+	case "", "quadtree":
+	case "octree":
+		if c.Engine.WorldBounds.MaxZ == c.Engine.WorldBounds.MinZ {
+			return fmt.Errorf("engine.spatial_index octree needs world_bounds with max_z above min_z")
+		}
+	default:
+		return fmt.Errorf("engine.spatial_index must be quadtree or octree, got %q", c.Engine.SpatialIndex)
+	}
+
+	if c.Engine.Gravity < 0 || c.Engine.JumpSpeed < 0 {
+		return fmt.Errorf("engine.gravity and engine.jump_speed cannot be negative")
+	}
+
+	if sc := c.Engine.Scripting; sc.Enabled {
+		if sc.Dir == "" {
+			return fmt.Errorf("engine.scripting.dir is required when scripting is enabled")
+		}
+		if sc.ReloadIntervalMs < 0 || sc.HookTimeoutMs <= 0 {
+			return fmt.Errorf("engine.scripting.hook_timeout_ms must be positive and reload_interval_ms non-negative")
+		}
+	}
+
+	if in := c.Engine.Input; in.Enabled {
+		if in.MoveSpeed <= 0 || in.SprintMultiplier < 1 {
+			return fmt.Errorf("engine.input.move_speed must be positive and sprint_multiplier at least 1")
+		}
+		if in.MoveSpeed*in.SprintMultiplier > c.Engine.MaxSpeed {
+			return fmt.Errorf("engine.input: move_speed * sprint_multiplier cannot exceed engine.max_speed")
+		}
+		if in.MaxPerTick < 1 || in.MaxBuffered < in.MaxPerTick {
+			return fmt.Errorf("engine.input.max_per_tick must be at least 1 and max_buffered at least max_per_tick")
+		}
+	}
+
+	zones := make(map[string]bool, len(c.Engine.Zones))
+	for _, z := range c.Engine.Zones {
+		if z.Name == "" || zones[z.Name] {
+			return fmt.Errorf("engine.zones: every zone needs a unique name, got %q", z.Name)
+		}
+		zones[z.Name] = true
+
+		if len(z.Points) == 0 && (z.MaxX <= z.MinX || z.MaxY <= z.MinY) {
+			return fmt.Errorf("engine.zones.%s: max_x and max_y must exceed min_x and min_y", z.Name)
+		}
+		if len(z.Points) > 0 && len(z.Points) < 3 {
+			return fmt.Errorf("engine.zones.%s: a polygon needs at least 3 points", z.Name)
+		}
+		if z.SpeedMultiplier < 0 {
+			return fmt.Errorf("engine.zones.%s: speed_multiplier cannot be negative", z.Name)
+		}
+	}
+
+	if err := c.Engine.Spawn.validate(zones); err != nil {
+		return err
+	}
+
+	if c.Engine.Limits.SoftCapPercent < 1 || c.Engine.Limits.SoftCapPercent > 100 {
+		return fmt.Errorf("engine.limits.soft_cap_percent must be between 1-100, got %d", c.Engine.Limits.SoftCapPercent)
+	}
+	if c.Engine.Limits.PerClient < 0 {
+		return fmt.Errorf("engine.limits.per_client cannot be negative, got %d", c.Engine.Limits.PerClient)
+	}
+	for entityType, limit := range c.Engine.Limits.PerType {
+		if limit < 1 {
+			return fmt.Errorf("engine.limits.per_type.%s must be at least 1, got %d", entityType, limit)
+		}
+	}
+
+	if c.Reload.WatchIntervalMs < 0 {
+		return fmt.Errorf("reload.watch_interval_ms cannot be negative, got %d", c.Reload.WatchIntervalMs)
+	}
+
+	if c.Engine.InterpolationDelayMs < 0 || c.Engine.HistoryTicks < 0 {
+		return fmt.Errorf("engine.interpolation_delay_ms and engine.history_ticks cannot be negative")
+	}
+
+	for name, p := range c.Engine.Projectiles {
+		if p.Speed <= 0 || p.TTLMs <= 0 || p.HitRadius <= 0 {
+			return fmt.Errorf("engine.projectiles.%s: speed, ttl_ms and hit_radius must be positive", name)
+		}
+		if p.Damage < 0 || p.CooldownMs < 0 {
+			return fmt.Errorf("engine.projectiles.%s: damage and cooldown_ms cannot be negative", name)
+		}
+	}
+
+	if c.Engine.PossessRadius < 0 {
+		return fmt.Errorf("engine.possess_radius cannot be negative, got %f", c.Engine.PossessRadius)
+	}
+
+	if c.Engine.SnapshotInterval < 0 {
+		return fmt.Errorf("engine.snapshot_interval cannot be negative, got %d", c.Engine.SnapshotInterval)
+	}
+
 	if c.Gateway.BindAddr == "" {
 		return fmt.Errorf("gateway.bind_addr cannot be empty")
 	}
 
+	if c.Gateway.AdminBindAddr != "" && c.Gateway.AdminBindAddr == c.Gateway.BindAddr {
+		return fmt.Errorf("gateway.admin_bind_addr must differ from gateway.bind_addr")
+	}
+
 	if c.Gateway.ReadBufferSize <= 0 {
 		return fmt.Errorf("gateway.read_buffer_size must be positive, got %d", c.Gateway.ReadBufferSize)
 	}
@@ -107,9 +571,175 @@ func (c *Config) validate() error {
 		return fmt.Errorf("gateway.write_buffer_size must be positive, got %d", c.Gateway.WriteBufferSize)
 	}
 
+	if c.Outbox.BatchSize <= 0 {
+		return fmt.Errorf("outbox.batch_size must be positive, got %d", c.Outbox.BatchSize)
+	}
+
+	if c.Outbox.PollIntervalMs <= 0 || c.Outbox.LeaseMs <= 0 {
+		return fmt.Errorf("outbox.poll_interval_ms and outbox.lease_ms must be positive")
+	}
+
+	if c.Outbox.MaxAttempts < 1 {
+		return fmt.Errorf("outbox.max_attempts must be at least 1, got %d", c.Outbox.MaxAttempts)
+	}
+
+	if c.Outbox.BaseBackoffMs <= 0 || c.Outbox.MaxBackoffMs < c.Outbox.BaseBackoffMs {
+		return fmt.Errorf("outbox.base_backoff_ms must be positive and not exceed max_backoff_ms")
+	}
+
+	if c.Outbox.MaxPendingEvents <= 0 {
+		return fmt.Errorf("outbox.max_pending_events must be positive, got %d", c.Outbox.MaxPendingEvents)
+	}
+
+	if c.Outbox.FlushIntervalMs <= 0 {
+		return fmt.Errorf("outbox.flush_interval_ms must be positive, got %d", c.Outbox.FlushIntervalMs)
+	}
+
+	for i, sink := range c.Outbox.Sinks {
+		switch sink.Type {
+		case "file":
+			if sink.Path == "" {
+				return fmt.Errorf("outbox.sinks[%d]: file sink requires path", i)
+			}
+		case "nats", "webhook":
+			if sink.URL == "" {
+				return fmt.Errorf("outbox.sinks[%d]: %s sink requires url", i, sink.Type)
+			}
+		case "kafka":
+			if sink.URL == "" || sink.Topic == "" {
+				return fmt.Errorf("outbox.sinks[%d]: kafka sink requires url and topic", i)
+			}
+		default:
+			return fmt.Errorf("outbox.sinks[%d]: unknown sink type %q", i, sink.Type)
+		}
+	}
+
+	if c.Gateway.DrainTimeout < 0 {
+		return fmt.Errorf("gateway.drain_timeout cannot be negative, got %d", c.Gateway.DrainTimeout)
+	}
+
+	if c.Sharding.Enabled {
+		if c.Sharding.Columns < 1 || c.Sharding.Rows < 1 || c.Sharding.Columns*c.Sharding.Rows > 256 {
+			return fmt.Errorf("sharding.columns x sharding.rows must be between 1 and 256 regions")
+		}
+		if c.Sharding.Region == "" || c.Sharding.AdvertiseAddr == "" {
+			return fmt.Errorf("sharding.region and sharding.advertise_addr are required when sharding is enabled")
+		}
+		if c.Sharding.GhostMargin < 0 || c.Sharding.GhostInterval < 1 || c.Sharding.LeaseMs < 300 {
+			return fmt.Errorf("sharding.ghost_margin must be >= 0, ghost_interval >= 1 and lease_ms >= 300")
+		}
+	}
+
+	if c.Worlds.Default == "" {
+		return fmt.Errorf("worlds.default cannot be empty")
+	}
+
+	if c.Worlds.MaxWorlds < 1 || c.Worlds.IdleTimeoutSec < 0 {
+		return fmt.Errorf("worlds.max_worlds must be at least 1 and idle_timeout_sec >= 0")
+	}
+
+	for name, tmpl := range c.Worlds.Templates {
+		if tmpl.TickRateMs != 0 && (tmpl.TickRateMs < 10 || tmpl.TickRateMs > 100) {
+			return fmt.Errorf("worlds.templates.%s: tick_rate_ms must be between 10-100ms, got %d", name, tmpl.TickRateMs)
+		}
+		if tmpl.MaxEntities < 0 || tmpl.MaxEntities > 1000 || tmpl.AOIRadius < 0 {
+			return fmt.Errorf("worlds.templates.%s: max_entities must be between 0-1000 and aoi_radius >= 0", name)
+		}
+		if b := tmpl.WorldBounds; b != nil && (b.MinX >= b.MaxX || b.MinY >= b.MaxY || b.MinZ > b.MaxZ) {
+			return fmt.Errorf("worlds.templates.%s: world_bounds min must be below max", name)
+		}
+	}
+
+	if c.Lobby.Enabled {
+		if c.Lobby.PollIntervalMs < 50 || c.Lobby.TicketTTLSec < 1 {
+			return fmt.Errorf("lobby.poll_interval_ms must be at least 50 and ticket_ttl_sec at least 1")
+		}
+		for name, rule := range c.Lobby.Queues {
+			tmpl, ok := c.Worlds.Templates[rule.Template]
+			if !ok {
+				return fmt.Errorf("lobby.queues.%s: unknown world template %q", name, rule.Template)
+			}
+			maxEntities := tmpl.Apply(c.Engine).MaxEntities
+			if rule.MinPlayers < 1 || rule.MaxPlayers < rule.MinPlayers || rule.MaxPlayers > maxEntities {
+				return fmt.Errorf("lobby.queues.%s: need 1 <= min_players <= max_players <= %d", name, maxEntities)
+			}
+			if rule.MaxSkillSpread < 0 || rule.SkillSpreadPerSec < 0 || rule.MaxWaitSec < 1 {
+				return fmt.Errorf("lobby.queues.%s: skill spreads must be >= 0 and max_wait_sec >= 1", name)
+			}
+		}
+	}
+
+	if c.Channels.MaxPayloadBytes <= 0 || c.Channels.MaxSubscriptions <= 0 {
+		return fmt.Errorf("channels.max_payload_bytes and max_subscriptions must be positive")
+	}
+	for name, rule := range c.Channels.Rules {
+		if name == "" || strings.Contains(name, ":") {
+			return fmt.Errorf("channels.rules: invalid channel name %q", name)
+		}
+		switch rule.Kind {
+		case "global", "proximity":
+			if rule.Subscribe == "members" || rule.Publish == "members" {
+				return fmt.Errorf("channels.rules.%s: members ACL needs kind team", name)
+			}
+		case "team":
+		default:
+			return fmt.Errorf("channels.rules.%s: kind must be global, team or proximity, got %q", name, rule.Kind)
+		}
+		for _, acl := range []string{rule.Subscribe, rule.Publish} {
+			if acl != "all" && acl != "members" && acl != "none" {
+				return fmt.Errorf("channels.rules.%s: ACL must be all, members or none, got %q", name, acl)
+			}
+		}
+		if rule.RatePerSec < 0 || (rule.RatePerSec > 0 && rule.Burst < 1) {
+			return fmt.Errorf("channels.rules.%s: rate_per_sec must be >= 0 with burst >= 1", name)
+		}
+	}
+
+	if c.Chat.Enabled {
+		if c.Chat.Radius <= 0 || c.Chat.MaxLength <= 0 {
+			return fmt.Errorf("chat.radius and chat.max_length must be positive")
+		}
+		if c.Chat.BannedWordAction != "mask" && c.Chat.BannedWordAction != "reject" {
+			return fmt.Errorf("chat.banned_word_action must be mask or reject, got %q", c.Chat.BannedWordAction)
+		}
+		if c.Chat.DuplicateWindowSec < 0 || c.Chat.AuditBufferSize <= 0 || c.Chat.HistoryLimit <= 0 {
+			return fmt.Errorf("chat.duplicate_window_sec must be >= 0, audit_buffer_size and history_limit positive")
+		}
+	}
+
+	if c.Redis.PresenceTimeoutSec <= 0 {
+		return fmt.Errorf("redis.presence_timeout_sec must be positive, got %d", c.Redis.PresenceTimeoutSec)
+	}
+
+	if c.Redis.InstanceTimeoutSec < 3 {
+		return fmt.Errorf("redis.instance_timeout_sec must be at least 3, got %d", c.Redis.InstanceTimeoutSec)
+	}
+
 	return nil
 }
 
+func validAttributeDefault(kind, value string) bool {
+	var err error
+	switch kind {
+	case "string":
+	case "int":
+		if value != "" {
+			_, err = strconv.ParseInt(value, 10, 64)
+		}
+	case "float":
+		if value != "" {
+			_, err = strconv.ParseFloat(value, 64)
+		}
+	case "bool":
+		if value != "" {
+			_, err = strconv.ParseBool(value)
+		}
+	default:
+		return false
+	}
+	return err == nil
+}
+
 func Default() *Config {
 	return &Config{
 		Engine: EngineConfig{
@@ -120,11 +750,40 @@ func Default() *Config {
 				MinY: -1000,
 				MaxX: 1000,
 				MaxY: 1000,
+				MinZ: 0,
+				MaxZ: 500,
 			},
 			MaxSpeed:        5.0, // units per tick
 			AOIRadius:       200.0,
 			QuadtreeDepth:   8,
 			QuadtreeCapacity: 8,
+			ResumeGraceSec:   60,
+			SnapshotInterval: 200, // 5s at 40Hz
+			PossessRadius:    50,
+			SpatialIndex:     "quadtree",
+			Gravity:          0.2,
+			JumpSpeed:        3.0,
+			InterpolationDelayMs: 100, // 4 ticks at 40Hz
+			HistoryTicks:         40,  // 1s at 40Hz
+			Input: InputConfig{
+				MoveSpeed:        2.5,
+				SprintMultiplier: 2,
+				MaxPerTick:       2,
+				MaxBuffered:      32,
+			},
+			Spawn: SpawnConfig{
+				Policy:    "farthest",
+				Clearance: 2.0,
+				Attempts:  16,
+			},
+			Limits: LimitsConfig{
+				SoftCapPercent: 90,
+			},
+			Scripting: ScriptingConfig{
+				Dir:              "scripts",
+				ReloadIntervalMs: 1000,
+				HookTimeoutMs:    5,
+			},
 		},
 		Gateway: GatewayConfig{
 			BindAddr:          ":8080",
@@ -135,12 +794,16 @@ func Default() *Config {
 			WriteWait:         10,  // seconds
 			MaxMessageSize:    512, // bytes
 			EnableCompression: true,
+			DrainTimeout:      20, // seconds
+			AdminBindAddr:     "127.0.0.1:9101",
 		},
 		Redis: RedisConfig{
 			Addr:     "localhost:6379",
 			Password: "",
 			DB:       0,
 			PoolSize: 10,
+			PresenceTimeoutSec: 30,
+			InstanceTimeoutSec: 15,
 		},
 		Postgres: PostgresConfig{
 			Host:            "localhost",
@@ -151,6 +814,53 @@ func Default() *Config {
 			MaxOpenConns:    25,
 			MaxIdleConns:    5,
 			ConnMaxLifetime: 300, // seconds
+			SnapshotRetention: 3600, // seconds
+		},
+		Outbox: OutboxConfig{
+			BatchSize:      100,
+			PollIntervalMs: 1000,
+			LeaseMs:        30000,
+			MaxAttempts:    10,
+			BaseBackoffMs:  1000,
+			MaxBackoffMs:   300000, // 5 minutes
+			MaxPendingEvents: 100000,
+			FlushIntervalMs:  1000,
+		},
+		Metrics: MetricsConfig{
+			BindAddr: ":9100",
+		},
+		Reload: ReloadConfig{
+			WatchIntervalMs: 2000,
+		},
+		Sharding: ShardingConfig{
+			Enabled:       false,
+			Columns:       2,
+			Rows:          1,
+			GhostInterval: 4,
+			LeaseMs:       5000,
+		},
+		Worlds: WorldsConfig{
+			Default:        "default",
+			MaxWorlds:      64,
+			IdleTimeoutSec: 30,
+		},
+		Lobby: LobbyConfig{
+			Enabled:        false,
+			PollIntervalMs: 500,
+			TicketTTLSec:   30,
+		},
+		Channels: ChannelsConfig{
+			MaxPayloadBytes:  1024,
+			MaxSubscriptions: 16,
+		},
+		Chat: ChatConfig{
+			Enabled:            true,
+			Radius:             50.0,
+			MaxLength:          280,
+			BannedWordAction:   "mask",
+			DuplicateWindowSec: 5,
+			AuditBufferSize:    1024,
+			HistoryLimit:       200,
 		},
 	}
 }
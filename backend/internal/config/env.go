@@ -0,0 +1,85 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// applyEnv overrides settings from environment variables, which win over the
+// config file. Connection settings use the names docker-compose sets; engine
+// tunables are prefixed with AETHER_.
+func (c *Config) applyEnv() error {
+	envString("REDIS_ADDR", &c.Redis.Addr)
+	envString("REDIS_PASSWORD", &c.Redis.Password)
+	envString("POSTGRES_HOST", &c.Postgres.Host)
+	envString("POSTGRES_USER", &c.Postgres.User)
+	envString("POSTGRES_PASSWORD", &c.Postgres.Password)
+	envString("POSTGRES_DBNAME", &c.Postgres.DBName)
+	envString("AETHER_BIND_ADDR", &c.Gateway.BindAddr)
+	envString("AETHER_ADMIN_BIND_ADDR", &c.Gateway.AdminBindAddr)
+
+	ints := []struct {
+		name  string
+		value *int
+	}{
+		{"REDIS_DB", &c.Redis.DB},
+		{"POSTGRES_PORT", &c.Postgres.Port},
+		{"AETHER_TICK_RATE_MS", &c.Engine.TickRateMs},
+		{"AETHER_MAX_ENTITIES", &c.Engine.MaxEntities},
+	}
+	for _, v := range ints {
+		if err := envInt(v.name, v.value); err != nil {
+			return err
+		}
+	}
+
+	floats := []struct {
+		name  string
+		value *float64
+	}{
+		{"AETHER_MAX_SPEED", &c.Engine.MaxSpeed},
+		{"AETHER_AOI_RADIUS", &c.Engine.AOIRadius},
+	}
+	for _, v := range floats {
+		if err := envFloat(v.name, v.value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func envString(name string, value *string) {
+	if s, ok := os.LookupEnv(name); ok {
+		*value = s
+	}
+}
+
+func envInt(name string, value *int) error {
+	s, ok := os.LookupEnv(name)
+	if !ok {
+		return nil
+	}
+
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", name, err)
+	}
+	*value = n
+	return nil
+}
+
+func envFloat(name string, value *float64) error {
+	s, ok := os.LookupEnv(name)
+	if !ok {
+		return nil
+	}
+
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", name, err)
+	}
+	*value = f
+	return nil
+}
@@ -0,0 +1,221 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ReloadConfig controls reloading the configuration while the server runs.
+type ReloadConfig struct {
+	WatchIntervalMs int `yaml:"watch_interval_ms"` // How often the config file is checked for changes, 0 reloads only on SIGHUP
+}
+
+// Reloaded returns e with the settings that can change while the engine
+// runs taken from next. Everything else keeps its value until a restart.
+func (e EngineConfig) Reloaded(next EngineConfig) EngineConfig {
+	cfg := e
+	cfg.TickRateMs = next.TickRateMs
+	cfg.MaxEntities = next.MaxEntities
+	cfg.MaxSpeed = next.MaxSpeed
+	cfg.AOIRadius = next.AOIRadius
+	cfg.PossessRadius = next.PossessRadius
+	cfg.ResumeGraceSec = next.ResumeGraceSec
+	cfg.InterpolationDelayMs = next.InterpolationDelayMs
+	cfg.Spawn = next.Spawn
+	cfg.Limits = next.Limits
+	return cfg
+}
+
+// RestartRequired lists the settings, by YAML path, that differ between old
+// and next but only take effect after a restart.
+func RestartRequired(old, next *Config) []string {
+	var changed []string
+
+	oldV, nextV := reflect.ValueOf(*old), reflect.ValueOf(*next)
+	for i := 0; i < oldV.NumField(); i++ {
+		field := oldV.Type().Field(i)
+		if field.Name == "Engine" {
+			continue
+		}
+		if !reflect.DeepEqual(oldV.Field(i).Interface(), nextV.Field(i).Interface()) {
+			changed = append(changed, yamlName(field))
+		}
+	}
+
+	merged, nextEngine := reflect.ValueOf(old.Engine.Reloaded(next.Engine)), reflect.ValueOf(next.Engine)
+	for i := 0; i < merged.NumField(); i++ {
+		if !reflect.DeepEqual(merged.Field(i).Interface(), nextEngine.Field(i).Interface()) {
+			changed = append(changed, "engine."+yamlName(merged.Type().Field(i)))
+		}
+	}
+
+	return changed
+}
+
+func yamlName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+// Change is a configuration that passed validation and replaced Old.
+type Change struct {
+	Old             *Config
+	New             *Config
+	RestartRequired []string // changed settings that are not applied until a restart
+}
+
+// Watcher reloads the configuration file when it, or one of its zone files,
+// changes on disk, and whenever the process receives SIGHUP. The new
+// configuration is validated first; if it is invalid the error is logged and
+// the current one stays in force.
+type Watcher struct {
+	path        string
+	logger      *zap.Logger
+	mu          sync.Mutex
+	current     *Config
+	version     string // fingerprint of the loaded files
+	subscribers []func(Change)
+}
+
+// NewWatcher watches path, which cfg was loaded from.
+func NewWatcher(path string, cfg *Config, logger *zap.Logger) *Watcher {
+	w := &Watcher{
+		path:    path,
+		logger:  logger,
+		current: cfg,
+	}
+	w.version, _ = w.fingerprint(cfg)
+	return w
+}
+
+// Subscribe calls fn with every accepted change. Subscribers run on the
+// watcher's goroutine, one after another, and must not block.
+func (w *Watcher) Subscribe(fn func(Change)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.subscribers = append(w.subscribers, fn)
+}
+
+// Current returns the configuration in force.
+func (w *Watcher) Current() *Config {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.current
+}
+
+// Start reloads on SIGHUP and, with WatchIntervalMs set, when the files
+// change, until ctx is done.
+func (w *Watcher) Start(ctx context.Context) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+
+	go func() {
+		defer signal.Stop(hup)
+
+		var poll <-chan time.Time
+		if interval := w.Current().Reload.WatchIntervalMs; interval > 0 {
+			ticker := time.NewTicker(time.Duration(interval) * time.Millisecond)
+			defer ticker.Stop()
+			poll = ticker.C
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-hup:
+				w.logger.Info("SIGHUP received, reloading configuration", zap.String("path", w.path))
+				w.reload(true)
+			case <-poll:
+				w.reload(false)
+			}
+		}
+	}()
+}
+
+// reload loads the files if they changed, or regardless with force.
+func (w *Watcher) reload(force bool) {
+	current := w.Current()
+
+	version, err := w.fingerprint(current)
+	if err != nil {
+		w.logger.Warn("Failed to check configuration file", zap.String("path", w.path), zap.Error(err))
+		return
+	}
+
+	w.mu.Lock()
+	unchanged := version == w.version
+	w.version = version // a broken file is reported once, not on every poll
+	w.mu.Unlock()
+	if unchanged && !force {
+		return
+	}
+
+	next, err := Load(w.path)
+	if err != nil {
+		w.logger.Error("Configuration reload failed, keeping current configuration", zap.Error(err))
+		return
+	}
+	if reflect.DeepEqual(current, next) {
+		w.logger.Info("Configuration unchanged", zap.String("path", w.path))
+		return
+	}
+
+	change := Change{
+		Old:             current,
+		New:             next,
+		RestartRequired: RestartRequired(current, next),
+	}
+
+	w.mu.Lock()
+	w.current = next
+	subscribers := make([]func(Change), len(w.subscribers))
+	copy(subscribers, w.subscribers)
+	w.mu.Unlock()
+
+	if len(change.RestartRequired) > 0 {
+		w.logger.Warn("Changed settings take effect after a restart", zap.Strings("settings", change.RestartRequired))
+	}
+	w.logger.Info("Configuration reloaded", zap.String("path", w.path))
+
+	for _, fn := range subscribers {
+		fn(change)
+	}
+}
+
+// fingerprint identifies the config file and cfg's zone files by size and
+// modification time.
+func (w *Watcher) fingerprint(cfg *Config) (string, error) {
+	files := []string{w.path}
+	for _, name := range cfg.Engine.ZoneFiles {
+		if !filepath.IsAbs(name) {
+			name = filepath.Join(filepath.Dir(w.path), name)
+		}
+		files = append(files, name)
+	}
+
+	var b strings.Builder
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&b, "%s:%d:%d;", file, info.Size(), info.ModTime().UnixNano())
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,118 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+func TestRestartRequired(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(cfg *Config)
+		want   string
+	}{
+		{"nothing", func(cfg *Config) {}, ""},
+		{"reloadable engine settings", func(cfg *Config) {
+			cfg.Engine.TickRateMs = 50
+			cfg.Engine.AOIRadius = 120
+			cfg.Engine.Limits.PerClient = 3
+		}, ""},
+		{"engine setting needing a restart", func(cfg *Config) { cfg.Engine.SpatialIndex = "octree" }, "engine.spatial_index"},
+		{"other sections", func(cfg *Config) {
+			cfg.Redis.Addr = "redis:6380"
+			cfg.Reload.WatchIntervalMs = 500
+		}, "redis,reload"},
+		{"both", func(cfg *Config) {
+			cfg.Engine.MaxSpeed = 8
+			cfg.Engine.WorldBounds.MaxX = 2000
+			cfg.Gateway.BindAddr = ":9000"
+		}, "gateway,engine.world_bounds"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			old, next := Default(), Default()
+			tt.change(next)
+
+			if got := strings.Join(RestartRequired(old, next), ","); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestEngineConfig_Reloaded(t *testing.T) {
+	old, next := Default().Engine, Default().Engine
+	next.TickRateMs = 50
+	next.SpatialIndex = "octree"
+
+	merged := old.Reloaded(next)
+	if merged.TickRateMs != 50 {
+		t.Errorf("expected the tick rate to reload, got %d", merged.TickRateMs)
+	}
+	if merged.SpatialIndex != old.SpatialIndex {
+		t.Errorf("expected the spatial index to wait for a restart, got %q", merged.SpatialIndex)
+	}
+}
+
+func writeConfig(t *testing.T, path string, cfg *Config) {
+	t.Helper()
+
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("marshal config: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+}
+
+func TestWatcher_Reload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfig(t, path, Default())
+	initial, err := Load(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+
+	w := NewWatcher(path, initial, zap.NewNop())
+	var changes []Change
+	w.Subscribe(func(c Change) { changes = append(changes, c) })
+
+	// An unchanged file is not reported, even when forced
+	w.reload(true)
+	if len(changes) != 0 {
+		t.Fatalf("expected no change for an identical file, got %d", len(changes))
+	}
+
+	// An invalid file keeps the current configuration
+	if err := os.WriteFile(path, []byte("engine: ["), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	w.reload(true)
+	if len(changes) != 0 || w.Current() != initial {
+		t.Fatal("expected an invalid file to keep the current configuration")
+	}
+
+	next := Default()
+	next.Engine.TickRateMs = 50
+	next.Gateway.BindAddr = ":9000"
+	writeConfig(t, path, next)
+	w.reload(true)
+
+	if len(changes) != 1 {
+		t.Fatalf("expected 1 change, got %d", len(changes))
+	}
+	change := changes[0]
+	if change.Old != initial || change.New != w.Current() || change.New.Engine.TickRateMs != 50 {
+		t.Errorf("expected the change to replace the initial configuration, got %+v", change)
+	}
+	if got := strings.Join(change.RestartRequired, ","); got != "gateway" {
+		t.Errorf("expected only the gateway to need a restart, got %q", got)
+	}
+}
@@ -8,7 +8,7 @@ import (
 )
 
 type AOIManager struct {
-	quadtree   *spatial.Quadtree
+	index      spatial.Index
 	aoiRadius  float64
 	subscribers map[uint32]map[uint32]struct{} // entity_id -> set of subscriber entity_ids
 	mu         sync.RWMutex
@@ -18,25 +18,34 @@ type AOIEvent struct {
 	Type      string    // "enter", "exit", "move"
 	EntityID  uint32
 	OtherID   uint32
-	Position  entity.Vector2
+	Position  entity.Vector3
 }
 
-func NewAOIManager(quadtree *spatial.Quadtree, aoiRadius float64) *AOIManager {
+func NewAOIManager(index spatial.Index, aoiRadius float64) *AOIManager {
 	return &AOIManager{
-		quadtree:    quadtree,
+		index:       index,
 		aoiRadius:   aoiRadius,
 		subscribers: make(map[uint32]map[uint32]struct{}),
 	}
 }
 
-func (am *AOIManager) UpdateEntity(entityID uint32, position entity.Vector2) []AOIEvent {
+// SetRadius changes the AOI radius. Subscriptions catch up as each entity
+// is next updated, raising enter and exit events for the difference.
+func (am *AOIManager) SetRadius(radius float64) {
+	am.mu.Lock()
+	defer am.mu.Unlock()
+
+	am.aoiRadius = radius
+}
+
+func (am *AOIManager) UpdateEntity(entityID uint32, position entity.Vector3) []AOIEvent {
 	am.mu.Lock()
 	defer am.mu.Unlock()
 
 	events := make([]AOIEvent, 0)
 
 	// Get current nearby entities
-	nearby := am.quadtree.QueryRadius(position, am.aoiRadius)
+	nearby := am.index.QueryRadius(position, am.aoiRadius)
 	
 	// Get current subscribers
 	currentSubscribers, exists := am.subscribers[entityID]
@@ -122,8 +131,8 @@ func (am *AOIManager) GetNearbyEntities(entityID uint32) []uint32 {
 	return nearby
 }
 
-func (am *AOIManager) GetEntitiesInRadius(center entity.Vector2, radius float64) []*entity.Entity {
-	return am.quadtree.QueryRadius(center, radius)
+func (am *AOIManager) GetEntitiesInRadius(center entity.Vector3, radius float64) []*entity.Entity {
+	return am.index.QueryRadius(center, radius)
 }
 
 func (am *AOIManager) GetSubscriberCount() int {
@@ -0,0 +1,225 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/akarsh-2004/aether/internal/config"
+	"github.com/akarsh-2004/aether/internal/engine/entity"
+	"github.com/akarsh-2004/aether/proto"
+)
+
+var (
+	ErrUnknownAttribute = errors.New("unknown attribute")
+	ErrAttributeType    = errors.New("attribute value has the wrong type")
+)
+
+// attributeSchema is an entity type's EntitySchema with defaults parsed.
+type attributeSchema struct {
+	kinds       map[string]string
+	defaults    map[string]entity.Value
+	allowCustom bool
+}
+
+func compileSchemas(types map[string]config.EntitySchema) map[string]*attributeSchema {
+	schemas := make(map[string]*attributeSchema, len(types))
+	for entityType, def := range types {
+		schema := &attributeSchema{
+			kinds:       make(map[string]string, len(def.Attributes)),
+			defaults:    make(map[string]entity.Value, len(def.Attributes)),
+			allowCustom: def.AllowCustom,
+		}
+		for _, attr := range def.Attributes {
+			schema.kinds[attr.Name] = attr.Kind
+			// Defaults were checked when the config was loaded
+			schema.defaults[attr.Name], _ = parseValue(attr.Kind, attr.Default)
+		}
+		schemas[entityType] = schema
+	}
+	return schemas
+}
+
+func parseValue(kind, text string) (entity.Value, error) {
+	value := entity.Value{Kind: kind}
+	if text == "" {
+		return value, nil
+	}
+
+	var err error
+	switch kind {
+	case entity.KindInt:
+		value.Int, err = strconv.ParseInt(text, 10, 64)
+	case entity.KindFloat:
+		value.Float, err = strconv.ParseFloat(text, 64)
+	case entity.KindBool:
+		value.Bool, err = strconv.ParseBool(text)
+	case entity.KindString:
+		value.String = text
+	default:
+		err = fmt.Errorf("unknown attribute kind %q", kind)
+	}
+	return value, err
+}
+
+// ToValue converts a Go value into an attribute value of kind, or of the
+// value's own kind when kind is empty.
+func ToValue(kind string, v interface{}) (entity.Value, error) {
+	var value entity.Value
+	switch x := v.(type) {
+	case int:
+		value = entity.Value{Kind: entity.KindInt, Int: int64(x)}
+	case int32:
+		value = entity.Value{Kind: entity.KindInt, Int: int64(x)}
+	case int64:
+		value = entity.Value{Kind: entity.KindInt, Int: x}
+	case uint32:
+		value = entity.Value{Kind: entity.KindInt, Int: int64(x)}
+	case float32:
+		value = entity.Value{Kind: entity.KindFloat, Float: float64(x)}
+	case float64:
+		value = entity.Value{Kind: entity.KindFloat, Float: x}
+	case string:
+		value = entity.Value{Kind: entity.KindString, String: x}
+	case bool:
+		value = entity.Value{Kind: entity.KindBool, Bool: x}
+	case entity.Value:
+		value = x
+	default:
+		return entity.Value{}, fmt.Errorf("%w: %T", ErrAttributeType, v)
+	}
+
+	if kind == "" || kind == value.Kind {
+		return value, nil
+	}
+	// Whole numbers are accepted for float attributes
+	if kind == entity.KindFloat && value.Kind == entity.KindInt {
+		return entity.Value{Kind: entity.KindFloat, Float: float64(value.Int)}, nil
+	}
+	return entity.Value{}, fmt.Errorf("%w: want %s, got %s", ErrAttributeType, kind, value.Kind)
+}
+
+// applyDefaults gives a new entity its type's default attributes.
+func (se *SpatialEngine) applyDefaults(ent *entity.Entity) {
+	schema := se.schemas[ent.Type]
+	if schema == nil {
+		return
+	}
+	for name, value := range schema.defaults {
+		ent.SetAttribute(name, value)
+	}
+}
+
+func (se *SpatialEngine) checkAttributeLocked(ent *entity.Entity, name string, v interface{}) (entity.Value, error) {
+	schema := se.schemas[ent.Type]
+	if schema == nil {
+		return entity.Value{}, fmt.Errorf("%w: %s has no attributes", ErrUnknownAttribute, ent.Type)
+	}
+
+	kind, declared := schema.kinds[name]
+	if !declared && !schema.allowCustom {
+		return entity.Value{}, fmt.Errorf("%w: %s", ErrUnknownAttribute, name)
+	}
+	return ToValue(kind, v)
+}
+
+// SetAttribute changes one attribute of a local entity. The change reaches
+// nearby clients on the next tick.
+func (se *SpatialEngine) SetAttribute(entityID uint32, name string, v interface{}) error {
+	return se.SetAttributes(entityID, map[string]interface{}{name: v})
+}
+
+// SetAttributes changes several attributes at once. Nothing is changed if any
+// of them is invalid.
+func (se *SpatialEngine) SetAttributes(entityID uint32, values map[string]interface{}) error {
+	se.mu.Lock()
+	defer se.mu.Unlock()
+
+	return se.setAttributesLocked(entityID, values)
+}
+
+func (se *SpatialEngine) setAttributesLocked(entityID uint32, values map[string]interface{}) error {
+	ent, exists := se.entityManager.GetEntity(entityID)
+	if !exists || ent.Ghost {
+		return ErrEntityNotFound
+	}
+
+	checked := make(map[string]entity.Value, len(values))
+	for name, v := range values {
+		value, err := se.checkAttributeLocked(ent, name, v)
+		if err != nil {
+			return err
+		}
+		checked[name] = value
+	}
+
+	for name, value := range checked {
+		ent.SetAttribute(name, value)
+	}
+	return nil
+}
+
+// DeleteAttribute removes a custom attribute, or resets a declared one to its
+// default.
+func (se *SpatialEngine) DeleteAttribute(entityID uint32, name string) error {
+	se.mu.Lock()
+	defer se.mu.Unlock()
+
+	ent, exists := se.entityManager.GetEntity(entityID)
+	if !exists || ent.Ghost {
+		return ErrEntityNotFound
+	}
+
+	if schema := se.schemas[ent.Type]; schema != nil {
+		if value, declared := schema.defaults[name]; declared {
+			ent.SetAttribute(name, value)
+			return nil
+		}
+	}
+	ent.DeleteAttribute(name)
+	return nil
+}
+
+// Attributes returns a copy of an entity's attributes.
+func (se *SpatialEngine) Attributes(entityID uint32) (map[string]entity.Value, bool) {
+	se.mu.RLock()
+	defer se.mu.RUnlock()
+
+	ent, exists := se.entityManager.GetEntity(entityID)
+	if !exists {
+		return nil, false
+	}
+	return ent.Copy().Attributes, true
+}
+
+// attributesToProto returns every attribute of ent when full is set, or only
+// those changed since the last tick.
+func attributesToProto(ent *entity.Entity, full bool) []*proto.Attribute {
+	var names []string
+	if full {
+		for name := range ent.Attributes {
+			names = append(names, name)
+		}
+	} else {
+		names = ent.DirtyAttributes()
+	}
+
+	attrs := make([]*proto.Attribute, 0, len(names))
+	for _, name := range names {
+		value, exists := ent.Attributes[name]
+		if !exists {
+			attrs = append(attrs, &proto.Attribute{Name: name, Removed: true})
+			continue
+		}
+
+		attrs = append(attrs, &proto.Attribute{
+			Name:        name,
+			Kind:        value.Kind,
+			IntValue:    value.Int,
+			FloatValue:  value.Float,
+			StringValue: value.String,
+			BoolValue:   value.Bool,
+		})
+	}
+	return attrs
+}
@@ -152,6 +152,8 @@ func (mv *MovementValidator) limitSpeed(delta *proto.MovementDelta, maxSpeed flo
 		Sequence:  delta.Sequence,
 		DeltaX:    delta.DeltaX * scale,
 		DeltaY:    delta.DeltaY * scale,
+		DeltaZ:    delta.DeltaZ,
+		Yaw:       delta.Yaw,
 		Timestamp: delta.Timestamp,
 	}
 }
@@ -188,6 +190,8 @@ func (mv *MovementValidator) clampToBounds(ent *entity.Entity, delta *proto.Move
 		Sequence:  delta.Sequence,
 		DeltaX:    clampedDeltaX,
 		DeltaY:    clampedDeltaY,
+		DeltaZ:    delta.DeltaZ,
+		Yaw:       delta.Yaw,
 		Timestamp: delta.Timestamp,
 	}
 }
@@ -239,12 +243,11 @@ func (mv *MovementValidator) hasSpeedAnomaly(entityID uint32) bool {
 
 func (sr *StateReconciler) ShouldReconcile(ent *entity.Entity, clientState *proto.EntityState) bool {
 	// Calculate position difference
-	serverX := ent.Position.X
-	serverY := ent.Position.Y
 	clientX := float64(clientState.X)
 	clientY := float64(clientState.Y)
+	clientZ := float64(clientState.Z)
 
-	distance := math.Sqrt((serverX-clientX)*(serverX-clientX) + (serverY-clientY)*(serverY-clientY))
+	distance := math.Sqrt(ent.Position.Distance3D(entity.Vector3{X: clientX, Y: clientY, Z: clientZ}))
 
 	// Define reconciliation threshold
 	reconciliationThreshold := sr.config.MaxSpeed * 2 // Allow some prediction error
@@ -259,6 +262,9 @@ func (sr *StateReconciler) GenerateCorrection(ent *entity.Entity, ackSequence ui
 		CorrectY:         float32(ent.Position.Y),
 		CorrectVelocityX: float32(ent.Velocity.X),
 		CorrectVelocityY: float32(ent.Velocity.Y),
+		CorrectZ:         float32(ent.Position.Z),
+		CorrectVelocityZ: float32(ent.Velocity.Z),
+		CorrectYaw:       float32(ent.Yaw),
 		AckSequence:      ackSequence,
 	}
 
@@ -0,0 +1,179 @@
+package engine
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/akarsh-2004/aether/internal/config"
+	"github.com/akarsh-2004/aether/internal/engine/entity"
+	"github.com/akarsh-2004/aether/proto"
+	"go.uber.org/zap"
+)
+
+// startRecording starts se with a Sender that records what clientID is sent.
+func startRecording(t *testing.T, se *SpatialEngine, clientID string) <-chan *proto.Message {
+	t.Helper()
+
+	received := make(chan *proto.Message, 1024)
+	se.SetSender(func(to string, message *proto.Message) {
+		if to != clientID {
+			return
+		}
+		select {
+		case received <- message:
+		default:
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go se.Start(ctx)
+	t.Cleanup(func() {
+		cancel()
+		se.Shutdown(context.Background())
+	})
+	return received
+}
+
+// waitFor returns the first received message match accepts.
+func waitFor(t *testing.T, received <-chan *proto.Message, what string, match func(*proto.Message) bool) *proto.Message {
+	t.Helper()
+
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case message := <-received:
+			if match(message) {
+				return message
+			}
+		case <-timeout:
+			t.Fatalf("expected the client to receive %s", what)
+			return nil
+		}
+	}
+}
+
+func TestBroadcast_AttributeChangeReachesClient(t *testing.T) {
+	cfg := config.Default().Engine
+	cfg.EntityTypes = map[string]config.EntitySchema{
+		"npc": {Attributes: []config.AttributeDef{{Name: "health", Kind: "int", Default: "100"}}},
+	}
+	se := NewSpatialEngine(cfg, zap.NewNop())
+
+	if se.SpawnEntity("player", 0, 0, 0, "alice") == 0 {
+		t.Fatal("failed to spawn alice")
+	}
+	npcID := se.SpawnEntity("npc", 5, 0, 0, "")
+	if npcID == 0 {
+		t.Fatal("failed to spawn npc")
+	}
+
+	received := startRecording(t, se, "alice")
+
+	if err := se.SetAttribute(npcID, "health", 42); err != nil {
+		t.Fatalf("set attribute: %v", err)
+	}
+
+	waitFor(t, received, "the npc's new health", func(message *proto.Message) bool {
+		state := message.GetEntityState()
+		if state == nil || state.EntityId != npcID {
+			return false
+		}
+		for _, attr := range state.Attributes {
+			if attr.Name == "health" && attr.IntValue == 42 {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+func TestBroadcast_InputAckReachesOwner(t *testing.T) {
+	cfg := config.Default().Engine
+	cfg.Input.Enabled = true
+	se := NewSpatialEngine(cfg, zap.NewNop())
+
+	entityID := se.SpawnEntity("player", 0, 0, 0, "alice")
+	if entityID == 0 {
+		t.Fatal("failed to spawn alice")
+	}
+
+	received := startRecording(t, se, "alice")
+	se.ProcessInput("alice", &proto.InputCommand{EntityId: entityID, Sequence: 1, ClientTick: 7, MoveX: 1})
+
+	message := waitFor(t, received, "an input ack", func(message *proto.Message) bool {
+		return message.GetInputAck() != nil
+	})
+	ack := message.GetInputAck()
+	if ack.EntityId != entityID || ack.AckSequence != 1 || ack.ClientTick != 7 {
+		t.Errorf("expected ack of sequence 1 at client tick 7 for entity %d, got %+v", entityID, ack)
+	}
+	if ack.ServerTick == 0 {
+		t.Error("expected the ack to carry the server tick")
+	}
+}
+
+func TestBroadcast_StatesCarryTheirServerTick(t *testing.T) {
+	cfg := config.Default().Engine
+	cfg.Input.Enabled = true
+	se := NewSpatialEngine(cfg, zap.NewNop())
+
+	if se.SpawnEntity("player", 0, 0, 0, "alice") == 0 {
+		t.Fatal("failed to spawn alice")
+	}
+	bobID := se.SpawnEntity("player", 5, 0, 0, "bob")
+	if bobID == 0 {
+		t.Fatal("failed to spawn bob")
+	}
+
+	received := startRecording(t, se, "alice")
+	se.ProcessInput("bob", &proto.InputCommand{EntityId: bobID, Sequence: 1, MoveX: 1})
+
+	message := waitFor(t, received, "bob's move", func(message *proto.Message) bool {
+		state := message.GetEntityState()
+		return state != nil && state.EntityId == bobID && state.X > 5
+	})
+	state := message.GetEntityState()
+
+	if current, _, _ := se.TickTime(); state.ServerTick == 0 || state.ServerTick > current {
+		t.Fatalf("expected a server tick in [1, %d], got %d", current, state.ServerTick)
+	}
+	pos, ok := se.PositionAt(bobID, float64(state.ServerTick))
+	if !ok {
+		t.Fatalf("expected history for tick %d", state.ServerTick)
+	}
+	if math.Abs(pos.X-float64(state.X)) > 1e-3 || math.Abs(pos.Y-float64(state.Y)) > 1e-3 {
+		t.Errorf("expected bob at (%v, %v) on tick %d, state says (%v, %v)", pos.X, pos.Y, state.ServerTick, state.X, state.Y)
+	}
+}
+
+func TestBroadcast_ProjectileDespawnReasonReachesClient(t *testing.T) {
+	cfg := config.Default().Engine
+	cfg.Projectiles = map[string]config.ProjectileType{
+		"arrow": {Speed: 10, TTLMs: 1000, HitRadius: 1},
+	}
+	se := NewSpatialEngine(cfg, zap.NewNop())
+
+	aliceID := se.SpawnEntity("player", 0, 0, 0, "alice")
+	if aliceID == 0 {
+		t.Fatal("failed to spawn alice")
+	}
+	if se.SpawnEntity("npc", 25, 0, 0, "") == 0 {
+		t.Fatal("failed to spawn npc")
+	}
+
+	received := startRecording(t, se, "alice")
+	arrowID, err := se.FireProjectile("alice", aliceID, "arrow", entity.Vector3{X: 1})
+	if err != nil {
+		t.Fatalf("fire: %v", err)
+	}
+
+	message := waitFor(t, received, "the arrow's despawn", func(message *proto.Message) bool {
+		despawn := message.GetDespawn()
+		return despawn != nil && despawn.EntityId == arrowID
+	})
+	if reason := message.GetDespawn().Reason; reason != ProjectileHit {
+		t.Errorf("expected despawn reason %q, got %q", ProjectileHit, reason)
+	}
+}
@@ -0,0 +1,227 @@
+package engine
+
+import (
+	"errors"
+
+	"github.com/akarsh-2004/aether/internal/engine/entity"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrEntityNotFound   = errors.New("entity not found")
+	ErrNotController    = errors.New("entity is not controlled by this client")
+	ErrEntityControlled = errors.New("entity is controlled by another client")
+	ErrOutOfReach       = errors.New("entity is out of reach")
+)
+
+// Reasons recorded when control of an entity changes.
+const (
+	ControlPossessed   = "possessed"
+	ControlReleased    = "released"
+	ControlTransferred = "transferred"
+	ControlRevoked     = "revoked"
+)
+
+// ControlChange describes an entity changing controller. An empty ClientID
+// means nobody controls the entity any more.
+type ControlChange struct {
+	EntityID         uint32
+	PreviousClientID string
+	ClientID         string
+	Reason           string
+}
+
+// ControlListener is told about every control change. It is called with the
+// engine lock held, so implementations must not block.
+type ControlListener func(change ControlChange)
+
+// SetControlListener installs the callback for control changes. It should be
+// called before Start.
+func (se *SpatialEngine) SetControlListener(listener ControlListener) {
+	se.mu.Lock()
+	defer se.mu.Unlock()
+
+	se.onControl = listener
+}
+
+// ControlledBy returns copies of the entities clientID controls, ordered by ID.
+func (se *SpatialEngine) ControlledBy(clientID string) []entity.Entity {
+	se.mu.RLock()
+	defer se.mu.RUnlock()
+
+	owned := se.entityManager.GetEntitiesByClient(clientID)
+	entities := make([]entity.Entity, len(owned))
+	for i, ent := range owned {
+		entities[i] = ent.Copy()
+	}
+	return entities
+}
+
+// Possess gives clientID control of an entity nobody controls, such as a
+// vehicle. With PossessRadius set, one of the client's entities must be
+// within that distance.
+func (se *SpatialEngine) Possess(entityID uint32, clientID string) error {
+	se.mu.Lock()
+	defer se.mu.Unlock()
+
+	ent, exists := se.entityManager.GetEntity(entityID)
+	if !exists || ent.Ghost {
+		return ErrEntityNotFound
+	}
+	if se.isProjectile(entityID) {
+		return ErrProjectile
+	}
+
+	switch ent.ClientID {
+	case clientID:
+		return nil
+	case "":
+	default:
+		return ErrEntityControlled
+	}
+
+	if se.config.PossessRadius > 0 && !se.withinReachLocked(clientID, ent.Position) {
+		return ErrOutOfReach
+	}
+	if err := se.checkClientQuotaLocked(clientID); err != nil {
+		return err
+	}
+
+	se.setControllerLocked(ent.ID, clientID, ControlPossessed)
+	return nil
+}
+
+func (se *SpatialEngine) withinReachLocked(clientID string, target entity.Vector3) bool {
+	for _, owned := range se.entityManager.GetEntitiesByClient(clientID) {
+		// Distances are squared
+		if owned.Position.Distance3D(target) <= se.config.PossessRadius*se.config.PossessRadius {
+			return true
+		}
+	}
+	return false
+}
+
+// ReleaseControl gives up clientID's control of an entity; the entity stays
+// in the world uncontrolled.
+func (se *SpatialEngine) ReleaseControl(entityID uint32, clientID string) error {
+	se.mu.Lock()
+	defer se.mu.Unlock()
+
+	if err := se.checkControllerLocked(entityID, clientID); err != nil {
+		return err
+	}
+
+	se.setControllerLocked(entityID, "", ControlReleased)
+	return nil
+}
+
+// TransferControl hands an entity fromClientID controls to toClientID, e.g.
+// giving a pet to another player.
+func (se *SpatialEngine) TransferControl(entityID uint32, fromClientID, toClientID string) error {
+	se.mu.Lock()
+	defer se.mu.Unlock()
+
+	if err := se.checkControllerLocked(entityID, fromClientID); err != nil {
+		return err
+	}
+	if toClientID != fromClientID {
+		if err := se.checkClientQuotaLocked(toClientID); err != nil {
+			return err
+		}
+	}
+
+	se.setControllerLocked(entityID, toClientID, ControlTransferred)
+	return nil
+}
+
+// RemoveControlledEntity removes an entity only while clientID controls it,
+// so an avatar given away with TransferControl outlives its old client.
+// Returns false if the entity is gone or controlled by someone else.
+func (se *SpatialEngine) RemoveControlledEntity(entityID uint32, clientID, reason string) bool {
+	se.mu.Lock()
+	defer se.mu.Unlock()
+
+	if err := se.checkControllerLocked(entityID, clientID); err != nil {
+		return false
+	}
+	return se.removeEntityLocked(entityID, reason)
+}
+
+// RevokeControl takes an entity away from whoever controls it. It returns
+// the previous controller.
+func (se *SpatialEngine) RevokeControl(entityID uint32) (string, error) {
+	se.mu.Lock()
+	defer se.mu.Unlock()
+
+	ent, exists := se.entityManager.GetEntity(entityID)
+	if !exists || ent.Ghost {
+		return "", ErrEntityNotFound
+	}
+
+	previous := ent.ClientID
+	if previous != "" {
+		se.setControllerLocked(entityID, "", ControlRevoked)
+	}
+	return previous, nil
+}
+
+// ReleaseClient releases every entity clientID controls, e.g. when it
+// disconnects. It returns how many were released.
+func (se *SpatialEngine) ReleaseClient(clientID string) int {
+	se.mu.Lock()
+	defer se.mu.Unlock()
+
+	owned := se.entityManager.GetEntitiesByClient(clientID)
+	for _, ent := range owned {
+		se.setControllerLocked(ent.ID, "", ControlReleased)
+	}
+	return len(owned)
+}
+
+func (se *SpatialEngine) checkControllerLocked(entityID uint32, clientID string) error {
+	ent, exists := se.entityManager.GetEntity(entityID)
+	if !exists || ent.Ghost {
+		return ErrEntityNotFound
+	}
+	if ent.ClientID != clientID {
+		return ErrNotController
+	}
+	return nil
+}
+
+func (se *SpatialEngine) setControllerLocked(entityID uint32, clientID, reason string) {
+	ent, previous, exists := se.entityManager.SetOwner(entityID, clientID)
+	if !exists {
+		return
+	}
+
+	// An avatar is only its spawning client's; once given away it is an
+	// ordinary controlled entity
+	if clientID != previous {
+		ent.Avatar = false
+		ent.ResumeHash = nil
+	}
+
+	// Intents queued by the previous controller must not apply
+	delete(se.movementBuffer, entityID)
+	delete(se.inputs, entityID)
+	delete(se.orphans, entityID)
+
+	se.publishControlChanged(ent, previous, reason)
+
+	if se.onControl != nil {
+		se.onControl(ControlChange{
+			EntityID:         entityID,
+			PreviousClientID: previous,
+			ClientID:         clientID,
+			Reason:           reason,
+		})
+	}
+
+	se.logger.Info("Entity control changed",
+		zap.Uint32("entity_id", entityID),
+		zap.String("prev_client_id", previous),
+		zap.String("client_id", clientID),
+		zap.String("reason", reason),
+	)
+}
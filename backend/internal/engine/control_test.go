@@ -0,0 +1,119 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/akarsh-2004/aether/internal/config"
+	"github.com/akarsh-2004/aether/internal/engine/entity"
+	"go.uber.org/zap"
+)
+
+func TestRemoveControlledEntity_KeepsTransferredAvatar(t *testing.T) {
+	se := NewSpatialEngine(config.Default().Engine, zap.NewNop())
+
+	avatarID, err := se.SpawnClientEntity("player", "alice", SpawnOptions{})
+	if err != nil {
+		t.Fatalf("spawn: %v", err)
+	}
+	if err := se.TransferControl(avatarID, "alice", "bob"); err != nil {
+		t.Fatalf("transfer: %v", err)
+	}
+
+	// alice disconnects after giving her avatar to bob
+	if se.RemoveControlledEntity(avatarID, "alice", "client_disconnected") {
+		t.Fatal("expected the transferred avatar not to be removed")
+	}
+	ent, exists := se.GetEntity(avatarID)
+	if !exists {
+		t.Fatal("expected the transferred avatar to stay in the world")
+	}
+	if ent.ClientID != "bob" {
+		t.Errorf("expected bob to keep control, got %q", ent.ClientID)
+	}
+
+	if !se.RemoveControlledEntity(avatarID, "bob", "client_disconnected") {
+		t.Error("expected the controller's disconnect to remove the entity")
+	}
+}
+
+func TestResumeEntity_ReturnsAvatar(t *testing.T) {
+	se := NewSpatialEngine(config.Default().Engine, zap.NewNop())
+
+	// The possessed vehicle has a lower ID than the avatar
+	se.RestoreWorld(WorldSnapshot{
+		NextEntityID: 10,
+		Entities: []entity.Entity{
+			{ID: 3, Type: "vehicle", Position: entity.Vector3{X: 10, Y: 10}, ClientID: "old"},
+			{ID: 7, Type: "player", Position: entity.Vector3{X: 12, Y: 10}, ClientID: "old", Avatar: true, ResumeHash: hashResumeToken("secret")},
+		},
+	})
+
+	avatar, resumed := se.ResumeEntity("old", "secret", "new")
+	if !resumed {
+		t.Fatal("expected the session to resume")
+	}
+	if avatar.ID != 7 {
+		t.Errorf("expected avatar 7, got %d", avatar.ID)
+	}
+	if owned := se.ControlledBy("new"); len(owned) != 2 {
+		t.Errorf("expected 2 controlled entities after resume, got %d", len(owned))
+	}
+}
+
+func TestTransferControl_ClearsAvatar(t *testing.T) {
+	se := NewSpatialEngine(config.Default().Engine, zap.NewNop())
+
+	avatarID, err := se.SpawnClientEntity("player", "alice", SpawnOptions{})
+	if err != nil {
+		t.Fatalf("spawn: %v", err)
+	}
+	if ent, _ := se.GetEntity(avatarID); !ent.Avatar {
+		t.Fatal("expected a spawned client entity to be an avatar")
+	}
+
+	if err := se.TransferControl(avatarID, "alice", "bob"); err != nil {
+		t.Fatalf("transfer: %v", err)
+	}
+	if ent, _ := se.GetEntity(avatarID); ent.Avatar {
+		t.Error("expected a transferred entity to stop being an avatar")
+	}
+}
+
+func TestResumeEntity_RequiresToken(t *testing.T) {
+	se := NewSpatialEngine(config.Default().Engine, zap.NewNop())
+
+	avatarID, err := se.SpawnClientEntity("player", "alice", SpawnOptions{})
+	if err != nil {
+		t.Fatalf("spawn: %v", err)
+	}
+	first, err := se.IssueResumeToken("alice")
+	if err != nil {
+		t.Fatalf("issue token: %v", err)
+	}
+	token, err := se.IssueResumeToken("alice")
+	if err != nil {
+		t.Fatalf("issue token: %v", err)
+	}
+	if token == first || token == "alice" {
+		t.Fatalf("expected a fresh token unrelated to the client ID, got %q", token)
+	}
+
+	// Simulate a drain: the world is snapshotted and restored
+	world := se.SnapshotWorld()
+	restored := NewSpatialEngine(config.Default().Engine, zap.NewNop())
+	restored.RestoreWorld(world)
+
+	for _, bad := range []string{"", "alice", first} {
+		if _, resumed := restored.ResumeEntity("alice", bad, "mallory"); resumed {
+			t.Fatalf("expected token %q to be refused", bad)
+		}
+	}
+
+	avatar, resumed := restored.ResumeEntity("alice", token, "alice-2")
+	if !resumed {
+		t.Fatal("expected the current token to resume the session")
+	}
+	if avatar.ID != avatarID {
+		t.Errorf("expected avatar %d, got %d", avatarID, avatar.ID)
+	}
+}
@@ -2,14 +2,16 @@ package engine
 
 import (
 	"context"
-	"fmt"
 	"math"
+	"math/rand"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/akarsh-2004/aether/internal/config"
 	"github.com/akarsh-2004/aether/internal/engine/aoi"
 	"github.com/akarsh-2004/aether/internal/engine/entity"
+	"github.com/akarsh-2004/aether/internal/engine/movement"
 	"github.com/akarsh-2004/aether/internal/engine/spatial"
 	"github.com/akarsh-2004/aether/internal/engine/tick"
 	"github.com/akarsh-2004/aether/proto"
@@ -21,32 +23,79 @@ type SpatialEngine struct {
 	logger         *zap.Logger
 	tickManager    *tick.TickManager
 	entityManager  *entity.EntityManager
-	quadtree       *spatial.Quadtree
+	index          spatial.Index
+	zones          *spatial.ZoneIndex
+	zoneMembers    map[uint32][]string // entity -> zones it is in, in name order
+	terrain        Terrain
 	aoiManager     *aoi.AOIManager
 	movementBuffer map[uint32][]*proto.MovementDelta
+	inputs         map[uint32][]*proto.InputCommand // buffered input commands per entity, in sequence order
+	projectiles    map[uint32]*projectile
+	cooldowns      map[uint32]map[string]uint64 // shooter entity -> projectile type -> tick last fired
+	movement       movement.Params
+	rand           *rand.Rand // spawn placement; guarded by mu
+	history        *stateHistory
+	latency        map[string]time.Duration // client_id -> one-way delay, for lag compensation
+	orphans        map[uint32]time.Time // restored entities waiting for their client to resume
+	pendingEvents  []OutboxEvent // domain events not yet written to the outbox
+	eventSeq       uint64 // Seq of the last event raised
+	eventLimit     int // most pending events kept; 0 discards them
+	onControl      ControlListener
+	schemas        map[string]*attributeSchema // entity type -> replicated attributes
+	scripts        ScriptHost
+	scriptAPI      *ScriptAPI
+	pendingConfig  *config.EngineConfig // reloaded settings, applied at the start of the next tick
 	mu             sync.RWMutex
 	broadcastChan  chan BroadcastMessage
+	sender         atomic.Pointer[Sender] // delivers broadcasts to connected clients
 	shutdown       chan struct{}
 	wg             sync.WaitGroup
 }
 
+// BroadcastMessage is a message queued for one client.
 type BroadcastMessage struct {
 	ClientID string
-	Data     []byte
+	Message  *proto.Message
+}
+
+// Sender delivers a message to a connected client, e.g. by queueing it on
+// the client's connection. It is called from the engine's broadcast worker
+// without the engine lock, and must not block. Messages for clients that
+// are not connected should be dropped.
+type Sender func(clientID string, message *proto.Message)
+
+// SetSender installs the callback that delivers entity states, corrections,
+// input acks and other engine output to clients. Without one they are
+// dropped.
+func (se *SpatialEngine) SetSender(send Sender) {
+	se.sender.Store(&send)
 }
 
 func NewSpatialEngine(cfg config.EngineConfig, logger *zap.Logger) *SpatialEngine {
+	index := spatial.NewIndexFromConfig(cfg)
 	se := &SpatialEngine{
 		config:         cfg,
 		logger:         logger,
 		entityManager:  entity.NewEntityManager(),
-		quadtree:       spatial.NewQuadtreeFromConfig(cfg),
-		aoiManager:     aoi.NewAOIManager(se.quadtree, cfg.AOIRadius),
+		index:          index,
+		zones:          spatial.NewZoneIndexFromConfig(cfg),
+		zoneMembers:    make(map[uint32][]string),
+		aoiManager:     aoi.NewAOIManager(index, cfg.AOIRadius),
 		movementBuffer: make(map[uint32][]*proto.MovementDelta),
+		inputs:         make(map[uint32][]*proto.InputCommand),
+		projectiles:    make(map[uint32]*projectile),
+		cooldowns:      make(map[uint32]map[string]uint64),
+		movement:       movement.ParamsFromConfig(cfg),
+		rand:           rand.New(rand.NewSource(time.Now().UnixNano())),
+		history:        newStateHistory(cfg.HistoryTicks),
+		latency:        make(map[string]time.Duration),
+		orphans:        make(map[uint32]time.Time),
+		schemas:        compileSchemas(cfg.EntityTypes),
 		broadcastChan:  make(chan BroadcastMessage, 1000),
 		shutdown:       make(chan struct{}),
 	}
 
+	se.scriptAPI = &ScriptAPI{se: se}
 	se.tickManager = tick.NewTickManager(cfg, logger)
 	se.tickManager.AddHandler(se)
 
@@ -54,7 +103,17 @@ func NewSpatialEngine(cfg config.EngineConfig, logger *zap.Logger) *SpatialEngin
 }
 
 func (se *SpatialEngine) Start(ctx context.Context) error {
+	// Worlds start in their own goroutine and may be shut down before it runs
+	se.mu.Lock()
+	select {
+	case <-se.shutdown:
+		se.mu.Unlock()
+		return nil
+	default:
+	}
 	se.wg.Add(1)
+	se.mu.Unlock()
+
 	go se.broadcastWorker()
 
 	return se.tickManager.Start(ctx)
@@ -62,7 +121,10 @@ func (se *SpatialEngine) Start(ctx context.Context) error {
 
 func (se *SpatialEngine) Shutdown(ctx context.Context) error {
 	se.tickManager.Shutdown()
+
+	se.mu.Lock()
 	close(se.shutdown)
+	se.mu.Unlock()
 	
 	done := make(chan struct{})
 	go func() {
@@ -92,18 +154,40 @@ func (se *SpatialEngine) processTick(tickNumber uint64) {
 	se.mu.Lock()
 	defer se.mu.Unlock()
 
+	se.applyPendingConfig()
+
 	// Process all pending movement deltas
 	se.processMovementDeltas()
 
+	// Apply buffered input commands
+	se.processInputs(tickNumber)
+
 	// Update entity positions based on velocity
 	se.updateEntityPositions()
 
+	// Fly projectiles and resolve their hits
+	se.processProjectiles(tickNumber)
+
+	// Raise zone enter and exit events
+	se.processZones()
+
+	// Run game logic scripts
+	if se.scripts != nil {
+		se.scripts.OnTick(se.scriptAPI, tickNumber)
+	}
+
 	// Update spatial index
 	se.updateSpatialIndex()
 
+	// Remember where everything is for PositionAt
+	se.history.record(tickNumber, se.entityManager.GetAllEntities())
+
 	// Process AOI events and generate broadcasts
 	se.processAOIEvents()
 
+	// Drop restored entities whose clients never came back
+	se.reapOrphans()
+
 	duration := time.Since(start)
 	if duration > time.Duration(se.config.TickRateMs/2)*time.Millisecond {
 		se.logger.Warn("Engine tick processing slow",
@@ -113,51 +197,91 @@ func (se *SpatialEngine) processTick(tickNumber uint64) {
 	}
 }
 
-func (se *SpatialEngine) SpawnEntity(entityType string, x, y float64, clientID string) uint32 {
+// SpawnEntity creates an entity at (x, y), raised to the ground if z is below
+// it.
+func (se *SpatialEngine) SpawnEntity(entityType string, x, y, z float64, clientID string) uint32 {
+	se.mu.Lock()
+	defer se.mu.Unlock()
+
+	entityID, _ := se.spawnEntityLocked(entityType, x, y, z, clientID)
+	return entityID
+}
+
+// spawnEntityLocked creates an entity, or returns why it could not: a limit
+// or quota from checkSpawnLocked, ErrInvalidPosition, or ErrEntityLimit when
+// no IDs are left.
+func (se *SpatialEngine) spawnEntityLocked(entityType string, x, y, z float64, clientID string) (uint32, error) {
+	position := entity.Vector3{X: x, Y: y, Z: math.Max(z, se.groundHeight(x, y))}
+
 	// Validate spawn position
-	if !se.isPositionValid(x, y) {
+	if !se.isPositionValid(position) {
 		se.logger.Warn("Invalid spawn position",
 			zap.String("client_id", clientID),
 			zap.Float64("x", x),
 			zap.Float64("y", y),
+			zap.Float64("z", z),
+		)
+		return 0, ErrInvalidPosition
+	}
+
+	if err := se.checkSpawnLocked(entityType, clientID, false); err != nil {
+		se.logger.Warn("Spawn refused",
+			zap.String("entity_type", entityType),
+			zap.String("client_id", clientID),
+			zap.Error(err),
 		)
-		return 0
+		return 0, err
 	}
 
-	ent := se.entityManager.CreateEntity(entityType, x, y, clientID)
+	ent := se.entityManager.CreateEntity(entityType, position, clientID)
 	if ent == nil {
-		return 0
+		se.logger.Error("No entity IDs left", zap.String("entity_type", entityType))
+		return 0, ErrEntityLimit
 	}
+	se.applyDefaults(ent)
 
 	// Insert into spatial index
-	if !se.quadtree.Insert(ent) {
+	if !se.index.Insert(ent) {
 		se.entityManager.RemoveEntity(ent.ID)
 		se.logger.Error("Failed to insert entity into spatial index", zap.Uint32("entity_id", ent.ID))
-		return 0
+		return 0, ErrInvalidPosition
 	}
 
+	se.publishSpawned(ent)
+
 	se.logger.Info("Entity spawned",
 		zap.Uint32("entity_id", ent.ID),
 		zap.String("entity_type", entityType),
 		zap.String("client_id", clientID),
 		zap.Float64("x", x),
 		zap.Float64("y", y),
+		zap.Float64("z", position.Z),
 	)
 
-	return ent.ID
+	if se.scripts != nil {
+		se.scripts.OnSpawn(se.scriptAPI, ent.ID)
+	}
+
+	return ent.ID, nil
 }
 
-func (se *SpatialEngine) RemoveEntity(entityID uint32) bool {
+// RemoveEntity despawns an entity. reason is recorded in the entity_despawned
+// event, e.g. "client_disconnected".
+func (se *SpatialEngine) RemoveEntity(entityID uint32, reason string) bool {
 	se.mu.Lock()
 	defer se.mu.Unlock()
 
+	return se.removeEntityLocked(entityID, reason)
+}
+
+func (se *SpatialEngine) removeEntityLocked(entityID uint32, reason string) bool {
 	ent, exists := se.entityManager.GetEntity(entityID)
 	if !exists {
 		return false
 	}
 
 	// Remove from spatial index
-	se.quadtree.Remove(ent)
+	se.index.Remove(ent)
 
 	// Remove from entity manager
 	se.entityManager.RemoveEntity(entityID)
@@ -167,22 +291,231 @@ func (se *SpatialEngine) RemoveEntity(entityID uint32) bool {
 
 	// Clear movement buffer
 	delete(se.movementBuffer, entityID)
+	delete(se.inputs, entityID)
+	delete(se.orphans, entityID)
+	delete(se.projectiles, entityID)
+	delete(se.cooldowns, entityID)
+	delete(se.zoneMembers, entityID)
+
+	se.publishDespawned(ent, reason)
 
-	se.logger.Info("Entity removed", zap.Uint32("entity_id", entityID))
+	se.logger.Info("Entity removed", zap.Uint32("entity_id", entityID), zap.String("reason", reason))
 	return true
 }
 
-func (se *SpatialEngine) ProcessMovementIntent(entityID uint32, delta *proto.MovementDelta) {
+// WorldSnapshot is a point-in-time copy of the world that can be persisted
+// and later restored into a fresh engine.
+type WorldSnapshot struct {
+	TickNumber   uint64
+	NextEntityID uint32
+	Entities     []entity.Entity
+	Events       []OutboxEvent // raised up to this tick and not yet written
+}
+
+// SnapshotWorld copies every entity together with the tick it was taken at,
+// so callers can persist world state without holding the engine lock. The
+// pending outbox events are included; they stay pending until AckEvents.
+func (se *SpatialEngine) SnapshotWorld() WorldSnapshot {
+	se.mu.RLock()
+	defer se.mu.RUnlock()
+
+	entities := se.entityManager.GetAllEntities()
+	snapshot := WorldSnapshot{
+		TickNumber:   se.tickManager.GetCurrentTick(),
+		NextEntityID: se.entityManager.NextEntityID(),
+		Entities:     make([]entity.Entity, 0, len(entities)),
+		Events:       append([]OutboxEvent(nil), se.pendingEvents...),
+	}
+
+	for _, ent := range entities {
+		if ent.Ghost {
+			continue // owned and persisted by a neighbouring region
+		}
+		if se.isProjectile(ent.ID) {
+			continue // not worth restoring mid-flight
+		}
+
+		snapshot.Entities = append(snapshot.Entities, ent.Copy())
+	}
+
+	return snapshot
+}
+
+// RestoreWorld rebuilds the entity manager, spatial index, entity ID counter
+// and tick counter from a snapshot. It must be called on an empty engine
+// before Start. Entities owned by a client are kept for ResumeGraceSec so the
+// client can reconnect and resume.
+func (se *SpatialEngine) RestoreWorld(snapshot WorldSnapshot) int {
 	se.mu.Lock()
 	defer se.mu.Unlock()
 
+	restored := 0
+	now := time.Now()
+	for i := range snapshot.Entities {
+		ent := snapshot.Entities[i]
+
+		if !se.isPositionValid(ent.Position) {
+			se.logger.Warn("Skipping restored entity outside world bounds",
+				zap.Uint32("entity_id", ent.ID),
+				zap.Float64("x", ent.Position.X),
+				zap.Float64("y", ent.Position.Y),
+				zap.Float64("z", ent.Position.Z),
+			)
+			continue
+		}
+
+		if !se.entityManager.RestoreEntity(&ent) {
+			se.logger.Warn("Skipping duplicate restored entity", zap.Uint32("entity_id", ent.ID))
+			continue
+		}
+
+		if !se.index.Insert(&ent) {
+			se.entityManager.RemoveEntity(ent.ID)
+			se.logger.Error("Failed to insert restored entity into spatial index", zap.Uint32("entity_id", ent.ID))
+			continue
+		}
+
+		if ent.ClientID != "" {
+			se.orphans[ent.ID] = now
+		}
+		restored++
+	}
+
+	se.entityManager.SetNextEntityID(snapshot.NextEntityID)
+	se.tickManager.SetCurrentTick(snapshot.TickNumber)
+
+	se.logger.Info("World state restored",
+		zap.Uint64("tick", snapshot.TickNumber),
+		zap.Uint32("next_entity_id", se.entityManager.NextEntityID()),
+		zap.Int("entity_count", restored),
+	)
+
+	return restored
+}
+
+// AddTickHandler registers an additional handler that runs after the engine
+// has processed each tick.
+func (se *SpatialEngine) AddTickHandler(handler tick.TickHandler) {
+	se.tickManager.AddHandler(handler)
+}
+
+// GetEntity returns a copy of an entity's current state.
+func (se *SpatialEngine) GetEntity(entityID uint32) (entity.Entity, bool) {
+	se.mu.RLock()
+	defer se.mu.RUnlock()
+
+	ent, exists := se.entityManager.GetEntity(entityID)
+	if !exists {
+		return entity.Entity{}, false
+	}
+	return ent.Copy(), true
+}
+
+// EntityCount returns the number of entities simulated by this engine,
+// excluding ghosts.
+func (se *SpatialEngine) EntityCount() int {
+	se.mu.RLock()
+	defer se.mu.RUnlock()
+
+	return se.entityManager.GetLocalCount()
+}
+
+// NearbyClients returns the clients controlling an entity within the AOI
+// radius of any entity clientID controls, including clientID itself.
+func (se *SpatialEngine) NearbyClients(clientID string) map[string]struct{} {
+	return se.ClientsInRadius(clientID, se.config.AOIRadius)
+}
+
+// ClientsInRadius returns the clients controlling an entity within radius of
+// any entity clientID controls, including clientID itself.
+func (se *SpatialEngine) ClientsInRadius(clientID string, radius float64) map[string]struct{} {
+	se.mu.RLock()
+	defer se.mu.RUnlock()
+
+	nearby := make(map[string]struct{})
+	for _, owned := range se.entityManager.GetEntitiesByClient(clientID) {
+		for _, ent := range se.aoiManager.GetEntitiesInRadius(owned.Position, radius) {
+			if ent.ClientID != "" && !ent.Ghost {
+				nearby[ent.ClientID] = struct{}{}
+			}
+		}
+	}
+	return nearby
+}
+
+// ResumeEntity hands the restored entities owned by prevClientID over to the
+// newly connected clientID and returns its avatar. token must be the one last
+// issued for prevClientID's avatar. Returns false if there is nothing to
+// resume or the token does not match.
+func (se *SpatialEngine) ResumeEntity(prevClientID, token, clientID string) (entity.Entity, bool) {
+	se.mu.Lock()
+	defer se.mu.Unlock()
+
+	owned := se.entityManager.GetEntitiesByClient(prevClientID)
+	avatar := avatarOf(owned)
+	if avatar == nil || !validResumeToken(avatar, token) {
+		return entity.Entity{}, false
+	}
+
+	for _, ent := range owned {
+		if _, orphaned := se.orphans[ent.ID]; !orphaned {
+			// Entity is still controlled by a live connection
+			return entity.Entity{}, false
+		}
+	}
+
+	// The client takes back everything it controlled
+	owned = se.entityManager.RebindClient(prevClientID, clientID)
+	for _, ent := range owned {
+		delete(se.orphans, ent.ID)
+	}
+
+	se.logger.Info("Entity resumed",
+		zap.Uint32("entity_id", avatar.ID),
+		zap.Int("controlled_entities", len(owned)),
+		zap.String("prev_client_id", prevClientID),
+		zap.String("client_id", clientID),
+	)
+
+	return avatar.Copy(), true
+}
+
+func (se *SpatialEngine) reapOrphans() {
+	if len(se.orphans) == 0 {
+		return
+	}
+
+	cutoff := time.Now().Add(-time.Duration(se.config.ResumeGraceSec) * time.Second)
+	for entityID, restoredAt := range se.orphans {
+		if restoredAt.Before(cutoff) {
+			se.logger.Info("Removing unclaimed restored entity", zap.Uint32("entity_id", entityID))
+			se.removeEntityLocked(entityID, "resume_expired")
+		}
+	}
+}
+
+// ProcessMovementIntent buffers a movement delta for the next tick. The
+// delta's entity must currently be controlled by clientID.
+func (se *SpatialEngine) ProcessMovementIntent(clientID string, delta *proto.MovementDelta) {
+	se.mu.Lock()
+	defer se.mu.Unlock()
+
+	entityID := delta.EntityId
+
+	if se.config.Input.Enabled {
+		se.logger.Warn("Movement delta while input commands are enabled", zap.String("client_id", clientID))
+		return
+	}
+
 	// Validate movement
-	if !se.validateMovement(entityID, delta) {
+	if !se.validateMovement(clientID, delta) {
 		se.logger.Warn("Invalid movement detected",
+			zap.String("client_id", clientID),
 			zap.Uint32("entity_id", entityID),
 			zap.Uint64("sequence", delta.Sequence),
 			zap.Float64("delta_x", delta.DeltaX),
 			zap.Float64("delta_y", delta.DeltaY),
+			zap.Float64("delta_z", delta.DeltaZ),
 		)
 		return
 	}
@@ -207,13 +540,14 @@ func (se *SpatialEngine) processMovementDeltas() {
 		for _, delta := range deltas {
 			if delta.Sequence > ent.LastSequence {
 				// Apply movement validation
-				newX := ent.Position.X + delta.DeltaX
-				newY := ent.Position.Y + delta.DeltaY
+				newPos := ent.Position.Add(entity.Vector3{X: float64(delta.DeltaX), Y: float64(delta.DeltaY)})
 
-				if se.isPositionValid(newX, newY) {
+				if se.isPositionValid(newPos) {
 					// Update velocity based on movement delta
 					ent.Velocity.X = delta.DeltaX
 					ent.Velocity.Y = delta.DeltaY
+					se.applyVerticalIntent(ent, delta)
+					ent.Yaw = float64(delta.Yaw)
 					ent.LastSequence = delta.Sequence
 				} else {
 					// Movement would go out of bounds, generate correction
@@ -231,32 +565,50 @@ func (se *SpatialEngine) updateEntityPositions() {
 	entities := se.entityManager.GetAllEntities()
 
 	for _, ent := range entities {
-		// Apply velocity to position
-		newX := ent.Position.X + ent.Velocity.X
-		newY := ent.Position.Y + ent.Velocity.Y
+		if ent.Ghost {
+			continue // positions come from the owning region
+		}
+		if se.inputDriven(ent) {
+			continue // moved by processInputs
+		}
+		if se.isProjectile(ent.ID) {
+			continue // moved by processProjectiles
+		}
+
+		// Apply velocity to position, scaled by the zones the entity is in
+		step := ent.Velocity
+		if multiplier := se.speedMultiplier(ent.Position); multiplier != 1 {
+			step.X *= multiplier
+			step.Y *= multiplier
+		}
+		newPos := ent.Position.Add(step)
 
 		// Apply friction
 		ent.Velocity.X *= 0.95
 		ent.Velocity.Y *= 0.95
 
-		// Update position if valid
-		if se.isPositionValid(newX, newY) {
-			oldPos := ent.Position
-			ent.Position.X = newX
-			ent.Position.Y = newY
+		// Apply gravity and keep the entity between the ground and the ceiling
+		se.settleHeight(ent, &newPos)
+
+		oldPos := ent.Position
 
-			// Update spatial index
-			se.quadtree.Update(ent, oldPos)
+		// Update position if valid
+		if se.isPositionValid(newPos) {
+			ent.Position = newPos
 		} else {
 			// Clamp to bounds and stop velocity
-			ent.Position.X = math.Max(se.config.WorldBounds.MinX, math.Min(se.config.WorldBounds.MaxX, newX))
-			ent.Position.Y = math.Max(se.config.WorldBounds.MinY, math.Min(se.config.WorldBounds.MaxY, newY))
+			ent.Position.X = math.Max(se.config.WorldBounds.MinX, math.Min(se.config.WorldBounds.MaxX, newPos.X))
+			ent.Position.Y = math.Max(se.config.WorldBounds.MinY, math.Min(se.config.WorldBounds.MaxY, newPos.Y))
+			ent.Position.Z = newPos.Z
 			ent.Velocity.X = 0
 			ent.Velocity.Y = 0
 
 			// Generate correction for out-of-bounds movement
 			se.generateCorrection(ent.ID)
 		}
+
+		// Update spatial index
+		se.index.Update(ent, oldPos)
 	}
 }
 
@@ -267,40 +619,68 @@ func (se *SpatialEngine) updateSpatialIndex() {
 
 func (se *SpatialEngine) processAOIEvents() {
 	entities := se.entityManager.GetAllEntities()
+	var crossings []aoi.AOIEvent
 
 	for _, ent := range entities {
 		events := se.aoiManager.UpdateEntity(ent.ID, ent.Position)
+		sent := false
 
 		for _, event := range events {
 			switch event.Type {
-			case "enter", "move":
+			case "enter":
+				// Newcomers need every attribute, not just the changed ones
+				se.broadcastToNearby(ent, true)
+				sent = true
+			case "move":
 				// Send entity state to nearby entities
-				se.broadcastToNearby(ent.ID, ent.Position)
+				se.broadcastToNearby(ent, false)
+				sent = true
 			case "exit":
 				// Send despawn notification to entities that are no longer nearby
-				se.broadcastDespawnToNearby(ent.ID, ent.Position)
+				se.broadcastDespawnToNearby(ent.ID, ent.Position, "out_of_aoi")
 			}
+			if se.scripts != nil && event.Type != "move" {
+				crossings = append(crossings, event)
+			}
+		}
+
+		// Attribute changes replicate even when the entity stands still
+		if !sent && ent.HasDirtyAttributes() {
+			se.broadcastToNearby(ent, false)
 		}
+		ent.ClearDirty()
 	}
-}
 
-func (se *SpatialEngine) broadcastToNearby(entityID uint32, position entity.Vector2) {
-	nearbyEntities := se.aoiManager.GetEntitiesInRadius(position, se.config.AOIRadius)
-	
-	ent, exists := se.entityManager.GetEntity(entityID)
-	if !exists {
-		return
+	// Scripts run after the loop, since they may spawn and despawn entities
+	for _, event := range crossings {
+		if event.Type == "enter" {
+			se.scripts.OnEnter(se.scriptAPI, event.EntityID, event.OtherID)
+		} else {
+			se.scripts.OnExit(se.scriptAPI, event.EntityID, event.OtherID)
+		}
 	}
+}
+
+func (se *SpatialEngine) broadcastToNearby(ent *entity.Entity, fullAttributes bool) {
+	entityID := ent.ID
+	nearbyEntities := se.aoiManager.GetEntitiesInRadius(ent.Position, se.config.AOIRadius)
+	tick, tickTime := se.tickManager.GetTickTime()
 
 	// Create entity state message
 	entityState := &proto.EntityState{
 		EntityId:    ent.ID,
 		X:           float32(ent.Position.X),
 		Y:           float32(ent.Position.Y),
+		Z:           float32(ent.Position.Z),
 		VelocityX:   float32(ent.Velocity.X),
 		VelocityY:   float32(ent.Velocity.Y),
-		LastUpdate:  uint64(time.Now().UnixMilli()),
+		VelocityZ:   float32(ent.Velocity.Z),
+		Yaw:         float32(ent.Yaw),
+		LastUpdate:  uint64(tickTime.UnixMilli()),
+		ServerTick:  tick,
 		EntityType:   ent.Type,
+		Attributes:     attributesToProto(ent, fullAttributes),
+		FullAttributes: fullAttributes,
 	}
 
 	for _, nearbyEnt := range nearbyEntities {
@@ -321,7 +701,7 @@ func (se *SpatialEngine) broadcastToNearby(entityID uint32, position entity.Vect
 	}
 }
 
-func (se *SpatialEngine) broadcastDespawnToNearby(entityID uint32, position entity.Vector2) {
+func (se *SpatialEngine) broadcastDespawnToNearby(entityID uint32, position entity.Vector3, reason string) {
 	nearbyEntities := se.aoiManager.GetEntitiesInRadius(position, se.config.AOIRadius)
 
 	for _, nearbyEnt := range nearbyEntities {
@@ -335,7 +715,7 @@ func (se *SpatialEngine) broadcastDespawnToNearby(entityID uint32, position enti
 			Payload: &proto.Message_Despawn{
 				Despawn: &proto.Despawn{
 					EntityId: entityID,
-					Reason:   "out_of_aoi",
+					Reason:   reason,
 				},
 			},
 		}
@@ -360,23 +740,37 @@ func (se *SpatialEngine) generateCorrection(entityID uint32) {
 				CorrectY:        float32(ent.Position.Y),
 				CorrectVelocityX: float32(ent.Velocity.X),
 				CorrectVelocityY: float32(ent.Velocity.Y),
+				CorrectZ:         float32(ent.Position.Z),
+				CorrectVelocityZ: float32(ent.Velocity.Z),
+				CorrectYaw:       float32(ent.Yaw),
 				AckSequence:     ent.LastSequence,
 			},
 		},
 	}
 
 	se.queueBroadcast(ent.ClientID, message)
+	se.publishCorrection(ent)
 }
 
+// queueBroadcast hands a message for clientID to the broadcast worker. It
+// never blocks the tick; if the worker falls behind, the message is dropped.
 func (se *SpatialEngine) queueBroadcast(clientID string, message *proto.Message) {
-	// This would be handled by the gateway
-	// For now, we'll just log it
-	se.logger.Debug("Queuing broadcast",
-		zap.String("client_id", clientID),
-		zap.String("message_type", message.Type.String()),
-	)
+	if clientID == "" {
+		return // NPCs and ghosts have no connection
+	}
+
+	select {
+	case se.broadcastChan <- BroadcastMessage{ClientID: clientID, Message: message}:
+	default:
+		se.logger.Warn("Broadcast queue full, dropping message",
+			zap.String("client_id", clientID),
+			zap.String("message_type", message.Type.String()),
+		)
+	}
 }
 
+// broadcastWorker passes queued messages to the Sender outside the engine
+// lock.
 func (se *SpatialEngine) broadcastWorker() {
 	defer se.wg.Done()
 
@@ -385,18 +779,21 @@ func (se *SpatialEngine) broadcastWorker() {
 		case <-se.shutdown:
 			return
 		case msg := <-se.broadcastChan:
-			// This would send to the gateway
-			se.logger.Debug("Broadcasting message",
-				zap.String("client_id", msg.ClientID),
-				zap.Int("data_size", len(msg.Data)),
-			)
+			if send := se.sender.Load(); send != nil {
+				(*send)(msg.ClientID, msg.Message)
+			}
 		}
 	}
 }
 
-func (se *SpatialEngine) validateMovement(entityID uint32, delta *proto.MovementDelta) bool {
-	ent, exists := se.entityManager.GetEntity(entityID)
-	if !exists {
+func (se *SpatialEngine) validateMovement(clientID string, delta *proto.MovementDelta) bool {
+	ent, exists := se.entityManager.GetEntity(delta.EntityId)
+	if !exists || ent.Ghost {
+		return false
+	}
+
+	// Only the current controller may move an entity
+	if ent.ClientID == "" || ent.ClientID != clientID {
 		return false
 	}
 
@@ -405,24 +802,28 @@ func (se *SpatialEngine) validateMovement(entityID uint32, delta *proto.Movement
 		return false
 	}
 
-	// Check movement speed
-	distance := math.Sqrt(delta.DeltaX*delta.DeltaX + delta.DeltaY*delta.DeltaY)
+	// Check movement speed. Under gravity delta_z is a jump request that is
+	// capped separately, so only flying counts it as movement.
+	step := entity.Vector3{X: float64(delta.DeltaX), Y: float64(delta.DeltaY)}
+	if se.config.Gravity == 0 {
+		step.Z = float64(delta.DeltaZ)
+	}
+	distance := math.Sqrt(step.Length3D())
 	if distance > se.config.MaxSpeed {
 		return false
 	}
 
 	// Check if new position would be valid
-	newX := ent.Position.X + delta.DeltaX
-	newY := ent.Position.Y + delta.DeltaY
-
-	return se.isPositionValid(newX, newY)
+	return se.isPositionValid(ent.Position.Add(entity.Vector3{X: float64(delta.DeltaX), Y: float64(delta.DeltaY)}))
 }
 
-func (se *SpatialEngine) isPositionValid(x, y float64) bool {
-	return x >= se.config.WorldBounds.MinX &&
-		x <= se.config.WorldBounds.MaxX &&
-		y >= se.config.WorldBounds.MinY &&
-		y <= se.config.WorldBounds.MaxY
+func (se *SpatialEngine) isPositionValid(pos entity.Vector3) bool {
+	return pos.X >= se.config.WorldBounds.MinX &&
+		pos.X <= se.config.WorldBounds.MaxX &&
+		pos.Y >= se.config.WorldBounds.MinY &&
+		pos.Y <= se.config.WorldBounds.MaxY &&
+		pos.Z >= se.config.WorldBounds.MinZ &&
+		pos.Z <= se.config.WorldBounds.MaxZ
 }
 
 func (se *SpatialEngine) GetStats() map[string]interface{} {
@@ -432,8 +833,15 @@ func (se *SpatialEngine) GetStats() map[string]interface{} {
 	return map[string]interface{}{
 		"entity_count":       se.entityManager.GetEntityCount(),
 		"current_tick":       se.tickManager.GetCurrentTick(),
-		"quadtree_stats":     se.quadtree.GetStats(),
+		"spatial_index":      se.config.SpatialIndex,
+		"spatial_index_stats": se.index.GetStats(),
 		"aoi_subscribers":    se.aoiManager.GetSubscriberCount(),
 		"movement_buffer_size": len(se.movementBuffer),
+		"input_buffer_size":  len(se.inputs),
+		"projectiles":        len(se.projectiles),
+		"local_entities":     se.entityManager.GetLocalCount(),
+		"max_entities":       se.config.MaxEntities,
+		"recycled_ids":       se.entityManager.RecycledIDs(),
+		"zones":              se.zones.Len(),
 	}
 }
@@ -0,0 +1,102 @@
+package entity
+
+import "sort"
+
+// Attribute value kinds.
+const (
+	KindInt    = "int"
+	KindFloat  = "float"
+	KindString = "string"
+	KindBool   = "bool"
+)
+
+// Value is a replicated attribute value. Only the field matching Kind is
+// meaningful.
+type Value struct {
+	Kind   string  `json:"kind"`
+	Int    int64   `json:"int,omitempty"`
+	Float  float64 `json:"float,omitempty"`
+	String string  `json:"string,omitempty"`
+	Bool   bool    `json:"bool,omitempty"`
+}
+
+// SetAttribute stores an attribute, marking it dirty if it changed.
+func (e *Entity) SetAttribute(name string, value Value) {
+	if current, exists := e.Attributes[name]; exists && current == value {
+		return
+	}
+
+	if e.Attributes == nil {
+		e.Attributes = make(map[string]Value)
+	}
+	e.Attributes[name] = value
+	e.markDirty(name)
+}
+
+// DeleteAttribute removes an attribute, marking it dirty so clients drop it.
+func (e *Entity) DeleteAttribute(name string) {
+	if _, exists := e.Attributes[name]; !exists {
+		return
+	}
+
+	delete(e.Attributes, name)
+	e.markDirty(name)
+}
+
+// ReplaceAttributes makes the entity's attributes equal to attrs, marking
+// only the differences dirty.
+func (e *Entity) ReplaceAttributes(attrs map[string]Value) {
+	for name := range e.Attributes {
+		if _, keep := attrs[name]; !keep {
+			e.DeleteAttribute(name)
+		}
+	}
+	for name, value := range attrs {
+		e.SetAttribute(name, value)
+	}
+}
+
+func (e *Entity) markDirty(name string) {
+	if e.dirty == nil {
+		e.dirty = make(map[string]struct{})
+	}
+	e.dirty[name] = struct{}{}
+}
+
+// HasDirtyAttributes reports whether any attribute changed since the last
+// ClearDirty.
+func (e *Entity) HasDirtyAttributes() bool {
+	return len(e.dirty) > 0
+}
+
+// DirtyAttributes returns the names of attributes changed since the last
+// ClearDirty, sorted. Removed attributes are included.
+func (e *Entity) DirtyAttributes() []string {
+	names := make([]string, 0, len(e.dirty))
+	for name := range e.dirty {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ClearDirty marks every attribute as replicated.
+func (e *Entity) ClearDirty() {
+	e.dirty = nil
+}
+
+// Copy returns a copy of the entity that shares no state with it. Pending
+// moves and dirty marks are not copied.
+func (e *Entity) Copy() Entity {
+	copied := *e
+	copied.PendingMoves = nil
+	copied.dirty = nil
+
+	if e.Attributes != nil {
+		copied.Attributes = make(map[string]Value, len(e.Attributes))
+		for name, value := range e.Attributes {
+			copied.Attributes[name] = value
+		}
+	}
+	return copied
+}
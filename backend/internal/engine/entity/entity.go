@@ -1,6 +1,7 @@
 package entity
 
 import (
+	"sort"
 	"sync"
 	"time"
 
@@ -10,60 +11,221 @@ import (
 type Entity struct {
 	ID         uint32
 	Type       string
-	Position   Vector2
-	Velocity   Vector2
+	Position   Vector3
+	Velocity   Vector3
+	Yaw        float64 // Heading in radians, counter-clockwise from +X
 	ClientID   string
 	LastUpdate time.Time
+	Ghost      bool   // Read-only mirror of an entity owned by a neighbouring region
+	Avatar     bool   // Spawned for ClientID as its own entity, as opposed to possessed or given to it
+	ResumeHash []byte // SHA-256 of the avatar's resume token, see SpatialEngine.IssueResumeToken
 	
 	// Movement validation
 	LastSequence uint64
 	PendingMoves []*proto.MovementDelta
+
+	// Replicated state such as health or a name tag, see attributes.go
+	Attributes map[string]Value
+	dirty      map[string]struct{} // attributes changed since the last replication
 }
 
-type Vector2 struct {
+// Vector3 is a position or velocity. X and Y span the ground plane and Z is
+// height; worlds without vertical movement leave Z at their floor.
+type Vector3 struct {
 	X float64
 	Y float64
+	Z float64
 }
 
 type EntityManager struct {
 	entities    map[uint32]*Entity
-	clientMap   map[string]uint32 // client_id -> entity_id
-	nextEntityID uint32
+	clientMap   map[string]map[uint32]struct{} // client_id -> IDs of the entities it controls
+	typeCounts  map[string]int                 // entity type -> local entities of that type
+	localCount  int                            // entities that are not ghosts
+	ids         *idAllocator
 	mu          sync.RWMutex
 }
 
 func NewEntityManager() *EntityManager {
 	return &EntityManager{
-		entities:     make(map[uint32]*Entity),
-		clientMap:    make(map[string]uint32),
-		nextEntityID: 1,
+		entities:   make(map[uint32]*Entity),
+		clientMap:  make(map[string]map[uint32]struct{}),
+		typeCounts: make(map[string]int),
+		ids:        newIDAllocator(),
 	}
 }
 
-func (em *EntityManager) CreateEntity(entityType string, x, y float64, clientID string) *Entity {
+// CreateEntity creates an entity with a fresh ID. It returns nil if every
+// ID is in use.
+func (em *EntityManager) CreateEntity(entityType string, position Vector3, clientID string) *Entity {
 	em.mu.Lock()
 	defer em.mu.Unlock()
 
-	entityID := em.nextEntityID
-	em.nextEntityID++
+	entityID, ok := em.ids.allocate(func(id uint32) bool {
+		_, taken := em.entities[id]
+		return taken
+	})
+	if !ok {
+		return nil
+	}
 
 	entity := &Entity{
 		ID:         entityID,
 		Type:       entityType,
-		Position:   Vector2{X: x, Y: y},
-		Velocity:   Vector2{X: 0, Y: 0},
+		Position:   position,
+		Velocity:   Vector3{},
 		ClientID:   clientID,
 		LastUpdate: time.Now(),
 	}
 
 	em.entities[entityID] = entity
-	if clientID != "" {
-		em.clientMap[clientID] = entityID
-	}
+	em.bindLocked(entity)
+	em.countLocked(entity, 1)
 
 	return entity
 }
 
+// AddEntity inserts an entity created elsewhere, such as one handed off from
+// another region, keeping its ID. Returns false if the ID is already taken.
+func (em *EntityManager) AddEntity(ent *Entity) bool {
+	em.mu.Lock()
+	defer em.mu.Unlock()
+
+	return em.addLocked(ent)
+}
+
+func (em *EntityManager) addLocked(ent *Entity) bool {
+	if _, exists := em.entities[ent.ID]; exists {
+		return false
+	}
+
+	em.entities[ent.ID] = ent
+	em.bindLocked(ent)
+	em.countLocked(ent, 1)
+
+	return true
+}
+
+// RestoreEntity inserts an entity that was loaded from a snapshot, keeping its
+// original ID. Returns false if the ID is already taken. Call SetNextEntityID
+// once every entity is restored so their slots are not handed out again.
+func (em *EntityManager) RestoreEntity(ent *Entity) bool {
+	em.mu.Lock()
+	defer em.mu.Unlock()
+
+	return em.addLocked(ent)
+}
+
+// NextEntityID returns the first ID in the block that has never been handed
+// out. Snapshots store it so a restored world continues the block.
+func (em *EntityManager) NextEntityID() uint32 {
+	em.mu.RLock()
+	defer em.mu.RUnlock()
+
+	if em.ids.next > slotMask {
+		return em.ids.prefix | slotMask
+	}
+	return em.ids.prefix | em.ids.next
+}
+
+// SetNextEntityID moves ID allocation to id's region block and its slot
+// counter forward to id, never backwards. Slots below the counter that no
+// entity holds are freed for reuse. It is meant for startup, after a restore
+// or when a region claims its block.
+func (em *EntityManager) SetNextEntityID(id uint32) {
+	em.mu.Lock()
+	defer em.mu.Unlock()
+
+	inUse := make([]uint32, 0, len(em.entities))
+	for entityID := range em.entities {
+		inUse = append(inUse, entityID)
+	}
+	em.ids.reset(id, inUse)
+}
+
+// RecycledIDs returns how many IDs have been handed out from freed slots.
+func (em *EntityManager) RecycledIDs() uint64 {
+	em.mu.RLock()
+	defer em.mu.RUnlock()
+
+	return em.ids.recycled
+}
+
+// RebindClient moves every entity controlled by oldClientID to newClientID
+// and returns them ordered by ID.
+func (em *EntityManager) RebindClient(oldClientID, newClientID string) []*Entity {
+	em.mu.Lock()
+	defer em.mu.Unlock()
+
+	entities := em.byClientLocked(oldClientID)
+	for _, entity := range entities {
+		em.setOwnerLocked(entity, newClientID)
+	}
+
+	return entities
+}
+
+// SetOwner gives control of an entity to clientID, or to nobody if clientID
+// is empty. It returns the previous controller.
+func (em *EntityManager) SetOwner(id uint32, clientID string) (*Entity, string, bool) {
+	em.mu.Lock()
+	defer em.mu.Unlock()
+
+	entity, exists := em.entities[id]
+	if !exists {
+		return nil, "", false
+	}
+
+	previous := entity.ClientID
+	em.setOwnerLocked(entity, clientID)
+	return entity, previous, true
+}
+
+func (em *EntityManager) setOwnerLocked(entity *Entity, clientID string) {
+	em.unbindLocked(entity)
+	entity.ClientID = clientID
+	entity.LastUpdate = time.Now()
+	em.bindLocked(entity)
+}
+
+func (em *EntityManager) bindLocked(entity *Entity) {
+	if entity.ClientID == "" {
+		return
+	}
+
+	owned, exists := em.clientMap[entity.ClientID]
+	if !exists {
+		owned = make(map[uint32]struct{})
+		em.clientMap[entity.ClientID] = owned
+	}
+	owned[entity.ID] = struct{}{}
+}
+
+// countLocked adds delta to the local counts, leaving out ghosts.
+func (em *EntityManager) countLocked(entity *Entity, delta int) {
+	if entity.Ghost {
+		return
+	}
+
+	em.localCount += delta
+	em.typeCounts[entity.Type] += delta
+	if em.typeCounts[entity.Type] == 0 {
+		delete(em.typeCounts, entity.Type)
+	}
+}
+
+func (em *EntityManager) unbindLocked(entity *Entity) {
+	owned, exists := em.clientMap[entity.ClientID]
+	if !exists {
+		return
+	}
+
+	delete(owned, entity.ID)
+	if len(owned) == 0 {
+		delete(em.clientMap, entity.ClientID)
+	}
+}
+
 func (em *EntityManager) GetEntity(id uint32) (*Entity, bool) {
 	em.mu.RLock()
 	defer em.mu.RUnlock()
@@ -72,17 +234,26 @@ func (em *EntityManager) GetEntity(id uint32) (*Entity, bool) {
 	return entity, exists
 }
 
-func (em *EntityManager) GetEntityByClient(clientID string) (*Entity, bool) {
+// GetEntitiesByClient returns the entities controlled by clientID, ordered
+// by ID.
+func (em *EntityManager) GetEntitiesByClient(clientID string) []*Entity {
 	em.mu.RLock()
 	defer em.mu.RUnlock()
 
-	entityID, exists := em.clientMap[clientID]
-	if !exists {
-		return nil, false
+	return em.byClientLocked(clientID)
+}
+
+func (em *EntityManager) byClientLocked(clientID string) []*Entity {
+	owned := em.clientMap[clientID]
+	entities := make([]*Entity, 0, len(owned))
+	for id := range owned {
+		if entity, exists := em.entities[id]; exists {
+			entities = append(entities, entity)
+		}
 	}
 
-	entity, exists := em.entities[entityID]
-	return entity, exists
+	sort.Slice(entities, func(i, j int) bool { return entities[i].ID < entities[j].ID })
+	return entities
 }
 
 func (em *EntityManager) RemoveEntity(id uint32) bool {
@@ -95,14 +266,14 @@ func (em *EntityManager) RemoveEntity(id uint32) bool {
 	}
 
 	delete(em.entities, id)
-	if entity.ClientID != "" {
-		delete(em.clientMap, entity.ClientID)
-	}
+	em.unbindLocked(entity)
+	em.countLocked(entity, -1)
+	em.ids.release(id)
 
 	return true
 }
 
-func (em *EntityManager) UpdateEntity(id uint32, position, velocity Vector2) bool {
+func (em *EntityManager) UpdateEntity(id uint32, position, velocity Vector3) bool {
 	em.mu.Lock()
 	defer em.mu.Unlock()
 
@@ -190,24 +361,62 @@ func (em *EntityManager) GetEntityCount() int {
 	return len(em.entities)
 }
 
-func (v Vector2) Add(other Vector2) Vector2 {
-	return Vector2{X: v.X + other.X, Y: v.Y + other.Y}
+// GetLocalCount returns how many entities are simulated here, leaving out
+// ghosts.
+func (em *EntityManager) GetLocalCount() int {
+	em.mu.RLock()
+	defer em.mu.RUnlock()
+
+	return em.localCount
+}
+
+// GetTypeCount returns how many local entities have the given type.
+func (em *EntityManager) GetTypeCount(entityType string) int {
+	em.mu.RLock()
+	defer em.mu.RUnlock()
+
+	return em.typeCounts[entityType]
+}
+
+// GetClientCount returns how many entities clientID controls.
+func (em *EntityManager) GetClientCount(clientID string) int {
+	em.mu.RLock()
+	defer em.mu.RUnlock()
+
+	return len(em.clientMap[clientID])
 }
 
-func (v Vector2) Subtract(other Vector2) Vector2 {
-	return Vector2{X: v.X - other.X, Y: v.Y - other.Y}
+func (v Vector3) Add(other Vector3) Vector3 {
+	return Vector3{X: v.X + other.X, Y: v.Y + other.Y, Z: v.Z + other.Z}
 }
 
-func (v Vector2) Multiply(scalar float64) Vector2 {
-	return Vector2{X: v.X * scalar, Y: v.Y * scalar}
+func (v Vector3) Subtract(other Vector3) Vector3 {
+	return Vector3{X: v.X - other.X, Y: v.Y - other.Y, Z: v.Z - other.Z}
 }
 
-func (v Vector2) Distance(other Vector2) float64 {
+func (v Vector3) Multiply(scalar float64) Vector3 {
+	return Vector3{X: v.X * scalar, Y: v.Y * scalar, Z: v.Z * scalar}
+}
+
+// Distance is the squared distance on the ground plane, ignoring height.
+func (v Vector3) Distance(other Vector3) float64 {
 	dx := v.X - other.X
 	dy := v.Y - other.Y
 	return dx*dx + dy*dy // Return squared distance for performance
 }
 
-func (v Vector2) Length() float64 {
+// Distance3D is the squared distance including height.
+func (v Vector3) Distance3D(other Vector3) float64 {
+	dz := v.Z - other.Z
+	return v.Distance(other) + dz*dz
+}
+
+// Length is the squared length on the ground plane, ignoring height.
+func (v Vector3) Length() float64 {
 	return v.X*v.X + v.Y*v.Y // Return squared length for performance
 }
+
+// Length3D is the squared length including height.
+func (v Vector3) Length3D() float64 {
+	return v.Length() + v.Z*v.Z
+}
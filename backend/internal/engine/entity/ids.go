@@ -0,0 +1,125 @@
+package entity
+
+// Entity IDs are split into a region prefix, a generation and a slot:
+//
+//	bits 24-31: region block, see shard.Region.EntityIDBase
+//	bits 16-23: generation
+//	bits  0-15: slot
+//
+// A removed entity's slot is reused with the next generation, so an ID a
+// client still holds never addresses the entity that takes the slot next,
+// until the generation wraps after 256 reuses. Freed slots are reused oldest
+// first to keep that as far off as possible.
+const (
+	slotBits   = 16
+	genBits    = 8
+	slotMask   = 1<<slotBits - 1
+	genMask    = (1<<genBits - 1) << slotBits
+	prefixMask = ^uint32(slotMask | genMask)
+)
+
+// idAllocator hands out entity IDs within one region block.
+type idAllocator struct {
+	prefix      uint32
+	next        uint32            // next slot never handed out; slot 0 is unused so no ID is 0
+	generations map[uint32]uint32 // slot -> generation of its current ID
+	free        []uint32          // released slots, oldest first
+	recycled    uint64
+}
+
+func newIDAllocator() *idAllocator {
+	return &idAllocator{
+		next:        1,
+		generations: make(map[uint32]uint32),
+	}
+}
+
+func splitID(id uint32) (prefix, generation, slot uint32) {
+	return id & prefixMask, (id & genMask) >> slotBits, id & slotMask
+}
+
+func (a *idAllocator) id(slot uint32) uint32 {
+	return a.prefix | a.generations[slot]<<slotBits | slot
+}
+
+// allocate returns an unused ID, or false once every slot is in use. taken
+// reports IDs already held, e.g. by entities handed back from other regions.
+func (a *idAllocator) allocate(taken func(uint32) bool) (uint32, bool) {
+	for len(a.free) > 0 {
+		slot := a.free[0]
+		a.free = a.free[1:]
+
+		a.generations[slot] = (a.generations[slot] + 1) % (1 << genBits)
+		if id := a.id(slot); !taken(id) {
+			a.recycled++
+			return id, true
+		}
+	}
+
+	for a.next <= slotMask {
+		slot := a.next
+		a.next++
+
+		if id := a.id(slot); !taken(id) {
+			return id, true
+		}
+	}
+
+	return 0, false
+}
+
+// release frees the slot of an ID this allocator handed out, once its
+// entity is removed.
+func (a *idAllocator) release(id uint32) {
+	prefix, generation, slot := splitID(id)
+	if prefix != a.prefix || slot == 0 || slot >= a.next || a.generations[slot] != generation {
+		return // another region's ID, or the slot has moved on
+	}
+	a.free = append(a.free, slot)
+}
+
+// reset moves the allocator to the block of next and its slot counter to
+// at least next's slot, then rebuilds the free slots around the IDs in use.
+// Generations of slots freed before a restore are not known, so they start
+// over.
+func (a *idAllocator) reset(next uint32, inUse []uint32) {
+	prefix, _, slot := splitID(next)
+	if prefix != a.prefix {
+		a.prefix = prefix
+		a.next = 1
+		a.generations = make(map[uint32]uint32)
+		a.free = nil
+	}
+	if slot > a.next {
+		a.next = slot
+	}
+
+	used := make(map[uint32]bool, len(inUse))
+	for _, id := range inUse {
+		prefix, generation, slot := splitID(id)
+		if prefix != a.prefix || slot == 0 {
+			continue
+		}
+		used[slot] = true
+		a.generations[slot] = generation
+		if slot >= a.next {
+			a.next = slot + 1
+		}
+	}
+
+	// Keep the order of slots already free, then add the rest
+	queued := make(map[uint32]bool, len(a.free))
+	free := a.free[:0]
+	for _, slot := range a.free {
+		if !used[slot] && !queued[slot] {
+			queued[slot] = true
+			free = append(free, slot)
+		}
+	}
+	for slot := uint32(1); slot < a.next; slot++ {
+		if !used[slot] && !queued[slot] {
+			free = append(free, slot)
+		}
+	}
+	a.free = free
+}
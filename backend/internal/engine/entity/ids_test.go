@@ -0,0 +1,174 @@
+package entity
+
+import "testing"
+
+func notTaken(uint32) bool { return false }
+
+func TestIDAllocator_Layout(t *testing.T) {
+	tests := []struct {
+		name       string
+		id         uint32
+		prefix     uint32
+		generation uint32
+		slot       uint32
+	}{
+		{"first slot", 1, 0, 0, 1},
+		{"region block", 3<<24 | 5, 3 << 24, 0, 5},
+		{"reused slot", 2<<16 | 7, 0, 2, 7},
+		{"all fields", 0xAB<<24 | 0xCD<<16 | 0xBEEF, 0xAB << 24, 0xCD, 0xBEEF},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prefix, generation, slot := splitID(tt.id)
+			if prefix != tt.prefix || generation != tt.generation || slot != tt.slot {
+				t.Errorf("expected prefix %#x, generation %d, slot %d, got %#x, %d, %d",
+					tt.prefix, tt.generation, tt.slot, prefix, generation, slot)
+			}
+		})
+	}
+}
+
+func TestIDAllocator_Reuse(t *testing.T) {
+	tests := []struct {
+		name     string
+		allocate int      // IDs handed out first
+		release  []uint32 // slots freed, in order
+		want     []uint32 // IDs handed out next
+	}{
+		{"fresh slots count up", 0, nil, []uint32{1, 2, 3}},
+		{"freed slot comes back one generation on", 2, []uint32{1}, []uint32{1<<16 | 1, 3}},
+		{"freed slots reused oldest first", 3, []uint32{3, 1, 2}, []uint32{1<<16 | 3, 1<<16 | 1, 1<<16 | 2, 4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newIDAllocator()
+			for i := 0; i < tt.allocate; i++ {
+				a.allocate(notTaken)
+			}
+			for _, slot := range tt.release {
+				a.release(a.id(slot))
+			}
+
+			for _, want := range tt.want {
+				id, ok := a.allocate(notTaken)
+				if !ok || id != want {
+					t.Fatalf("expected ID %#x, got %#x (ok=%v)", want, id, ok)
+				}
+			}
+		})
+	}
+}
+
+func TestIDAllocator_ReleaseIgnoresForeignAndStaleIDs(t *testing.T) {
+	a := newIDAllocator()
+	id, _ := a.allocate(notTaken)
+
+	a.release(5<<24 | id) // another region's block
+	a.release(id | 3<<16) // wrong generation
+	a.release(a.id(1000)) // never handed out
+	if len(a.free) != 0 {
+		t.Fatalf("expected no free slots, got %v", a.free)
+	}
+
+	a.release(id)
+	if id, _ := a.allocate(notTaken); id != 1<<16|1 {
+		t.Fatalf("expected slot 1 back at generation 1, got %#x", id)
+	}
+
+	a.release(id) // the slot has moved on to generation 1
+	if len(a.free) != 0 {
+		t.Errorf("expected the stale release to be ignored, got %v", a.free)
+	}
+}
+
+func TestIDAllocator_GenerationWraps(t *testing.T) {
+	a := newIDAllocator()
+	first, _ := a.allocate(notTaken)
+
+	id := first
+	for i := 0; i < 1<<genBits; i++ {
+		a.release(id)
+		id, _ = a.allocate(notTaken)
+	}
+
+	if id != first {
+		t.Errorf("expected the generation to wrap back to %#x after 256 reuses, got %#x", first, id)
+	}
+	if a.recycled != 1<<genBits {
+		t.Errorf("expected 256 recycled IDs, got %d", a.recycled)
+	}
+}
+
+func TestIDAllocator_SkipsTakenIDs(t *testing.T) {
+	a := newIDAllocator()
+	taken := map[uint32]bool{1: true, 2: true}
+
+	id, ok := a.allocate(func(id uint32) bool { return taken[id] })
+	if !ok || id != 3 {
+		t.Fatalf("expected ID 3, got %d (ok=%v)", id, ok)
+	}
+}
+
+func TestIDAllocator_Exhausted(t *testing.T) {
+	a := newIDAllocator()
+	a.next = slotMask
+
+	if _, ok := a.allocate(notTaken); !ok {
+		t.Fatal("expected the last slot to be handed out")
+	}
+	if id, ok := a.allocate(notTaken); ok {
+		t.Errorf("expected no IDs left, got %#x", id)
+	}
+}
+
+func TestEntityManager_StaleIDFailsAfterReuse(t *testing.T) {
+	em := NewEntityManager()
+
+	old := em.CreateEntity("player", Vector3{}, "alice")
+	em.RemoveEntity(old.ID)
+	reused := em.CreateEntity("player", Vector3{}, "bob")
+
+	_, _, oldSlot := splitID(old.ID)
+	_, _, newSlot := splitID(reused.ID)
+	if oldSlot != newSlot {
+		t.Fatalf("expected slot %d to be reused, got %d", oldSlot, newSlot)
+	}
+	if _, ok := em.GetEntity(old.ID); ok {
+		t.Error("expected the stale ID not to find the entity in its slot")
+	}
+	if ent, ok := em.GetEntity(reused.ID); !ok || ent.ClientID != "bob" {
+		t.Error("expected the new ID to find the new entity")
+	}
+	if em.RecycledIDs() != 1 {
+		t.Errorf("expected 1 recycled ID, got %d", em.RecycledIDs())
+	}
+}
+
+func TestEntityManager_ResetAfterRestore(t *testing.T) {
+	const prefix = 2 << 24
+
+	em := NewEntityManager()
+	em.RestoreEntity(&Entity{ID: prefix | 4<<16 | 2, Type: "player"})
+	em.RestoreEntity(&Entity{ID: prefix | 5, Type: "player"})
+	em.SetNextEntityID(prefix | 4)
+
+	if next := em.NextEntityID(); next != prefix|6 {
+		t.Errorf("expected the counter past the highest restored slot, got %#x", next)
+	}
+
+	// Slots 1, 3 and 4 are free; slot 2 keeps its restored generation
+	want := []uint32{prefix | 1<<16 | 1, prefix | 1<<16 | 3, prefix | 1<<16 | 4, prefix | 6}
+	for _, id := range want {
+		ent := em.CreateEntity("player", Vector3{}, "")
+		if ent == nil || ent.ID != id {
+			t.Fatalf("expected ID %#x, got %+v", id, ent)
+		}
+	}
+
+	em.RemoveEntity(prefix | 4<<16 | 2)
+	if ent := em.CreateEntity("player", Vector3{}, ""); ent.ID != prefix|5<<16|2 {
+		t.Errorf("expected slot 2 to continue from its restored generation, got %#x", ent.ID)
+	}
+}
@@ -0,0 +1,160 @@
+package engine
+
+import (
+	"fmt"
+
+	"github.com/akarsh-2004/aether/internal/engine/entity"
+	"github.com/akarsh-2004/aether/proto"
+	"go.uber.org/zap"
+	protobuf "google.golang.org/protobuf/proto"
+)
+
+// OutboxEvent is a domain event raised by the engine that has not been
+// written to the outbox yet. Seq increases by one per event.
+type OutboxEvent struct {
+	Seq          uint64
+	AggregateKey string
+	Event        protobuf.Message
+}
+
+// EnableOutbox makes the engine keep the domain events it raises until they
+// are acknowledged with AckEvents. World snapshots carry them, so they are
+// written in the same transaction as the state they describe; worlds that
+// are not snapshotted need an event flusher. At most maxPending are kept,
+// so an outage cannot grow the backlog without bound. Without it events are
+// discarded. It should be called before Start.
+func (se *SpatialEngine) EnableOutbox(maxPending int) {
+	se.mu.Lock()
+	defer se.mu.Unlock()
+
+	se.eventLimit = maxPending
+}
+
+// PendingEvents returns the events not acknowledged yet, oldest first.
+func (se *SpatialEngine) PendingEvents() []OutboxEvent {
+	se.mu.RLock()
+	defer se.mu.RUnlock()
+
+	return append([]OutboxEvent(nil), se.pendingEvents...)
+}
+
+// AckEvents forgets every event up to and including seq, once they have been
+// committed to the outbox.
+func (se *SpatialEngine) AckEvents(seq uint64) {
+	se.mu.Lock()
+	defer se.mu.Unlock()
+
+	n := 0
+	for n < len(se.pendingEvents) && se.pendingEvents[n].Seq <= seq {
+		n++
+	}
+	se.pendingEvents = append(se.pendingEvents[:0], se.pendingEvents[n:]...)
+}
+
+// publish queues a domain event. The caller must hold the engine lock.
+func (se *SpatialEngine) publish(aggregateKey string, event protobuf.Message) {
+	if se.eventLimit <= 0 {
+		return
+	}
+
+	if len(se.pendingEvents) >= se.eventLimit {
+		dropped := se.pendingEvents[0]
+		se.pendingEvents = se.pendingEvents[1:]
+		se.logger.Error("Outbox backlog full, dropping oldest event",
+			zap.Uint64("seq", dropped.Seq),
+			zap.String("aggregate_key", dropped.AggregateKey),
+			zap.Int("max_pending_events", se.eventLimit),
+		)
+	}
+
+	se.eventSeq++
+	se.pendingEvents = append(se.pendingEvents, OutboxEvent{
+		Seq:          se.eventSeq,
+		AggregateKey: aggregateKey,
+		Event:        event,
+	})
+}
+
+func entityAggregateKey(entityID uint32) string {
+	return fmt.Sprintf("entity:%d", entityID)
+}
+
+func (se *SpatialEngine) publishSpawned(ent *entity.Entity) {
+	se.publish(entityAggregateKey(ent.ID), &proto.EntitySpawnedEvent{
+		EntityId:   ent.ID,
+		EntityType: ent.Type,
+		ClientId:   ent.ClientID,
+		X:          ent.Position.X,
+		Y:          ent.Position.Y,
+		Z:          ent.Position.Z,
+	})
+}
+
+func (se *SpatialEngine) publishDespawned(ent *entity.Entity, reason string) {
+	se.publish(entityAggregateKey(ent.ID), &proto.EntityDespawnedEvent{
+		EntityId: ent.ID,
+		ClientId: ent.ClientID,
+		Reason:   reason,
+		X:        ent.Position.X,
+		Y:        ent.Position.Y,
+		Z:        ent.Position.Z,
+	})
+}
+
+func (se *SpatialEngine) publishCorrection(ent *entity.Entity) {
+	se.publish(entityAggregateKey(ent.ID), &proto.MovementCorrectionEvent{
+		EntityId:    ent.ID,
+		ClientId:    ent.ClientID,
+		X:           ent.Position.X,
+		Y:           ent.Position.Y,
+		Z:           ent.Position.Z,
+		AckSequence: ent.LastSequence,
+	})
+}
+
+func (se *SpatialEngine) publishProjectileHit(proj *entity.Entity, p *projectile, target *entity.Entity, damage int) {
+	se.publish(entityAggregateKey(target.ID), &proto.ProjectileHitEvent{
+		ProjectileId:    proj.ID,
+		ProjectileType:  p.kind,
+		ShooterEntityId: p.shooterID,
+		ShooterClientId: p.shooterClient,
+		TargetEntityId:  target.ID,
+		TargetClientId:  target.ClientID,
+		X:               proj.Position.X,
+		Y:               proj.Position.Y,
+		Z:               proj.Position.Z,
+		Damage:          int32(damage),
+	})
+}
+
+func (se *SpatialEngine) publishZoneCrossed(ent *entity.Entity, zone string, entered bool) {
+	if entered {
+		se.publish(entityAggregateKey(ent.ID), &proto.ZoneEnteredEvent{
+			EntityId: ent.ID,
+			ClientId: ent.ClientID,
+			Zone:     zone,
+			X:        ent.Position.X,
+			Y:        ent.Position.Y,
+			Z:        ent.Position.Z,
+		})
+		return
+	}
+
+	se.publish(entityAggregateKey(ent.ID), &proto.ZoneExitedEvent{
+		EntityId: ent.ID,
+		ClientId: ent.ClientID,
+		Zone:     zone,
+		X:        ent.Position.X,
+		Y:        ent.Position.Y,
+		Z:        ent.Position.Z,
+	})
+}
+
+func (se *SpatialEngine) publishControlChanged(ent *entity.Entity, previous, reason string) {
+	se.publish(entityAggregateKey(ent.ID), &proto.EntityControlChangedEvent{
+		EntityId:         ent.ID,
+		ClientId:         ent.ClientID,
+		PreviousClientId: previous,
+		Reason:           reason,
+	})
+}
@@ -0,0 +1,57 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/akarsh-2004/aether/internal/config"
+	"github.com/akarsh-2004/aether/proto"
+	"go.uber.org/zap"
+)
+
+func TestOutbox_EventsPendingUntilAcked(t *testing.T) {
+	se := NewSpatialEngine(config.Default().Engine, zap.NewNop())
+	se.EnableOutbox(10)
+
+	first := se.SpawnEntity("npc", 0, 0, 0, "")
+	second := se.SpawnEntity("npc", 5, 0, 0, "")
+
+	snapshot := se.SnapshotWorld()
+	if len(snapshot.Events) != 2 {
+		t.Fatalf("expected the snapshot to carry 2 spawn events, got %d", len(snapshot.Events))
+	}
+	spawned, ok := snapshot.Events[0].Event.(*proto.EntitySpawnedEvent)
+	if !ok || spawned.EntityId != first {
+		t.Fatalf("expected the first event to be the spawn of %d, got %+v", first, snapshot.Events[0].Event)
+	}
+
+	// An event raised after the snapshot must survive acknowledging it
+	se.RemoveEntity(second, "test")
+	se.AckEvents(snapshot.Events[1].Seq)
+
+	pending := se.PendingEvents()
+	if len(pending) != 1 {
+		t.Fatalf("expected 1 pending event after the ack, got %d", len(pending))
+	}
+	if _, ok := pending[0].Event.(*proto.EntityDespawnedEvent); !ok {
+		t.Errorf("expected the despawn to stay pending, got %+v", pending[0].Event)
+	}
+}
+
+func TestOutbox_LimitDropsOldest(t *testing.T) {
+	se := NewSpatialEngine(config.Default().Engine, zap.NewNop())
+	se.EnableOutbox(2)
+
+	se.SpawnEntity("npc", 0, 0, 0, "")
+	second := se.SpawnEntity("npc", 5, 0, 0, "")
+	third := se.SpawnEntity("npc", 10, 0, 0, "")
+
+	pending := se.PendingEvents()
+	if len(pending) != 2 {
+		t.Fatalf("expected 2 pending events, got %d", len(pending))
+	}
+	for i, want := range []uint32{second, third} {
+		if spawned := pending[i].Event.(*proto.EntitySpawnedEvent); spawned.EntityId != want {
+			t.Errorf("expected pending event %d to be the spawn of %d, got %d", i, want, spawned.EntityId)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package engine
+
+import (
+	"math"
+	"time"
+
+	"github.com/akarsh-2004/aether/internal/engine/entity"
+)
+
+// tickFrame holds every entity's position at the end of one tick.
+type tickFrame struct {
+	tick      uint64
+	positions map[uint32]entity.Vector3
+}
+
+// stateHistory is a ring of the last HistoryTicks frames, so the server can
+// see the world the way a client rendering in the past saw it.
+type stateHistory struct {
+	frames []tickFrame
+}
+
+func newStateHistory(ticks int) *stateHistory {
+	return &stateHistory{frames: make([]tickFrame, ticks)}
+}
+
+func (h *stateHistory) record(tick uint64, entities []*entity.Entity) {
+	if len(h.frames) == 0 {
+		return
+	}
+
+	frame := &h.frames[tick%uint64(len(h.frames))]
+	if frame.positions == nil {
+		frame.positions = make(map[uint32]entity.Vector3, len(entities))
+	}
+	for id := range frame.positions {
+		delete(frame.positions, id)
+	}
+
+	frame.tick = tick
+	for _, ent := range entities {
+		frame.positions[ent.ID] = ent.Position
+	}
+}
+
+func (h *stateHistory) at(tick uint64) (map[uint32]entity.Vector3, bool) {
+	if len(h.frames) == 0 {
+		return nil, false
+	}
+
+	frame := h.frames[tick%uint64(len(h.frames))]
+	if frame.positions == nil || frame.tick != tick {
+		return nil, false
+	}
+	return frame.positions, true
+}
+
+// PositionAt returns where an entity was at a fractional server tick,
+// interpolated linearly between the two recorded ticks around it. It returns
+// false if the tick is older than the history or the entity did not exist.
+func (se *SpatialEngine) PositionAt(entityID uint32, tick float64) (entity.Vector3, bool) {
+	se.mu.RLock()
+	defer se.mu.RUnlock()
+
+	if tick < 0 {
+		return entity.Vector3{}, false
+	}
+
+	base := uint64(math.Floor(tick))
+	from, ok := se.history.at(base)
+	if !ok {
+		return entity.Vector3{}, false
+	}
+	start, ok := from[entityID]
+	if !ok {
+		return entity.Vector3{}, false
+	}
+
+	// Past the newest tick, or the entity left: hold the last position
+	to, ok := se.history.at(base + 1)
+	if !ok {
+		return start, true
+	}
+	end, ok := to[entityID]
+	if !ok {
+		return start, true
+	}
+
+	frac := tick - float64(base)
+	return start.Add(end.Subtract(start).Multiply(frac)), true
+}
+
+// RenderTick returns the fractional tick a client with the suggested
+// interpolation delay is displaying at server time now.
+func (se *SpatialEngine) RenderTick(now time.Time) float64 {
+	tick, started := se.tickManager.GetTickTime()
+	rate := se.tickManager.TickRate()
+	delay := time.Duration(se.config.InterpolationDelayMs) * time.Millisecond
+	if started.IsZero() {
+		return float64(tick) // not started yet
+	}
+
+	return float64(tick) + float64(now.Sub(started)-delay)/float64(rate)
+}
+
+// TickTime returns the current tick, the time it started and the tick rate.
+func (se *SpatialEngine) TickTime() (uint64, time.Time, time.Duration) {
+	tick, started := se.tickManager.GetTickTime()
+	return tick, started, se.tickManager.TickRate()
+}
+
+// InterpolationDelay is the render delay suggested to clients.
+func (se *SpatialEngine) InterpolationDelay() time.Duration {
+	return time.Duration(se.config.InterpolationDelayMs) * time.Millisecond
+}
+
+// SetClientLatency records a client's one-way delay for lag compensation.
+// Zero forgets the client.
+func (se *SpatialEngine) SetClientLatency(clientID string, oneWay time.Duration) {
+	se.mu.Lock()
+	defer se.mu.Unlock()
+
+	if oneWay <= 0 {
+		delete(se.latency, clientID)
+		return
+	}
+	se.latency[clientID] = oneWay
+}
+
+// ClientRenderTick returns the tick clientID was displaying when it sent a
+// message that arrived at now: RenderTick less the client's one-way delay.
+func (se *SpatialEngine) ClientRenderTick(clientID string, now time.Time) float64 {
+	se.mu.RLock()
+	oneWay := se.latency[clientID]
+	se.mu.RUnlock()
+
+	return se.RenderTick(now.Add(-oneWay))
+}
@@ -0,0 +1,123 @@
+package engine
+
+import (
+	"github.com/akarsh-2004/aether/internal/engine/entity"
+	"github.com/akarsh-2004/aether/internal/engine/movement"
+	"github.com/akarsh-2004/aether/proto"
+	"go.uber.org/zap"
+)
+
+// ProcessInput buffers an input command for the next tick. The command's
+// entity must currently be controlled by clientID. Commands repeated for
+// redundancy are dropped by sequence number.
+func (se *SpatialEngine) ProcessInput(clientID string, cmd *proto.InputCommand) {
+	se.mu.Lock()
+	defer se.mu.Unlock()
+
+	if !se.config.Input.Enabled {
+		se.logger.Warn("Input command while input commands are disabled", zap.String("client_id", clientID))
+		return
+	}
+
+	ent, exists := se.entityManager.GetEntity(cmd.EntityId)
+	if !exists || ent.Ghost || ent.ClientID == "" || ent.ClientID != clientID {
+		se.logger.Warn("Input command for entity the client does not control",
+			zap.String("client_id", clientID),
+			zap.Uint32("entity_id", cmd.EntityId),
+		)
+		return
+	}
+
+	queue := se.inputs[cmd.EntityId]
+	if cmd.Sequence <= ent.LastSequence || (len(queue) > 0 && cmd.Sequence <= queue[len(queue)-1].Sequence) {
+		return
+	}
+	if len(queue) >= se.config.Input.MaxBuffered {
+		se.logger.Debug("Input buffer full, dropping command",
+			zap.Uint32("entity_id", cmd.EntityId),
+			zap.Uint64("sequence", cmd.Sequence),
+		)
+		return
+	}
+
+	se.inputs[cmd.EntityId] = append(queue, cmd)
+}
+
+// processInputs applies each buffered command once, up to MaxPerTick per
+// entity, and acknowledges the last one to the controlling client. An entity
+// without commands stands still, so its state depends on its inputs alone.
+func (se *SpatialEngine) processInputs(tickNumber uint64) {
+	for entityID, queue := range se.inputs {
+		ent, exists := se.entityManager.GetEntity(entityID)
+		if !exists || ent.Ghost {
+			delete(se.inputs, entityID)
+			continue
+		}
+
+		applied := queue
+		if len(applied) > se.config.Input.MaxPerTick {
+			applied = applied[:se.config.Input.MaxPerTick]
+		}
+
+		state := movement.State{Position: ent.Position, Velocity: ent.Velocity, Yaw: ent.Yaw}
+		for _, cmd := range applied {
+			// Clients predict with the same zone multipliers
+			params := se.movement
+			params.MoveSpeed *= se.speedMultiplier(state.Position)
+			state = movement.Step(params, state, inputFromProto(cmd), se.groundHeight)
+		}
+
+		oldPos := ent.Position
+		ent.Position = state.Position
+		ent.Velocity = state.Velocity
+		ent.Yaw = state.Yaw
+		ent.LastSequence = applied[len(applied)-1].Sequence
+		se.index.Update(ent, oldPos)
+
+		se.sendInputAck(ent, applied[len(applied)-1], tickNumber)
+
+		if len(applied) == len(queue) {
+			delete(se.inputs, entityID)
+		} else {
+			se.inputs[entityID] = queue[len(applied):]
+		}
+	}
+}
+
+// inputDriven reports whether the entity moves only through input commands.
+func (se *SpatialEngine) inputDriven(ent *entity.Entity) bool {
+	return se.config.Input.Enabled && ent.ClientID != ""
+}
+
+func inputFromProto(cmd *proto.InputCommand) movement.Input {
+	return movement.Input{
+		MoveX:   float64(cmd.MoveX),
+		MoveY:   float64(cmd.MoveY),
+		MoveZ:   float64(cmd.MoveZ),
+		Yaw:     float64(cmd.Yaw),
+		Buttons: cmd.Buttons,
+	}
+}
+
+func (se *SpatialEngine) sendInputAck(ent *entity.Entity, cmd *proto.InputCommand, tickNumber uint64) {
+	message := &proto.Message{
+		Type: proto.MessageType_INPUT_ACK,
+		Payload: &proto.Message_InputAck{
+			InputAck: &proto.InputAck{
+				EntityId:    ent.ID,
+				AckSequence: cmd.Sequence,
+				ClientTick:  cmd.ClientTick,
+				ServerTick:  tickNumber,
+				X:           float32(ent.Position.X),
+				Y:           float32(ent.Position.Y),
+				Z:           float32(ent.Position.Z),
+				VelocityX:   float32(ent.Velocity.X),
+				VelocityY:   float32(ent.Velocity.Y),
+				VelocityZ:   float32(ent.Velocity.Z),
+				Yaw:         float32(ent.Yaw),
+			},
+		},
+	}
+
+	se.queueBroadcast(ent.ClientID, message)
+}
@@ -0,0 +1,52 @@
+package engine
+
+import (
+	"errors"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrWorldFull   = errors.New("world is full")
+	ErrEntityLimit = errors.New("entity limit reached")
+	ErrClientQuota = errors.New("client entity quota reached")
+	ErrTypeQuota   = errors.New("entity type limit reached")
+)
+
+// checkSpawnLocked reports whether a new entity of entityType may spawn for
+// clientID, which is empty for entities nobody controls. Client spawns stop
+// at the soft cap; everything else may go on up to MaxEntities.
+func (se *SpatialEngine) checkSpawnLocked(entityType, clientID string, clientSpawn bool) error {
+	count := se.entityManager.GetLocalCount()
+
+	if count >= se.config.MaxEntities {
+		se.logger.Warn("Entity limit reached",
+			zap.String("entity_type", entityType),
+			zap.String("client_id", clientID),
+			zap.Int("max_entities", se.config.MaxEntities),
+		)
+		return ErrEntityLimit
+	}
+
+	if clientSpawn && count >= se.config.SoftMaxEntities() {
+		return ErrWorldFull
+	}
+
+	if limit, ok := se.config.Limits.PerType[entityType]; ok && se.entityManager.GetTypeCount(entityType) >= limit {
+		return ErrTypeQuota
+	}
+
+	return se.checkClientQuotaLocked(clientID)
+}
+
+// checkClientQuotaLocked reports whether clientID may control one more
+// entity.
+func (se *SpatialEngine) checkClientQuotaLocked(clientID string) error {
+	if clientID == "" || se.config.Limits.PerClient == 0 {
+		return nil
+	}
+	if se.entityManager.GetClientCount(clientID) >= se.config.Limits.PerClient {
+		return ErrClientQuota
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package engine
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/akarsh-2004/aether/internal/config"
+	"go.uber.org/zap"
+)
+
+func limitedEngine(limits config.LimitsConfig, maxEntities int) *SpatialEngine {
+	cfg := config.Default().Engine
+	cfg.MaxEntities = maxEntities
+	cfg.Limits = limits
+	return NewSpatialEngine(cfg, zap.NewNop())
+}
+
+// fill spawns n uncontrolled entities spread along the X axis.
+func fill(t *testing.T, se *SpatialEngine, entityType string, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		if se.SpawnEntity(entityType, float64(i*10), 0, 0, "") == 0 {
+			t.Fatalf("expected entity %d of %d to spawn", i+1, n)
+		}
+	}
+}
+
+func TestSpawnClientEntity_Limits(t *testing.T) {
+	tests := []struct {
+		name        string
+		limits      config.LimitsConfig
+		maxEntities int
+		setup       func(t *testing.T, se *SpatialEngine)
+		entityType  string
+		want        error
+	}{
+		{
+			name:        "below every limit",
+			limits:      config.LimitsConfig{SoftCapPercent: 50},
+			maxEntities: 10,
+			setup:       func(t *testing.T, se *SpatialEngine) { fill(t, se, "crate", 4) },
+			entityType:  "player",
+		},
+		{
+			name:        "soft cap",
+			limits:      config.LimitsConfig{SoftCapPercent: 50},
+			maxEntities: 10,
+			setup:       func(t *testing.T, se *SpatialEngine) { fill(t, se, "crate", 5) },
+			entityType:  "player",
+			want:        ErrWorldFull,
+		},
+		{
+			name:        "hard cap",
+			limits:      config.LimitsConfig{SoftCapPercent: 100},
+			maxEntities: 10,
+			setup:       func(t *testing.T, se *SpatialEngine) { fill(t, se, "crate", 10) },
+			entityType:  "player",
+			want:        ErrEntityLimit,
+		},
+		{
+			name:        "per type",
+			limits:      config.LimitsConfig{SoftCapPercent: 100, PerType: map[string]int{"player": 2}},
+			maxEntities: 10,
+			setup:       func(t *testing.T, se *SpatialEngine) { fill(t, se, "player", 2) },
+			entityType:  "player",
+			want:        ErrTypeQuota,
+		},
+		{
+			name:        "per type leaves other types alone",
+			limits:      config.LimitsConfig{SoftCapPercent: 100, PerType: map[string]int{"crate": 2}},
+			maxEntities: 10,
+			setup:       func(t *testing.T, se *SpatialEngine) { fill(t, se, "crate", 2) },
+			entityType:  "player",
+		},
+		{
+			name:        "per client",
+			limits:      config.LimitsConfig{SoftCapPercent: 100, PerClient: 1},
+			maxEntities: 10,
+			setup: func(t *testing.T, se *SpatialEngine) {
+				if _, err := se.SpawnClientEntity("player", "alice", SpawnOptions{}); err != nil {
+					t.Fatalf("spawn: %v", err)
+				}
+			},
+			entityType: "player",
+			want:       ErrClientQuota,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			se := limitedEngine(tt.limits, tt.maxEntities)
+			tt.setup(t, se)
+
+			entityID, err := se.SpawnClientEntity(tt.entityType, "alice", SpawnOptions{})
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("expected error %v, got %v", tt.want, err)
+			}
+			if (err == nil) != (entityID != 0) {
+				t.Errorf("expected an entity ID only on success, got %d with %v", entityID, err)
+			}
+		})
+	}
+}
+
+func TestSpawnEntity_SoftCapOnlyLimitsClients(t *testing.T) {
+	se := limitedEngine(config.LimitsConfig{SoftCapPercent: 50}, 10)
+	fill(t, se, "crate", 5)
+
+	if _, err := se.SpawnClientEntity("player", "alice", SpawnOptions{}); !errors.Is(err, ErrWorldFull) {
+		t.Fatalf("expected client spawns to stop at the soft cap, got %v", err)
+	}
+
+	// Uncontrolled entities may go on up to the hard cap
+	fill(t, se, "crate", 5)
+	if se.SpawnEntity("crate", 500, 500, 0, "") != 0 {
+		t.Error("expected no spawn past the hard cap")
+	}
+}
+
+func TestSpawnClientEntity_QuotaFreedOnRemove(t *testing.T) {
+	se := limitedEngine(config.LimitsConfig{SoftCapPercent: 100, PerClient: 1}, 10)
+
+	entityID, err := se.SpawnClientEntity("player", "alice", SpawnOptions{})
+	if err != nil {
+		t.Fatalf("spawn: %v", err)
+	}
+	se.RemoveEntity(entityID, "test")
+
+	if _, err := se.SpawnClientEntity("player", "alice", SpawnOptions{}); err != nil {
+		t.Errorf("expected the quota to be free after removal, got %v", err)
+	}
+}
@@ -0,0 +1,116 @@
+// Package movement is the deterministic movement step shared by the server
+// and client-side prediction. Step must stay free of randomness, wall-clock
+// time and map iteration so that a client replaying the same inputs from the
+// same state arrives at exactly the server's result. Clients written in other
+// languages should port it line for line, using 64-bit floats.
+package movement
+
+import (
+	"math"
+
+	"github.com/akarsh-2004/aether/internal/config"
+	"github.com/akarsh-2004/aether/internal/engine/entity"
+)
+
+// Buttons in an input command's bitmask.
+const (
+	ButtonJump   uint32 = 1 << 0
+	ButtonSprint uint32 = 1 << 1
+)
+
+// GroundEpsilon is how far above the ground an entity may be and still count
+// as standing on it.
+const GroundEpsilon = 0.01
+
+// Params are the movement rules. Clients receive the same values from the
+// server configuration.
+type Params struct {
+	MoveSpeed        float64
+	SprintMultiplier float64
+	Gravity          float64
+	JumpSpeed        float64
+	Bounds           config.Bounds
+}
+
+func ParamsFromConfig(cfg config.EngineConfig) Params {
+	return Params{
+		MoveSpeed:        cfg.Input.MoveSpeed,
+		SprintMultiplier: cfg.Input.SprintMultiplier,
+		Gravity:          cfg.Gravity,
+		JumpSpeed:        cfg.JumpSpeed,
+		Bounds:           cfg.WorldBounds,
+	}
+}
+
+// Input is one tick of player input.
+type Input struct {
+	MoveX, MoveY, MoveZ float64 // Stick axes; MoveZ only applies without gravity
+	Yaw                 float64
+	Buttons             uint32
+}
+
+// State is the part of an entity that movement changes.
+type State struct {
+	Position entity.Vector3
+	Velocity entity.Vector3
+	Yaw      float64
+}
+
+// Ground returns the ground height at a point, within the world's height
+// bounds.
+type Ground func(x, y float64) float64
+
+// Step advances s by one tick of input.
+func Step(p Params, s State, in Input, ground Ground) State {
+	move := entity.Vector3{X: finite(in.MoveX), Y: finite(in.MoveY)}
+	if p.Gravity == 0 {
+		move.Z = finite(in.MoveZ)
+	}
+	if lengthSq := move.Length3D(); lengthSq > 1 {
+		move = move.Multiply(1 / math.Sqrt(lengthSq))
+	}
+
+	speed := p.MoveSpeed
+	if in.Buttons&ButtonSprint != 0 {
+		speed *= p.SprintMultiplier
+	}
+
+	s.Yaw = finite(in.Yaw)
+	s.Velocity.X = move.X * speed
+	s.Velocity.Y = move.Y * speed
+	if p.Gravity == 0 {
+		s.Velocity.Z = move.Z * speed
+	} else if in.Buttons&ButtonJump != 0 && s.Position.Z <= ground(s.Position.X, s.Position.Y)+GroundEpsilon {
+		s.Velocity.Z = p.JumpSpeed
+	}
+
+	s.Position = s.Position.Add(s.Velocity)
+	s.Velocity.Z -= p.Gravity
+
+	s.Position.X = clamp(s.Position.X, p.Bounds.MinX, p.Bounds.MaxX)
+	s.Position.Y = clamp(s.Position.Y, p.Bounds.MinY, p.Bounds.MaxY)
+
+	if floor := ground(s.Position.X, s.Position.Y); s.Position.Z <= floor {
+		s.Position.Z = floor
+		s.Velocity.Z = math.Max(0, s.Velocity.Z)
+	}
+	if s.Position.Z >= p.Bounds.MaxZ {
+		s.Position.Z = p.Bounds.MaxZ
+		s.Velocity.Z = math.Min(0, s.Velocity.Z)
+	}
+
+	return s
+}
+
+func clamp(v, lo, hi float64) float64 {
+	return math.Max(lo, math.Min(hi, v))
+}
+
+// finite replaces NaN and infinities, which a client could send to poison
+// the simulation, with zero.
+func finite(v float64) float64 {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0
+	}
+	return v
+}
@@ -0,0 +1,226 @@
+package engine
+
+import (
+	"errors"
+	"math"
+
+	"github.com/akarsh-2004/aether/internal/engine/entity"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrUnknownProjectile = errors.New("unknown projectile type")
+	ErrFireCooldown      = errors.New("projectile is cooling down")
+	ErrProjectile        = errors.New("projectiles cannot be controlled")
+)
+
+// Reasons a projectile despawns, sent in Despawn.
+const (
+	ProjectileHit         = "hit"
+	ProjectileBlocked     = "blocked"
+	ProjectileExpired     = "expired"
+	ProjectileOutOfBounds = "out_of_bounds"
+)
+
+// projectile is the simulation state of a projectile entity. The entity
+// itself lives in the entity manager like any other, so it replicates to
+// nearby clients through the usual AOI broadcasts.
+type projectile struct {
+	kind          string
+	shooterID     uint32
+	shooterClient string
+	expiresTick   uint64
+}
+
+// FireProjectile launches a projectile of the given kind from an entity
+// clientID controls. A zero direction fires along the shooter's yaw. It
+// returns the projectile's entity ID.
+func (se *SpatialEngine) FireProjectile(clientID string, shooterID uint32, kind string, direction entity.Vector3) (uint32, error) {
+	se.mu.Lock()
+	defer se.mu.Unlock()
+
+	def, ok := se.config.Projectiles[kind]
+	if !ok {
+		return 0, ErrUnknownProjectile
+	}
+
+	shooter, exists := se.entityManager.GetEntity(shooterID)
+	if !exists || shooter.Ghost {
+		return 0, ErrEntityNotFound
+	}
+	if shooter.ClientID != clientID {
+		return 0, ErrNotController
+	}
+
+	tick := se.tickManager.GetCurrentTick()
+	if last, fired := se.cooldowns[shooterID][kind]; fired && tick < last+se.ticksFor(def.CooldownMs) {
+		return 0, ErrFireCooldown
+	}
+
+	lengthSq := direction.Length3D()
+	if lengthSq == 0 || math.IsNaN(lengthSq) || math.IsInf(lengthSq, 0) {
+		direction = entity.Vector3{X: math.Cos(shooter.Yaw), Y: math.Sin(shooter.Yaw)}
+		lengthSq = 1
+	}
+	direction = direction.Multiply(1 / math.Sqrt(lengthSq))
+
+	if err := se.checkSpawnLocked(kind, "", false); err != nil {
+		return 0, err
+	}
+
+	ent := se.entityManager.CreateEntity(kind, shooter.Position, "")
+	if ent == nil {
+		return 0, ErrEntityLimit
+	}
+	ent.Velocity = direction.Multiply(def.Speed)
+	ent.Yaw = math.Atan2(direction.Y, direction.X)
+	se.applyDefaults(ent)
+
+	if !se.index.Insert(ent) {
+		se.entityManager.RemoveEntity(ent.ID)
+		se.logger.Error("Failed to insert projectile into spatial index", zap.Uint32("entity_id", ent.ID))
+		return 0, ErrEntityNotFound
+	}
+
+	se.projectiles[ent.ID] = &projectile{
+		kind:          kind,
+		shooterID:     shooterID,
+		shooterClient: clientID,
+		expiresTick:   tick + se.ticksFor(def.TTLMs),
+	}
+	if se.cooldowns[shooterID] == nil {
+		se.cooldowns[shooterID] = make(map[string]uint64)
+	}
+	se.cooldowns[shooterID][kind] = tick
+
+	se.logger.Debug("Projectile fired",
+		zap.Uint32("entity_id", ent.ID),
+		zap.String("projectile_type", kind),
+		zap.Uint32("shooter_entity_id", shooterID),
+		zap.String("client_id", clientID),
+	)
+
+	return ent.ID, nil
+}
+
+// ticksFor converts a duration in milliseconds to whole ticks, rounding up.
+func (se *SpatialEngine) ticksFor(ms int) uint64 {
+	return uint64((ms + se.config.TickRateMs - 1) / se.config.TickRateMs)
+}
+
+// isProjectile reports whether an entity is simulated as a projectile.
+func (se *SpatialEngine) isProjectile(entityID uint32) bool {
+	_, ok := se.projectiles[entityID]
+	return ok
+}
+
+// processProjectiles moves every projectile one tick along its velocity,
+// sweeping the path so fast projectiles cannot pass through entities between
+// ticks.
+func (se *SpatialEngine) processProjectiles(tickNumber uint64) {
+	for id, p := range se.projectiles {
+		ent, exists := se.entityManager.GetEntity(id)
+		if !exists {
+			delete(se.projectiles, id)
+			continue
+		}
+
+		if tickNumber >= p.expiresTick {
+			se.removeProjectileLocked(ent, ProjectileExpired)
+			continue
+		}
+
+		def := se.config.Projectiles[p.kind]
+		from := ent.Position
+		to := from.Add(ent.Velocity)
+
+		if target, at, hit := se.sweepLocked(ent, p, from, to, def.HitRadius); hit {
+			se.hitLocked(ent, p, target, at, def.Damage)
+			continue
+		}
+
+		if !se.isPositionValid(to) {
+			se.removeProjectileLocked(ent, ProjectileOutOfBounds)
+			continue
+		}
+		if to.Z < se.groundHeight(to.X, to.Y) {
+			se.removeProjectileLocked(ent, ProjectileBlocked)
+			continue
+		}
+
+		ent.Position = to
+		se.index.Update(ent, from)
+	}
+}
+
+// sweepLocked finds the first entity within radius of the segment from..to,
+// and the point on the segment where the projectile reaches it.
+func (se *SpatialEngine) sweepLocked(proj *entity.Entity, p *projectile, from, to entity.Vector3, radius float64) (*entity.Entity, entity.Vector3, bool) {
+	path := to.Subtract(from)
+	lengthSq := path.Length3D()
+
+	// One query around the middle of the path covers the whole swept capsule
+	mid := from.Add(path.Multiply(0.5))
+	reach := math.Sqrt(lengthSq)/2 + radius
+
+	var target *entity.Entity
+	first := math.Inf(1)
+	for _, cand := range se.index.QueryRadius(mid, reach) {
+		if cand.ID == proj.ID || cand.ID == p.shooterID || cand.Ghost || se.isProjectile(cand.ID) {
+			continue
+		}
+		if se.inSafeZone(cand.Position) {
+			continue
+		}
+
+		// Closest point on the path to the candidate
+		t := 0.0
+		if lengthSq > 0 {
+			rel := cand.Position.Subtract(from)
+			t = (rel.X*path.X + rel.Y*path.Y + rel.Z*path.Z) / lengthSq
+			t = math.Max(0, math.Min(1, t))
+		}
+		closest := from.Add(path.Multiply(t))
+		if cand.Position.Distance3D(closest) <= radius*radius && t < first {
+			target, first = cand, t
+		}
+	}
+
+	if target == nil {
+		return nil, entity.Vector3{}, false
+	}
+	return target, from.Add(path.Multiply(first)), true
+}
+
+// hitLocked applies damage to target, records the hit and despawns the
+// projectile where it struck.
+func (se *SpatialEngine) hitLocked(proj *entity.Entity, p *projectile, target *entity.Entity, at entity.Vector3, damage int) {
+	if health, ok := target.Attributes["health"]; ok && health.Kind == entity.KindInt && damage > 0 {
+		health.Int -= int64(damage)
+		if health.Int < 0 {
+			health.Int = 0
+		}
+		target.SetAttribute("health", health)
+	}
+
+	proj.Position = at
+	se.publishProjectileHit(proj, p, target, damage)
+
+	se.logger.Debug("Projectile hit",
+		zap.Uint32("entity_id", proj.ID),
+		zap.Uint32("target_entity_id", target.ID),
+		zap.Int("damage", damage),
+	)
+
+	se.removeProjectileLocked(proj, ProjectileHit)
+}
+
+// removeProjectileLocked despawns a projectile, telling nearby clients why.
+// Projectiles are too short-lived to record spawn and despawn events for.
+func (se *SpatialEngine) removeProjectileLocked(ent *entity.Entity, reason string) {
+	se.broadcastDespawnToNearby(ent.ID, ent.Position, reason)
+	se.index.Remove(ent)
+	se.entityManager.RemoveEntity(ent.ID)
+	se.aoiManager.RemoveEntity(ent.ID)
+	delete(se.projectiles, ent.ID)
+}
@@ -0,0 +1,166 @@
+package engine
+
+import (
+	"time"
+
+	"github.com/akarsh-2004/aether/internal/engine/entity"
+	"go.uber.org/zap"
+)
+
+// LocalEntities returns copies of the entities this engine simulates,
+// excluding ghosts.
+func (se *SpatialEngine) LocalEntities() []entity.Entity {
+	se.mu.RLock()
+	defer se.mu.RUnlock()
+
+	entities := se.entityManager.GetAllEntities()
+	local := make([]entity.Entity, 0, len(entities))
+	for _, ent := range entities {
+		if ent.Ghost || se.isProjectile(ent.ID) {
+			continue
+		}
+		local = append(local, ent.Copy())
+	}
+
+	return local
+}
+
+// SetNextEntityID moves the entity ID counter forward, e.g. to the start of
+// a region's ID block. It never moves backwards.
+func (se *SpatialEngine) SetNextEntityID(id uint32) {
+	se.entityManager.SetNextEntityID(id)
+}
+
+// ExtractEntity removes a local entity so it can be handed off to the region
+// it moved into. It returns the entity as it was at removal.
+func (se *SpatialEngine) ExtractEntity(entityID uint32) (entity.Entity, bool) {
+	se.mu.Lock()
+	defer se.mu.Unlock()
+
+	ent, exists := se.entityManager.GetEntity(entityID)
+	if !exists || ent.Ghost {
+		return entity.Entity{}, false
+	}
+
+	extracted := ent.Copy()
+	se.removeEntityLocked(entityID, "region_handoff")
+
+	return extracted, true
+}
+
+// AdoptEntity takes over an entity handed off by another region, keeping its
+// ID. A ghost with the same ID is replaced. With awaitResume, a client-owned
+// entity waits up to ResumeGraceSec for its client to reconnect here, like a
+// restored entity.
+func (se *SpatialEngine) AdoptEntity(ent entity.Entity, awaitResume bool) bool {
+	se.mu.Lock()
+	defer se.mu.Unlock()
+
+	if existing, exists := se.entityManager.GetEntity(ent.ID); exists {
+		if !existing.Ghost {
+			se.logger.Warn("Rejecting handoff of entity that is already local", zap.Uint32("entity_id", ent.ID))
+			return false
+		}
+		se.removeGhostLocked(existing)
+	}
+
+	if !se.isPositionValid(ent.Position) {
+		se.logger.Warn("Rejecting handoff outside region bounds",
+			zap.Uint32("entity_id", ent.ID),
+			zap.Float64("x", ent.Position.X),
+			zap.Float64("y", ent.Position.Y),
+		)
+		return false
+	}
+
+	adopted := ent
+	adopted.Ghost = false
+	adopted.PendingMoves = nil
+	adopted.LastUpdate = time.Now()
+
+	if !se.entityManager.AddEntity(&adopted) {
+		return false
+	}
+
+	if !se.index.Insert(&adopted) {
+		se.entityManager.RemoveEntity(adopted.ID)
+		se.logger.Error("Failed to insert adopted entity into spatial index", zap.Uint32("entity_id", adopted.ID))
+		return false
+	}
+
+	if awaitResume && adopted.ClientID != "" {
+		se.orphans[adopted.ID] = time.Now()
+	}
+
+	se.publishSpawned(&adopted)
+
+	se.logger.Info("Entity adopted from neighbouring region",
+		zap.Uint32("entity_id", adopted.ID),
+		zap.String("client_id", adopted.ClientID),
+	)
+
+	return true
+}
+
+// UpsertGhost mirrors an entity owned by a neighbouring region so local
+// clients near the border can see it. Ghosts are never simulated, persisted
+// or published as events.
+func (se *SpatialEngine) UpsertGhost(state entity.Entity) {
+	se.mu.Lock()
+	defer se.mu.Unlock()
+
+	if !se.isPositionValid(state.Position) {
+		return
+	}
+
+	if existing, exists := se.entityManager.GetEntity(state.ID); exists {
+		if !existing.Ghost {
+			return // we own it now; the neighbour's view is stale
+		}
+
+		oldPos := existing.Position
+		existing.Position = state.Position
+		existing.Velocity = state.Velocity
+		existing.Yaw = state.Yaw
+		existing.LastUpdate = time.Now()
+		existing.ReplaceAttributes(state.Attributes)
+		se.index.Update(existing, oldPos)
+		return
+	}
+
+	ghost := &entity.Entity{
+		ID:         state.ID,
+		Type:       state.Type,
+		Position:   state.Position,
+		Velocity:   state.Velocity,
+		Yaw:        state.Yaw,
+		LastUpdate: time.Now(),
+		Ghost:      true,
+		Attributes: state.Attributes,
+	}
+
+	if !se.entityManager.AddEntity(ghost) {
+		return
+	}
+
+	if !se.index.Insert(ghost) {
+		se.entityManager.RemoveEntity(ghost.ID)
+	}
+}
+
+// RemoveGhost drops a mirrored entity that left the border band.
+func (se *SpatialEngine) RemoveGhost(entityID uint32) {
+	se.mu.Lock()
+	defer se.mu.Unlock()
+
+	if ent, exists := se.entityManager.GetEntity(entityID); exists && ent.Ghost {
+		se.removeGhostLocked(ent)
+	}
+}
+
+func (se *SpatialEngine) removeGhostLocked(ent *entity.Entity) {
+	se.index.Remove(ent)
+	se.entityManager.RemoveEntity(ent.ID)
+	se.aoiManager.RemoveEntity(ent.ID)
+	se.broadcastDespawnToNearby(ent.ID, ent.Position, "out_of_aoi")
+}
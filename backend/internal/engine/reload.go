@@ -0,0 +1,45 @@
+package engine
+
+import (
+	"time"
+
+	"github.com/akarsh-2004/aether/internal/config"
+	"go.uber.org/zap"
+)
+
+// ApplyConfig schedules a reloaded configuration. Only the settings
+// config.EngineConfig.Reloaded takes are used, and they apply at the start
+// of the next tick so a tick never runs with a mix of old and new values.
+func (se *SpatialEngine) ApplyConfig(cfg config.EngineConfig) {
+	se.mu.Lock()
+	defer se.mu.Unlock()
+
+	next := se.config.Reloaded(cfg)
+	se.pendingConfig = &next
+}
+
+// applyPendingConfig switches to the configuration scheduled by ApplyConfig,
+// if any, and passes the changes on to the tick loop and AOI manager.
+func (se *SpatialEngine) applyPendingConfig() {
+	if se.pendingConfig == nil {
+		return
+	}
+
+	old := se.config
+	se.config = *se.pendingConfig
+	se.pendingConfig = nil
+
+	if se.config.AOIRadius != old.AOIRadius {
+		se.aoiManager.SetRadius(se.config.AOIRadius)
+	}
+	if se.config.TickRateMs != old.TickRateMs {
+		se.tickManager.SetTickRate(time.Duration(se.config.TickRateMs) * time.Millisecond)
+	}
+
+	se.logger.Info("Engine configuration applied",
+		zap.Int("tick_rate_ms", se.config.TickRateMs),
+		zap.Int("max_entities", se.config.MaxEntities),
+		zap.Float64("max_speed", se.config.MaxSpeed),
+		zap.Float64("aoi_radius", se.config.AOIRadius),
+	)
+}
@@ -0,0 +1,62 @@
+package engine
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"github.com/akarsh-2004/aether/internal/engine/entity"
+)
+
+var ErrNoAvatar = errors.New("client has no avatar")
+
+// resumeTokenBytes is the size of the random part of a resume token.
+const resumeTokenBytes = 32
+
+// IssueResumeToken gives clientID's avatar a new secret resume token and
+// returns it. Only the SHA-256 of the token is kept, on the avatar, so it
+// travels with snapshots and region handoffs. Earlier tokens stop working.
+func (se *SpatialEngine) IssueResumeToken(clientID string) (string, error) {
+	raw := make([]byte, resumeTokenBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("failed to generate resume token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	se.mu.Lock()
+	defer se.mu.Unlock()
+
+	avatar := avatarOf(se.entityManager.GetEntitiesByClient(clientID))
+	if avatar == nil {
+		return "", ErrNoAvatar
+	}
+	avatar.ResumeHash = hashResumeToken(token)
+	return token, nil
+}
+
+// avatarOf returns the avatar among a client's entities, or nil.
+func avatarOf(owned []*entity.Entity) *entity.Entity {
+	for _, ent := range owned {
+		if ent.Avatar {
+			return ent
+		}
+	}
+	return nil
+}
+
+func hashResumeToken(token string) []byte {
+	sum := sha256.Sum256([]byte(token))
+	return sum[:]
+}
+
+// validResumeToken compares in constant time so the stored hash can't be
+// probed byte by byte.
+func validResumeToken(avatar *entity.Entity, token string) bool {
+	if token == "" || len(avatar.ResumeHash) == 0 {
+		return false
+	}
+	return subtle.ConstantTimeCompare(hashResumeToken(token), avatar.ResumeHash) == 1
+}
@@ -0,0 +1,156 @@
+package engine
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	"github.com/akarsh-2004/aether/internal/engine/entity"
+	"github.com/akarsh-2004/aether/proto"
+)
+
+var ErrScriptingDisabled = errors.New("scripting is not enabled")
+
+// ScriptHost runs game logic scripts. Hooks are called with the engine lock
+// held, so they must not call the engine's exported methods; the ScriptAPI
+// they receive is the way back into the world, and is only valid until the
+// hook returns.
+type ScriptHost interface {
+	OnSpawn(api *ScriptAPI, entityID uint32)
+	OnTick(api *ScriptAPI, tick uint64)
+	OnEnter(api *ScriptAPI, entityID, otherID uint32) // otherID came within AOI range of entityID
+	OnExit(api *ScriptAPI, entityID, otherID uint32)
+	OnMessage(api *ScriptAPI, clientID string, entityID uint32, name, payload string)
+	OnZoneEnter(api *ScriptAPI, entityID uint32, zone string)
+	OnZoneExit(api *ScriptAPI, entityID uint32, zone string)
+}
+
+// SetScriptHost installs the game logic scripts. It should be called before
+// Start.
+func (se *SpatialEngine) SetScriptHost(host ScriptHost) {
+	se.mu.Lock()
+	defer se.mu.Unlock()
+
+	se.scripts = host
+}
+
+// HandleScriptMessage passes a client's custom message to the scripts. The
+// entity must be controlled by clientID.
+func (se *SpatialEngine) HandleScriptMessage(clientID string, entityID uint32, name, payload string) error {
+	se.mu.Lock()
+	defer se.mu.Unlock()
+
+	if se.scripts == nil {
+		return ErrScriptingDisabled
+	}
+
+	ent, exists := se.entityManager.GetEntity(entityID)
+	if !exists || ent.Ghost {
+		return ErrEntityNotFound
+	}
+	if ent.ClientID != clientID {
+		return ErrNotController
+	}
+
+	se.scripts.OnMessage(se.scriptAPI, clientID, entityID, name, payload)
+	return nil
+}
+
+// ScriptAPI is the view of the world given to scripts. Its methods assume
+// the engine lock is held by the hook that received it.
+type ScriptAPI struct {
+	se *SpatialEngine
+}
+
+// Tick returns the tick being simulated.
+func (api *ScriptAPI) Tick() uint64 {
+	return api.se.tickManager.GetCurrentTick()
+}
+
+// Entity returns a copy of an entity's state.
+func (api *ScriptAPI) Entity(entityID uint32) (entity.Entity, bool) {
+	ent, exists := api.se.entityManager.GetEntity(entityID)
+	if !exists {
+		return entity.Entity{}, false
+	}
+	return ent.Copy(), true
+}
+
+// QueryRadius returns the IDs of local entities within radius of center,
+// in ID order so scripts behave the same from run to run.
+func (api *ScriptAPI) QueryRadius(center entity.Vector3, radius float64) []uint32 {
+	found := api.se.index.QueryRadius(center, radius)
+	ids := make([]uint32, 0, len(found))
+	for _, ent := range found {
+		if ent.Ghost || ent.Position.Distance3D(center) > radius*radius {
+			continue
+		}
+		ids = append(ids, ent.ID)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// ZonesAt returns the names of the zones containing position.
+func (api *ScriptAPI) ZonesAt(position entity.Vector3) []string {
+	return api.se.ZonesAt(position)
+}
+
+// SetPosition moves a local entity. It returns false if the entity does not
+// exist or the position is outside the world.
+func (api *ScriptAPI) SetPosition(entityID uint32, position entity.Vector3) bool {
+	ent, exists := api.se.entityManager.GetEntity(entityID)
+	if !exists || ent.Ghost || !api.se.isPositionValid(position) {
+		return false
+	}
+
+	oldPos := ent.Position
+	ent.Position = position
+	api.se.index.Update(ent, oldPos)
+	return true
+}
+
+// SetVelocity changes a local entity's velocity per tick.
+func (api *ScriptAPI) SetVelocity(entityID uint32, velocity entity.Vector3) bool {
+	ent, exists := api.se.entityManager.GetEntity(entityID)
+	if !exists || ent.Ghost {
+		return false
+	}
+	if math.IsNaN(velocity.Length3D()) || math.IsInf(velocity.Length3D(), 0) {
+		return false
+	}
+
+	ent.Velocity = velocity
+	return true
+}
+
+// SetAttribute changes one attribute, checked against the entity type's
+// schema.
+func (api *ScriptAPI) SetAttribute(entityID uint32, name string, v interface{}) error {
+	return api.se.setAttributesLocked(entityID, map[string]interface{}{name: v})
+}
+
+// Spawn creates an entity nobody controls. It returns 0 if the position is
+// outside the world or a limit is reached.
+func (api *ScriptAPI) Spawn(entityType string, position entity.Vector3) uint32 {
+	entityID, _ := api.se.spawnEntityLocked(entityType, position.X, position.Y, position.Z, "")
+	return entityID
+}
+
+// Despawn removes an entity, recording reason in the despawn event.
+func (api *ScriptAPI) Despawn(entityID uint32, reason string) bool {
+	return api.se.removeEntityLocked(entityID, reason)
+}
+
+// Send delivers a custom message to a client.
+func (api *ScriptAPI) Send(clientID, name, payload string) {
+	api.se.queueBroadcast(clientID, &proto.Message{
+		Type: proto.MessageType_SCRIPT_MESSAGE,
+		Payload: &proto.Message_ScriptMessage{
+			ScriptMessage: &proto.ScriptMessage{
+				Name:    name,
+				Payload: payload,
+			},
+		},
+	})
+}
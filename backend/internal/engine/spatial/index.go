@@ -0,0 +1,30 @@
+package spatial
+
+import (
+	"github.com/akarsh-2004/aether/internal/config"
+	"github.com/akarsh-2004/aether/internal/engine/entity"
+)
+
+// Index finds entities near a point.
+type Index interface {
+	Insert(ent *entity.Entity) bool
+	Remove(ent *entity.Entity) bool
+	Update(ent *entity.Entity, oldPos entity.Vector3) bool
+	QueryRadius(center entity.Vector3, radius float64) []*entity.Entity
+	Clear()
+	GetStats() TreeStats
+}
+
+// NewIndexFromConfig builds the index named by cfg.SpatialIndex. Flat worlds
+// always get a quadtree, as an octree over zero height could hold nothing.
+func NewIndexFromConfig(cfg config.EngineConfig) Index {
+	if cfg.SpatialIndex == "octree" && cfg.WorldBounds.MaxZ > cfg.WorldBounds.MinZ {
+		return NewOctreeFromConfig(cfg)
+	}
+	return NewQuadtreeFromConfig(cfg)
+}
+
+var (
+	_ Index = (*Quadtree)(nil)
+	_ Index = (*Octree)(nil)
+)
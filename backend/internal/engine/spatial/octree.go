@@ -0,0 +1,228 @@
+package spatial
+
+import (
+	"math"
+	"sync"
+
+	"github.com/akarsh-2004/aether/internal/config"
+	"github.com/akarsh-2004/aether/internal/engine/entity"
+)
+
+// Octree indexes entities in three dimensions, so radius queries select a
+// sphere. Use it for worlds where height separates entities, such as
+// multi-storey buildings or flying units.
+type Octree struct {
+	bounds   Box
+	capacity int
+	depth    int
+	maxDepth int
+	entities []*entity.Entity
+	children [8]*Octree
+	divided  bool
+	mu       sync.RWMutex
+}
+
+// Box is an axis-aligned box with its minimum corner at X, Y, Z.
+type Box struct {
+	X, Y, Z              float64
+	Width, Height, Depth float64
+}
+
+func NewOctree(bounds Box, capacity, depth, maxDepth int) *Octree {
+	return &Octree{
+		bounds:   bounds,
+		capacity: capacity,
+		depth:    depth,
+		maxDepth: maxDepth,
+		entities: make([]*entity.Entity, 0, capacity),
+	}
+}
+
+func NewOctreeFromConfig(cfg config.EngineConfig) *Octree {
+	bounds := Box{
+		X:      cfg.WorldBounds.MinX,
+		Y:      cfg.WorldBounds.MinY,
+		Z:      cfg.WorldBounds.MinZ,
+		Width:  cfg.WorldBounds.MaxX - cfg.WorldBounds.MinX,
+		Height: cfg.WorldBounds.MaxY - cfg.WorldBounds.MinY,
+		Depth:  cfg.WorldBounds.MaxZ - cfg.WorldBounds.MinZ,
+	}
+	return NewOctree(bounds, cfg.QuadtreeCapacity, 0, cfg.QuadtreeDepth)
+}
+
+func (ot *Octree) Insert(ent *entity.Entity) bool {
+	if !ot.bounds.Contains(ent.Position) {
+		return false
+	}
+
+	ot.mu.Lock()
+	defer ot.mu.Unlock()
+
+	return ot.insertLocked(ent)
+}
+
+func (ot *Octree) insertLocked(ent *entity.Entity) bool {
+	// Once divided, entities only live in the children
+	if !ot.divided && (len(ot.entities) < ot.capacity || ot.depth >= ot.maxDepth) {
+		ot.entities = append(ot.entities, ent)
+		return true
+	}
+
+	if !ot.divided {
+		ot.subdivideLocked()
+	}
+
+	return ot.insertIntoChildren(ent)
+}
+
+func (ot *Octree) insertIntoChildren(ent *entity.Entity) bool {
+	for _, child := range ot.children {
+		if child.Insert(ent) {
+			return true
+		}
+	}
+	return false
+}
+
+func (ot *Octree) Remove(ent *entity.Entity) bool {
+	ot.mu.Lock()
+	defer ot.mu.Unlock()
+
+	return ot.removeInternal(ent)
+}
+
+func (ot *Octree) removeInternal(ent *entity.Entity) bool {
+	for i, e := range ot.entities {
+		if e.ID == ent.ID {
+			ot.entities = append(ot.entities[:i], ot.entities[i+1:]...)
+			return true
+		}
+	}
+
+	if ot.divided {
+		for _, child := range ot.children {
+			if child.removeInternal(ent) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (ot *Octree) Update(ent *entity.Entity, oldPos entity.Vector3) bool {
+	ot.Remove(ent)
+	return ot.Insert(ent)
+}
+
+func (ot *Octree) QueryRadius(center entity.Vector3, radius float64) []*entity.Entity {
+	var results []*entity.Entity
+	radiusSq := radius * radius
+
+	ot.mu.RLock()
+	defer ot.mu.RUnlock()
+
+	ot.queryRadiusInternal(center, radiusSq, &results)
+	return results
+}
+
+func (ot *Octree) queryRadiusInternal(center entity.Vector3, radiusSq float64, results *[]*entity.Entity) {
+	for _, ent := range ot.entities {
+		if ent.Position.Distance3D(center) <= radiusSq {
+			*results = append(*results, ent)
+		}
+	}
+
+	if ot.divided {
+		for _, child := range ot.children {
+			if child.bounds.IntersectsSphere(center, radiusSq) {
+				child.queryRadiusInternal(center, radiusSq, results)
+			}
+		}
+	}
+}
+
+func (ot *Octree) Clear() {
+	ot.mu.Lock()
+	defer ot.mu.Unlock()
+
+	ot.entities = ot.entities[:0]
+	if ot.divided {
+		for _, child := range ot.children {
+			child.Clear()
+		}
+		ot.divided = false
+		ot.children = [8]*Octree{}
+	}
+}
+
+func (ot *Octree) GetStats() TreeStats {
+	ot.mu.RLock()
+	defer ot.mu.RUnlock()
+
+	stats := TreeStats{
+		Depth:         ot.depth,
+		Entities:      len(ot.entities),
+		TotalEntities: len(ot.entities),
+		Divided:       ot.divided,
+	}
+
+	if ot.divided {
+		for _, child := range ot.children {
+			stats.TotalEntities += child.GetStats().TotalEntities
+			stats.ChildCount++
+		}
+	}
+
+	return stats
+}
+
+func (ot *Octree) subdivideLocked() {
+	w := ot.bounds.Width / 2
+	h := ot.bounds.Height / 2
+	d := ot.bounds.Depth / 2
+
+	for i := range ot.children {
+		child := Box{X: ot.bounds.X, Y: ot.bounds.Y, Z: ot.bounds.Z, Width: w, Height: h, Depth: d}
+		if i&1 != 0 {
+			child.X += w
+		}
+		if i&2 != 0 {
+			child.Y += h
+		}
+		if i&4 != 0 {
+			child.Z += d
+		}
+		ot.children[i] = NewOctree(child, ot.capacity, ot.depth+1, ot.maxDepth)
+	}
+
+	ot.divided = true
+
+	// Re-insert existing entities into children
+	for _, ent := range ot.entities {
+		ot.insertIntoChildren(ent)
+	}
+
+	ot.entities = ot.entities[:0]
+}
+
+// Contains reports whether point lies inside the box. Points on the far faces
+// count as inside, so entities standing exactly on the floor or ceiling of a
+// world can be indexed.
+func (b Box) Contains(point entity.Vector3) bool {
+	return inSpan(point.X, b.X, b.Width) &&
+		inSpan(point.Y, b.Y, b.Height) &&
+		inSpan(point.Z, b.Z, b.Depth)
+}
+
+func inSpan(v, lo, size float64) bool {
+	return v >= lo && v < math.Nextafter(lo+size, math.Inf(1))
+}
+
+func (b Box) IntersectsSphere(center entity.Vector3, radiusSq float64) bool {
+	dx := center.X - max(b.X, min(center.X, b.X+b.Width))
+	dy := center.Y - max(b.Y, min(center.Y, b.Y+b.Height))
+	dz := center.Z - max(b.Z, min(center.Z, b.Z+b.Depth))
+
+	return dx*dx+dy*dy+dz*dz <= radiusSq
+}
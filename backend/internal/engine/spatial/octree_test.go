@@ -0,0 +1,157 @@
+package spatial
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/akarsh-2004/aether/internal/config"
+	"github.com/akarsh-2004/aether/internal/engine/entity"
+)
+
+func testOctree() *Octree {
+	// Capacity 2 forces subdivision after a few inserts
+	return NewOctree(Box{X: -100, Y: -100, Z: 0, Width: 200, Height: 200, Depth: 100}, 2, 0, 6)
+}
+
+func ids(entities []*entity.Entity) []uint32 {
+	result := make([]uint32, 0, len(entities))
+	for _, ent := range entities {
+		result = append(result, ent.ID)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i] < result[j] })
+	return result
+}
+
+func equalIDs(a, b []uint32) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestOctree_QueryRadius(t *testing.T) {
+	ot := testOctree()
+	entities := []*entity.Entity{
+		{ID: 1, Position: entity.Vector3{X: 0, Y: 0, Z: 0}},
+		{ID: 2, Position: entity.Vector3{X: 5, Y: 0, Z: 0}},
+		{ID: 3, Position: entity.Vector3{X: 0, Y: 0, Z: 50}}, // straight above 1
+		{ID: 4, Position: entity.Vector3{X: -60, Y: 60, Z: 10}},
+		{ID: 5, Position: entity.Vector3{X: 100, Y: 100, Z: 100}}, // on the far corner
+		{ID: 6, Position: entity.Vector3{X: 3, Y: 4, Z: 0}},
+	}
+	for _, ent := range entities {
+		if !ot.Insert(ent) {
+			t.Fatalf("expected entity %d to be inserted", ent.ID)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		center entity.Vector3
+		radius float64
+		want   []uint32
+	}{
+		{"sphere excludes entities above", entity.Vector3{}, 10, []uint32{1, 2, 6}},
+		{"boundary is inclusive", entity.Vector3{}, 5, []uint32{1, 2, 6}},
+		{"height within radius", entity.Vector3{Z: 45}, 10, []uint32{3}},
+		{"across children", entity.Vector3{X: -30, Y: 30, Z: 5}, 60, []uint32{1, 2, 4, 6}},
+		{"far corner", entity.Vector3{X: 99, Y: 99, Z: 99}, 2, []uint32{5}},
+		{"empty region", entity.Vector3{X: 50, Y: -50, Z: 50}, 10, []uint32{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ids(ot.QueryRadius(tt.center, tt.radius)); !equalIDs(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	if stats := ot.GetStats(); !stats.Divided || stats.TotalEntities != len(entities) {
+		t.Errorf("expected a divided tree holding %d entities, got %+v", len(entities), stats)
+	}
+}
+
+func TestOctree_InsertOutsideBounds(t *testing.T) {
+	ot := testOctree()
+
+	for _, pos := range []entity.Vector3{
+		{X: 101, Y: 0, Z: 0},
+		{X: 0, Y: 0, Z: -1},
+		{X: 0, Y: 0, Z: 101},
+	} {
+		if ot.Insert(&entity.Entity{ID: 1, Position: pos}) {
+			t.Errorf("expected %+v to be rejected", pos)
+		}
+	}
+}
+
+func TestOctree_RemoveAndUpdate(t *testing.T) {
+	ot := testOctree()
+	entities := make([]*entity.Entity, 0, 8)
+	for i := uint32(1); i <= 8; i++ {
+		ent := &entity.Entity{ID: i, Position: entity.Vector3{X: float64(i), Y: float64(i), Z: float64(i)}}
+		ot.Insert(ent)
+		entities = append(entities, ent)
+	}
+
+	if !ot.Remove(entities[2]) {
+		t.Fatal("expected entity 3 to be removed")
+	}
+	if ot.Remove(entities[2]) {
+		t.Error("expected a second removal to fail")
+	}
+	if got := ids(ot.QueryRadius(entity.Vector3{}, 100)); !equalIDs(got, []uint32{1, 2, 4, 5, 6, 7, 8}) {
+		t.Errorf("expected entity 3 to be gone, got %v", got)
+	}
+
+	// Move entity 1 high above the others
+	old := entities[0].Position
+	entities[0].Position = entity.Vector3{X: 1, Y: 1, Z: 90}
+	if !ot.Update(entities[0], old) {
+		t.Fatal("expected the update to succeed")
+	}
+	if got := ids(ot.QueryRadius(entity.Vector3{X: 1, Y: 1, Z: 90}, 1)); !equalIDs(got, []uint32{1}) {
+		t.Errorf("expected entity 1 at its new height, got %v", got)
+	}
+	if got := ids(ot.QueryRadius(old, 1)); len(got) != 0 {
+		t.Errorf("expected nothing left at the old position, got %v", got)
+	}
+
+	ot.Clear()
+	if stats := ot.GetStats(); stats.Divided || stats.TotalEntities != 0 {
+		t.Errorf("expected an empty tree after Clear, got %+v", stats)
+	}
+}
+
+func TestNewIndexFromConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		index  string
+		minZ   float64
+		maxZ   float64
+		octree bool
+	}{
+		{"quadtree by default", "quadtree", 0, 500, false},
+		{"octree when asked", "octree", 0, 500, true},
+		{"flat worlds fall back to the quadtree", "octree", 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Default().Engine
+			cfg.SpatialIndex = tt.index
+			cfg.WorldBounds.MinZ, cfg.WorldBounds.MaxZ = tt.minZ, tt.maxZ
+
+			_, isOctree := NewIndexFromConfig(cfg).(*Octree)
+			if isOctree != tt.octree {
+				t.Errorf("expected octree %v, got %T", tt.octree, NewIndexFromConfig(cfg))
+			}
+		})
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"github.com/akarsh-2004/aether/internal/engine/entity"
 )
 
+// Quadtree indexes entities on the ground plane. Height is ignored, so radius
+// queries select a vertical cylinder (2.5D).
 type Quadtree struct {
 	bounds     Rectangle
 	capacity   int
@@ -93,7 +95,7 @@ func (qt *Quadtree) removeInternal(ent *entity.Entity) bool {
 	return false
 }
 
-func (qt *Quadtree) Update(ent *entity.Entity, oldPos entity.Vector2) bool {
+func (qt *Quadtree) Update(ent *entity.Entity, oldPos entity.Vector3) bool {
 	// Remove from old position
 	qt.Remove(ent)
 	
@@ -101,7 +103,7 @@ func (qt *Quadtree) Update(ent *entity.Entity, oldPos entity.Vector2) bool {
 	return qt.Insert(ent)
 }
 
-func (qt *Quadtree) QueryRadius(center entity.Vector2, radius float64) []*entity.Entity {
+func (qt *Quadtree) QueryRadius(center entity.Vector3, radius float64) []*entity.Entity {
 	var results []*entity.Entity
 	radiusSq := radius * radius
 
@@ -112,7 +114,7 @@ func (qt *Quadtree) QueryRadius(center entity.Vector2, radius float64) []*entity
 	return results
 }
 
-func (qt *Quadtree) queryRadiusInternal(center entity.Vector2, radiusSq float64, results *[]*entity.Entity) {
+func (qt *Quadtree) queryRadiusInternal(center entity.Vector3, radiusSq float64, results *[]*entity.Entity) {
 	// Check entities at this level
 	for _, ent := range qt.entities {
 		distSq := ent.Position.Distance(center)
@@ -173,11 +175,11 @@ func (qt *Quadtree) Clear() {
 	}
 }
 
-func (qt *Quadtree) GetStats() QuadtreeStats {
+func (qt *Quadtree) GetStats() TreeStats {
 	qt.mu.RLock()
 	defer qt.mu.RUnlock()
 
-	stats := QuadtreeStats{
+	stats := TreeStats{
 		Depth:    qt.depth,
 		Entities: len(qt.entities),
 		Divided:  qt.divided,
@@ -196,7 +198,8 @@ func (qt *Quadtree) GetStats() QuadtreeStats {
 	return stats
 }
 
-type QuadtreeStats struct {
+// TreeStats describes the root node of a quadtree or octree.
+type TreeStats struct {
 	Depth         int
 	Entities      int
 	TotalEntities int
@@ -228,7 +231,7 @@ func (qt *Quadtree) subdivide() {
 	qt.entities = qt.entities[:0] // Clear current level entities
 }
 
-func (r Rectangle) Contains(point entity.Vector2) bool {
+func (r Rectangle) Contains(point entity.Vector3) bool {
 	return point.X >= r.X &&
 		point.X < r.X+r.Width &&
 		point.Y >= r.Y &&
@@ -242,7 +245,7 @@ func (r Rectangle) Intersects(other Rectangle) bool {
 		other.Y >= r.Y+r.Height)
 }
 
-func (r Rectangle) IntersectsCircle(center entity.Vector2, radiusSq float64) bool {
+func (r Rectangle) IntersectsCircle(center entity.Vector3, radiusSq float64) bool {
 	// Find the closest point on the rectangle to the circle center
 	closestX := max(r.X, min(center.X, r.X+r.Width))
 	closestY := max(r.Y, min(center.Y, r.Y+r.Height))
@@ -0,0 +1,207 @@
+package spatial
+
+import (
+	"math"
+	"sort"
+
+	"github.com/akarsh-2004/aether/internal/config"
+	"github.com/akarsh-2004/aether/internal/engine/entity"
+)
+
+// Zone is a named area of the world: a rectangle or a polygon in the XY
+// plane, spanning the world's full height.
+type Zone struct {
+	Name            string
+	Safe            bool    // Projectiles do not hit entities inside
+	SpeedMultiplier float64 // Scales horizontal movement inside; 1 leaves it unchanged
+	corners         []entity.Vector3
+	bounds          Box
+}
+
+func NewZone(cfg config.ZoneConfig) *Zone {
+	z := &Zone{
+		Name:            cfg.Name,
+		Safe:            cfg.Safe,
+		SpeedMultiplier: cfg.SpeedMultiplier,
+	}
+	if z.SpeedMultiplier == 0 {
+		z.SpeedMultiplier = 1
+	}
+
+	if len(cfg.Points) == 0 {
+		z.corners = []entity.Vector3{
+			{X: cfg.MinX, Y: cfg.MinY},
+			{X: cfg.MaxX, Y: cfg.MinY},
+			{X: cfg.MaxX, Y: cfg.MaxY},
+			{X: cfg.MinX, Y: cfg.MaxY},
+		}
+	} else {
+		for _, p := range cfg.Points {
+			z.corners = append(z.corners, entity.Vector3{X: p.X, Y: p.Y})
+		}
+	}
+
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, c := range z.corners {
+		minX, maxX = math.Min(minX, c.X), math.Max(maxX, c.X)
+		minY, maxY = math.Min(minY, c.Y), math.Max(maxY, c.Y)
+	}
+	z.bounds = Box{X: minX, Y: minY, Width: maxX - minX, Height: maxY - minY}
+	return z
+}
+
+// Contains reports whether point lies inside the zone. Height is ignored.
+func (z *Zone) Contains(point entity.Vector3) bool {
+	if !z.bounds.Contains(entity.Vector3{X: point.X, Y: point.Y}) {
+		return false
+	}
+
+	// Even-odd rule: count the edges a ray towards +X crosses
+	inside := false
+	for i, j := 0, len(z.corners)-1; i < len(z.corners); j, i = i, i+1 {
+		a, b := z.corners[i], z.corners[j]
+		if (a.Y > point.Y) != (b.Y > point.Y) &&
+			point.X < (b.X-a.X)*(point.Y-a.Y)/(b.Y-a.Y)+a.X {
+			inside = !inside
+		}
+	}
+	return inside || z.onEdge(point)
+}
+
+// Bounds returns the zone's bounding box in the XY plane.
+func (z *Zone) Bounds() Box {
+	return z.bounds
+}
+
+// onEdge keeps points exactly on the boundary, such as the far sides of a
+// rectangle, inside the zone.
+func (z *Zone) onEdge(point entity.Vector3) bool {
+	return z.edgeDistance(point) == 0
+}
+
+// IntersectsCircle reports whether any part of the zone lies within radius
+// of center in the XY plane.
+func (z *Zone) IntersectsCircle(center entity.Vector3, radius float64) bool {
+	flat := entity.Vector3{X: center.X, Y: center.Y}
+	if !z.bounds.IntersectsSphere(flat, radius*radius) {
+		return false
+	}
+	return z.Contains(flat) || z.edgeDistance(flat) <= radius*radius
+}
+
+// edgeDistance returns the squared distance from point to the zone's
+// nearest edge.
+func (z *Zone) edgeDistance(point entity.Vector3) float64 {
+	best := math.Inf(1)
+	for i, j := 0, len(z.corners)-1; i < len(z.corners); j, i = i, i+1 {
+		a, b := z.corners[j], z.corners[i]
+		edge := b.Subtract(a)
+		t := 0.0
+		if lengthSq := edge.Length(); lengthSq > 0 {
+			rel := point.Subtract(a)
+			t = math.Max(0, math.Min(1, (rel.X*edge.X+rel.Y*edge.Y)/lengthSq))
+		}
+		best = math.Min(best, point.Distance(a.Add(edge.Multiply(t))))
+	}
+	return best
+}
+
+// ZoneIndex finds zones by position. Zones are bucketed into a grid by their
+// bounding boxes, so a lookup only tests the zones near the point. It is
+// immutable once built and safe for concurrent use.
+type ZoneIndex struct {
+	cellSize float64
+	cells    map[[2]int][]*Zone
+	byName   map[string]*Zone
+}
+
+// NewZoneIndex indexes zones in cells of cellSize units.
+func NewZoneIndex(zones []config.ZoneConfig, cellSize float64) *ZoneIndex {
+	zi := &ZoneIndex{
+		cellSize: cellSize,
+		cells:    make(map[[2]int][]*Zone),
+		byName:   make(map[string]*Zone, len(zones)),
+	}
+
+	// Cells list their zones in name order, so lookups need no sorting
+	sorted := make([]config.ZoneConfig, len(zones))
+	copy(sorted, zones)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+
+	for _, cfg := range sorted {
+		z := NewZone(cfg)
+		zi.byName[z.Name] = z
+
+		minX, minY := zi.cell(z.bounds.X, z.bounds.Y)
+		maxX, maxY := zi.cell(z.bounds.X+z.bounds.Width, z.bounds.Y+z.bounds.Height)
+		for x := minX; x <= maxX; x++ {
+			for y := minY; y <= maxY; y++ {
+				zi.cells[cellKey(x, y)] = append(zi.cells[cellKey(x, y)], z)
+			}
+		}
+	}
+	return zi
+}
+
+// NewZoneIndexFromConfig indexes cfg.Zones in cells the size of the AOI
+// radius.
+func NewZoneIndexFromConfig(cfg config.EngineConfig) *ZoneIndex {
+	return NewZoneIndex(cfg.Zones, cfg.AOIRadius)
+}
+
+func (zi *ZoneIndex) cell(x, y float64) (int, int) {
+	return int(math.Floor(x / zi.cellSize)), int(math.Floor(y / zi.cellSize))
+}
+
+// At returns the zones containing point, in name order.
+func (zi *ZoneIndex) At(point entity.Vector3) []*Zone {
+	var zones []*Zone
+	for _, z := range zi.cells[cellKey(zi.cell(point.X, point.Y))] {
+		if z.Contains(point) {
+			zones = append(zones, z)
+		}
+	}
+	return zones
+}
+
+func cellKey(x, y int) [2]int {
+	return [2]int{x, y}
+}
+
+// QueryRadius returns the zones overlapping a circle around center, in name
+// order.
+func (zi *ZoneIndex) QueryRadius(center entity.Vector3, radius float64) []*Zone {
+	minX, minY := zi.cell(center.X-radius, center.Y-radius)
+	maxX, maxY := zi.cell(center.X+radius, center.Y+radius)
+
+	seen := make(map[*Zone]bool)
+	var zones []*Zone
+	for x := minX; x <= maxX; x++ {
+		for y := minY; y <= maxY; y++ {
+			for _, z := range zi.cells[cellKey(x, y)] {
+				if seen[z] {
+					continue
+				}
+				seen[z] = true
+				if z.IntersectsCircle(center, radius) {
+					zones = append(zones, z)
+				}
+			}
+		}
+	}
+
+	sort.Slice(zones, func(i, j int) bool { return zones[i].Name < zones[j].Name })
+	return zones
+}
+
+// Get returns the zone with the given name.
+func (zi *ZoneIndex) Get(name string) (*Zone, bool) {
+	z, ok := zi.byName[name]
+	return z, ok
+}
+
+// Len returns the number of zones.
+func (zi *ZoneIndex) Len() int {
+	return len(zi.byName)
+}
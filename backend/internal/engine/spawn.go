@@ -0,0 +1,183 @@
+package engine
+
+import (
+	"errors"
+	"math"
+
+	"github.com/akarsh-2004/aether/internal/config"
+	"github.com/akarsh-2004/aether/internal/engine/entity"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrUnknownSpawnPoint = errors.New("unknown spawn point")
+	ErrClientPosition    = errors.New("spawn position is chosen by the server")
+	ErrNoSpawnPosition   = errors.New("no free spawn position")
+	ErrInvalidPosition   = errors.New("position is outside the world")
+)
+
+// SpawnOptions is what a client asked for when spawning.
+type SpawnOptions struct {
+	Point       string         // Named spawn point, empty for none
+	Position    entity.Vector3 // Client-chosen coordinates
+	UsePosition bool           // The client asks for Position; always true under the client policy
+}
+
+// SpawnClientEntity spawns an entity for clientID where the spawn policy
+// puts it. Every candidate position must be inside the world and have no
+// entity within the configured clearance. The spawn is refused with
+// ErrWorldFull once the world reaches its soft cap, and with ErrClientQuota
+// or ErrTypeQuota when a quota is used up.
+func (se *SpatialEngine) SpawnClientEntity(entityType, clientID string, opts SpawnOptions) (uint32, error) {
+	se.mu.Lock()
+	defer se.mu.Unlock()
+
+	if err := se.checkSpawnLocked(entityType, clientID, true); err != nil {
+		return 0, err
+	}
+
+	candidates, farthest, err := se.spawnCandidatesLocked(opts)
+	if err != nil {
+		return 0, err
+	}
+
+	position, ok := se.pickSpawnLocked(candidates, farthest)
+	if !ok {
+		se.logger.Warn("No free spawn position",
+			zap.String("client_id", clientID),
+			zap.String("policy", se.config.Spawn.Policy),
+			zap.Int("candidates", len(candidates)),
+		)
+		return 0, ErrNoSpawnPosition
+	}
+
+	entityID, err := se.spawnEntityLocked(entityType, position.X, position.Y, position.Z, clientID)
+	if err != nil {
+		return 0, err
+	}
+	if ent, ok := se.entityManager.GetEntity(entityID); ok {
+		ent.Avatar = true
+	}
+	return entityID, nil
+}
+
+// spawnCandidatesLocked lists the positions the policy allows for opts. With
+// farthest set the caller should pick the one with the most room rather than
+// the first free one.
+func (se *SpatialEngine) spawnCandidatesLocked(opts SpawnOptions) ([]entity.Vector3, bool, error) {
+	cfg := se.config.Spawn
+
+	if opts.Point != "" {
+		for _, p := range cfg.Points {
+			if p.Name == opts.Point {
+				return se.aroundSpawnPoint(p), false, nil
+			}
+		}
+		return nil, false, ErrUnknownSpawnPoint
+	}
+
+	if cfg.Policy == "client" || opts.UsePosition {
+		if cfg.Policy != "client" && !cfg.AllowClient {
+			return nil, false, ErrClientPosition
+		}
+		return []entity.Vector3{opts.Position}, false, nil
+	}
+
+	switch cfg.Policy {
+	case "points":
+		var candidates []entity.Vector3
+		for _, p := range cfg.Points {
+			candidates = append(candidates, se.aroundSpawnPoint(p)...)
+		}
+		return candidates, true, nil
+	case "zone":
+		return se.inSpawnArea(), false, nil
+	default: // farthest
+		return se.inSpawnArea(), true, nil
+	}
+}
+
+// aroundSpawnPoint returns random positions within the point's radius.
+func (se *SpatialEngine) aroundSpawnPoint(p config.SpawnPoint) []entity.Vector3 {
+	center := entity.Vector3{X: p.X, Y: p.Y, Z: p.Z}
+	if p.Radius == 0 {
+		return []entity.Vector3{center}
+	}
+
+	candidates := make([]entity.Vector3, se.config.Spawn.Attempts)
+	for i := range candidates {
+		// sqrt keeps the spread uniform over the disc
+		angle := se.rand.Float64() * 2 * math.Pi
+		distance := p.Radius * math.Sqrt(se.rand.Float64())
+		candidates[i] = entity.Vector3{
+			X: center.X + distance*math.Cos(angle),
+			Y: center.Y + distance*math.Sin(angle),
+			Z: center.Z,
+		}
+	}
+	return candidates
+}
+
+// inSpawnArea returns random positions inside the spawn zone, or anywhere in
+// the world without one.
+func (se *SpatialEngine) inSpawnArea() []entity.Vector3 {
+	bounds := se.config.WorldBounds
+	minX, minY := bounds.MinX, bounds.MinY
+	width, height := bounds.MaxX-bounds.MinX, bounds.MaxY-bounds.MinY
+
+	zone, hasZone := se.zones.Get(se.config.Spawn.Zone)
+	if hasZone {
+		box := zone.Bounds()
+		minX, minY, width, height = box.X, box.Y, box.Width, box.Height
+	}
+
+	// Polygons fill only part of their bounding box, so allow some misses
+	attempts := se.config.Spawn.Attempts
+	candidates := make([]entity.Vector3, 0, attempts)
+	for i := 0; i < attempts*4 && len(candidates) < attempts; i++ {
+		candidate := entity.Vector3{
+			X: minX + se.rand.Float64()*width,
+			Y: minY + se.rand.Float64()*height,
+		}
+		if hasZone && !zone.Contains(candidate) {
+			continue
+		}
+		candidates = append(candidates, candidate)
+	}
+	return candidates
+}
+
+// pickSpawnLocked returns the first candidate with the required clearance,
+// or with farthest set the one farthest from any entity.
+func (se *SpatialEngine) pickSpawnLocked(candidates []entity.Vector3, farthest bool) (entity.Vector3, bool) {
+	var best entity.Vector3
+	bestRoom := -1.0
+	for _, candidate := range candidates {
+		candidate.Z = math.Max(candidate.Z, se.groundHeight(candidate.X, candidate.Y))
+		if !se.isPositionValid(candidate) {
+			continue
+		}
+
+		room := se.roomAt(candidate)
+		if room < se.config.Spawn.Clearance {
+			continue
+		}
+		if !farthest {
+			return candidate, true
+		}
+		if room > bestRoom {
+			best, bestRoom = candidate, room
+		}
+	}
+	return best, bestRoom >= 0
+}
+
+// roomAt returns the distance from position to the nearest entity, up to
+// the AOI radius.
+func (se *SpatialEngine) roomAt(position entity.Vector3) float64 {
+	room := se.config.AOIRadius
+	for _, ent := range se.index.QueryRadius(position, room) {
+		room = math.Min(room, math.Sqrt(ent.Position.Distance3D(position)))
+	}
+	return room
+}
@@ -0,0 +1,196 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/akarsh-2004/aether/internal/config"
+	"github.com/akarsh-2004/aether/internal/engine/entity"
+	"go.uber.org/zap"
+)
+
+func spawnEngine(spawn config.SpawnConfig, zones ...config.ZoneConfig) *SpatialEngine {
+	cfg := config.Default().Engine
+	if spawn.Attempts == 0 {
+		spawn.Attempts = config.Default().Engine.Spawn.Attempts
+	}
+	cfg.Spawn = spawn
+	cfg.Zones = zones
+	return NewSpatialEngine(cfg, zap.NewNop())
+}
+
+func spawnedAt(t *testing.T, se *SpatialEngine, clientID string, opts SpawnOptions) entity.Vector3 {
+	t.Helper()
+	entityID, err := se.SpawnClientEntity("player", clientID, opts)
+	if err != nil {
+		t.Fatalf("spawn: %v", err)
+	}
+	ent, _ := se.GetEntity(entityID)
+	return ent.Position
+}
+
+func TestSpawnClientEntity_Policies(t *testing.T) {
+	points := []config.SpawnPoint{
+		{Name: "west", X: -100, Y: 0},
+		{Name: "east", X: 100, Y: 0},
+	}
+
+	tests := []struct {
+		name    string
+		spawn   config.SpawnConfig
+		blocker *entity.Vector3 // an entity placed before the spawn
+		opts    SpawnOptions
+		want    entity.Vector3
+	}{
+		{
+			name:  "client uses the requested position",
+			spawn: config.SpawnConfig{Policy: "client", Clearance: 2},
+			opts:  SpawnOptions{Position: entity.Vector3{X: 40, Y: -30}},
+			want:  entity.Vector3{X: 40, Y: -30},
+		},
+		{
+			name:    "points picks the point with the most room",
+			spawn:   config.SpawnConfig{Policy: "points", Points: points, Clearance: 2},
+			blocker: &entity.Vector3{X: -95, Y: 0},
+			want:    entity.Vector3{X: 100, Y: 0},
+		},
+		{
+			name:    "named point overrides the policy",
+			spawn:   config.SpawnConfig{Policy: "farthest", Points: points, Clearance: 2},
+			blocker: &entity.Vector3{X: -95, Y: 0},
+			opts:    SpawnOptions{Point: "west"},
+			want:    entity.Vector3{X: -100, Y: 0},
+		},
+		{
+			name:  "allow_client accepts the requested position",
+			spawn: config.SpawnConfig{Policy: "farthest", AllowClient: true, Clearance: 2},
+			opts:  SpawnOptions{Position: entity.Vector3{X: 7, Y: 8}, UsePosition: true},
+			want:  entity.Vector3{X: 7, Y: 8},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			se := spawnEngine(tt.spawn)
+			if tt.blocker != nil {
+				se.SpawnEntity("crate", tt.blocker.X, tt.blocker.Y, 0, "")
+			}
+
+			if got := spawnedAt(t, se, "alice", tt.opts); got != tt.want {
+				t.Errorf("expected spawn at %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSpawnClientEntity_ZoneKeepsInside(t *testing.T) {
+	zone := config.ZoneConfig{Name: "start", MinX: 100, MinY: 100, MaxX: 200, MaxY: 150}
+
+	for _, policy := range []string{"zone", "farthest"} {
+		t.Run(policy, func(t *testing.T) {
+			se := spawnEngine(config.SpawnConfig{Policy: policy, Zone: "start", Clearance: 2}, zone)
+
+			for i := 0; i < 10; i++ {
+				pos := spawnedAt(t, se, fmt.Sprintf("client%d", i), SpawnOptions{})
+				if pos.X < zone.MinX || pos.X > zone.MaxX || pos.Y < zone.MinY || pos.Y > zone.MaxY {
+					t.Fatalf("expected spawn %d inside the zone, got %+v", i, pos)
+				}
+			}
+		})
+	}
+}
+
+func TestSpawnClientEntity_IgnoresClientPositionUnlessAllowed(t *testing.T) {
+	se := spawnEngine(config.SpawnConfig{Policy: "farthest", Clearance: 2})
+
+	requested := entity.Vector3{X: 7, Y: 8}
+	_, err := se.SpawnClientEntity("player", "alice", SpawnOptions{Position: requested, UsePosition: true})
+	if !errors.Is(err, ErrClientPosition) {
+		t.Fatalf("expected ErrClientPosition, got %v", err)
+	}
+
+	// Without use_position the coordinates are only a hint the server ignores
+	if pos := spawnedAt(t, se, "alice", SpawnOptions{Position: requested}); pos == requested {
+		t.Errorf("expected the server to choose the position, got the requested %+v", pos)
+	}
+}
+
+func TestSpawnClientEntity_Clearance(t *testing.T) {
+	points := []config.SpawnPoint{
+		{Name: "a", X: 0, Y: 0},
+		{Name: "b", X: 50, Y: 0},
+	}
+
+	tests := []struct {
+		name     string
+		spawn    config.SpawnConfig
+		blockers []entity.Vector3
+		opts     SpawnOptions
+		want     entity.Vector3
+		wantErr  error
+	}{
+		{
+			name:     "blocked point falls back to the next",
+			spawn:    config.SpawnConfig{Policy: "points", Points: points, Clearance: 5},
+			blockers: []entity.Vector3{{X: 1, Y: 0}},
+			want:     entity.Vector3{X: 50, Y: 0},
+		},
+		{
+			name:     "every point blocked",
+			spawn:    config.SpawnConfig{Policy: "points", Points: points, Clearance: 5},
+			blockers: []entity.Vector3{{X: 1, Y: 0}, {X: 51, Y: 0}},
+			wantErr:  ErrNoSpawnPosition,
+		},
+		{
+			name:     "client position blocked",
+			spawn:    config.SpawnConfig{Policy: "client", Clearance: 5},
+			blockers: []entity.Vector3{{X: 10, Y: 12}},
+			opts:     SpawnOptions{Position: entity.Vector3{X: 10, Y: 10}},
+			wantErr:  ErrNoSpawnPosition,
+		},
+		{
+			name:     "zero clearance allows stacking",
+			spawn:    config.SpawnConfig{Policy: "client"},
+			blockers: []entity.Vector3{{X: 10, Y: 10}},
+			opts:     SpawnOptions{Position: entity.Vector3{X: 10, Y: 10}},
+			want:     entity.Vector3{X: 10, Y: 10},
+		},
+		{
+			name:    "client position outside the world",
+			spawn:   config.SpawnConfig{Policy: "client"},
+			opts:    SpawnOptions{Position: entity.Vector3{X: 5000, Y: 0}},
+			wantErr: ErrNoSpawnPosition,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			se := spawnEngine(tt.spawn)
+			for _, b := range tt.blockers {
+				if se.SpawnEntity("crate", b.X, b.Y, b.Z, "") == 0 {
+					t.Fatalf("expected blocker at %+v to spawn", b)
+				}
+			}
+
+			entityID, err := se.SpawnClientEntity("player", "alice", tt.opts)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if ent, _ := se.GetEntity(entityID); ent.Position != tt.want {
+				t.Errorf("expected spawn at %+v, got %+v", tt.want, ent.Position)
+			}
+		})
+	}
+}
+
+func TestSpawnClientEntity_UnknownPoint(t *testing.T) {
+	se := spawnEngine(config.SpawnConfig{Policy: "farthest", Clearance: 2})
+
+	if _, err := se.SpawnClientEntity("player", "alice", SpawnOptions{Point: "nowhere"}); !errors.Is(err, ErrUnknownSpawnPoint) {
+		t.Errorf("expected ErrUnknownSpawnPoint, got %v", err)
+	}
+}
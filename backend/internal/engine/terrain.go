@@ -0,0 +1,76 @@
+package engine
+
+import (
+	"math"
+
+	"github.com/akarsh-2004/aether/internal/engine/entity"
+	"github.com/akarsh-2004/aether/internal/engine/movement"
+	"github.com/akarsh-2004/aether/proto"
+)
+
+// Terrain returns the ground height at a point on the plane.
+type Terrain func(x, y float64) float64
+
+// SetTerrain installs the ground height function. Without one the ground is
+// flat at WorldBounds.MinZ. It should be called before Start.
+func (se *SpatialEngine) SetTerrain(terrain Terrain) {
+	se.mu.Lock()
+	defer se.mu.Unlock()
+
+	se.terrain = terrain
+}
+
+// GroundHeight returns the authoritative ground height at (x, y).
+func (se *SpatialEngine) GroundHeight(x, y float64) float64 {
+	se.mu.RLock()
+	defer se.mu.RUnlock()
+
+	return se.groundHeight(x, y)
+}
+
+func (se *SpatialEngine) groundHeight(x, y float64) float64 {
+	bounds := se.config.WorldBounds
+	if se.terrain == nil {
+		return bounds.MinZ
+	}
+	return math.Max(bounds.MinZ, math.Min(bounds.MaxZ, se.terrain(x, y)))
+}
+
+func (se *SpatialEngine) grounded(ent *entity.Entity) bool {
+	return ent.Position.Z <= se.groundHeight(ent.Position.X, ent.Position.Y)+movement.GroundEpsilon
+}
+
+// applyVerticalIntent turns a delta's delta_z into vertical velocity. With
+// gravity the server owns vertical movement, so delta_z only starts a jump
+// from the ground; without it clients fly freely.
+func (se *SpatialEngine) applyVerticalIntent(ent *entity.Entity, delta *proto.MovementDelta) {
+	deltaZ := float64(delta.DeltaZ)
+	if se.config.Gravity == 0 {
+		ent.Velocity.Z = deltaZ
+		return
+	}
+
+	if deltaZ > 0 && se.grounded(ent) {
+		ent.Velocity.Z = math.Min(deltaZ, se.config.JumpSpeed)
+	}
+}
+
+// settleHeight applies gravity to an entity about to move to next and keeps
+// it between the ground and the ceiling.
+func (se *SpatialEngine) settleHeight(ent *entity.Entity, next *entity.Vector3) {
+	if se.config.Gravity > 0 {
+		ent.Velocity.Z -= se.config.Gravity
+	} else {
+		ent.Velocity.Z *= 0.95
+	}
+
+	if ground := se.groundHeight(next.X, next.Y); next.Z <= ground {
+		// Landed, or walked onto higher ground
+		next.Z = ground
+		ent.Velocity.Z = math.Max(0, ent.Velocity.Z)
+	}
+	if next.Z >= se.config.WorldBounds.MaxZ {
+		next.Z = se.config.WorldBounds.MaxZ
+		ent.Velocity.Z = math.Min(0, ent.Velocity.Z)
+	}
+}
@@ -0,0 +1,66 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/akarsh-2004/aether/internal/config"
+	"github.com/akarsh-2004/aether/internal/engine/entity"
+	"go.uber.org/zap"
+)
+
+// slope rises one unit per unit of X.
+func slope(x, y float64) float64 { return x }
+
+func TestGroundHeight(t *testing.T) {
+	tests := []struct {
+		name    string
+		terrain Terrain
+		x       float64
+		want    float64
+	}{
+		{"flat world sits on min_z", nil, 50, 0},
+		{"terrain height", slope, 50, 50},
+		{"clamped to the floor", slope, -50, 0},
+		{"clamped to the ceiling", slope, 800, 500},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			se := NewSpatialEngine(config.Default().Engine, zap.NewNop())
+			if tt.terrain != nil {
+				se.SetTerrain(tt.terrain)
+			}
+
+			if got := se.GroundHeight(tt.x, 0); got != tt.want {
+				t.Errorf("expected ground at %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSpawnEntity_RaisedToGround(t *testing.T) {
+	se := NewSpatialEngine(config.Default().Engine, zap.NewNop())
+	se.SetTerrain(slope)
+
+	tests := []struct {
+		name string
+		z    float64
+		want entity.Vector3
+	}{
+		{"below the ground", 0, entity.Vector3{X: 30, Y: 0, Z: 30}},
+		{"above the ground", 80, entity.Vector3{X: 30, Y: 0, Z: 80}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entityID := se.SpawnEntity("crate", 30, 0, tt.z, "")
+			ent, ok := se.GetEntity(entityID)
+			if !ok {
+				t.Fatal("expected the entity to spawn")
+			}
+			if ent.Position != tt.want {
+				t.Errorf("expected %+v, got %+v", tt.want, ent.Position)
+			}
+		})
+	}
+}
@@ -14,6 +14,7 @@ type TickManager struct {
 	logger       *zap.Logger
 	tickRate     time.Duration
 	currentTick  uint64
+	tickTime     time.Time // when currentTick started
 	shutdown     chan struct{}
 	wg           sync.WaitGroup
 	tickHandlers []TickHandler
@@ -41,10 +42,19 @@ func NewTickManager(cfg config.EngineConfig, logger *zap.Logger) *TickManager {
 }
 
 func (tm *TickManager) Start(ctx context.Context) error {
-	ticker := time.NewTicker(tm.tickRate)
+	rate := tm.TickRate()
+	ticker := time.NewTicker(rate)
 	defer ticker.Stop()
 
+	tm.mu.Lock()
+	select {
+	case <-tm.shutdown:
+		tm.mu.Unlock()
+		return nil
+	default:
+	}
 	tm.wg.Add(1)
+	tm.mu.Unlock()
 	defer tm.wg.Done()
 
 	tm.logger.Info("Tick loop started",
@@ -64,12 +74,21 @@ func (tm *TickManager) Start(ctx context.Context) error {
 			return nil
 		case <-ticker.C:
 			tm.processTick()
+
+			// A handler may have changed the rate during the tick
+			if next := tm.TickRate(); next != rate {
+				ticker.Reset(next)
+				tm.logger.Info("Tick rate changed", zap.Duration("from", rate), zap.Duration("to", next))
+				rate = next
+			}
 		}
 	}
 }
 
 func (tm *TickManager) Shutdown() {
+	tm.mu.Lock()
 	close(tm.shutdown)
+	tm.mu.Unlock()
 	tm.wg.Wait()
 }
 
@@ -80,12 +99,51 @@ func (tm *TickManager) AddHandler(handler TickHandler) {
 }
 
 func (tm *TickManager) GetCurrentTick() uint64 {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+
 	return tm.currentTick
 }
 
+// GetTickTime returns the current tick and the time it started. Before the
+// first tick the time is zero.
+func (tm *TickManager) GetTickTime() (uint64, time.Time) {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+
+	return tm.currentTick, tm.tickTime
+}
+
+// TickRate returns the fixed timestep.
+func (tm *TickManager) TickRate() time.Duration {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+
+	return tm.tickRate
+}
+
+// SetTickRate changes the fixed timestep. The tick loop switches to it once
+// the current tick's handlers return.
+func (tm *TickManager) SetTickRate(rate time.Duration) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	tm.tickRate = rate
+}
+
+// SetCurrentTick resumes the tick counter from a restored snapshot so tick
+// numbers keep increasing across restarts. Must be called before Start.
+func (tm *TickManager) SetCurrentTick(tick uint64) {
+	tm.currentTick = tick
+}
+
 func (tm *TickManager) processTick() {
 	start := time.Now()
+
+	tm.mu.Lock()
 	tm.currentTick++
+	tm.tickTime = start
+	tm.mu.Unlock()
 
 	tm.mu.RLock()
 	handlers := make([]TickHandler, len(tm.tickHandlers))
@@ -98,13 +156,14 @@ func (tm *TickManager) processTick() {
 	}
 
 	duration := time.Since(start)
+	tickRate := tm.TickRate()
 	
 	// Log warning if tick processing takes too long
-	if duration > tm.tickRate/2 {
+	if duration > tickRate/2 {
 		tm.logger.Warn("Tick processing taking too long",
 			zap.Uint64("tick", tm.currentTick),
 			zap.Duration("duration", duration),
-			zap.Duration("tick_rate", tm.tickRate),
+			zap.Duration("tick_rate", tickRate),
 		)
 	}
 
@@ -0,0 +1,142 @@
+package engine
+
+import (
+	"github.com/akarsh-2004/aether/internal/engine/entity"
+	"github.com/akarsh-2004/aether/internal/engine/spatial"
+)
+
+// zoneCrossing is an entity entering or leaving a zone during a tick.
+type zoneCrossing struct {
+	entityID uint32
+	zone     string
+	entered  bool
+}
+
+// processZones raises enter and exit events for entities that crossed a zone
+// boundary this tick. Entities that spawn inside a zone enter it on their
+// first tick.
+func (se *SpatialEngine) processZones() {
+	if se.zones.Len() == 0 {
+		return
+	}
+
+	var crossings []zoneCrossing
+	for _, ent := range se.entityManager.GetAllEntities() {
+		if ent.Ghost || se.isProjectile(ent.ID) {
+			continue // ghosts cross zones in the owning region
+		}
+
+		current := zoneNames(se.zones.At(ent.Position))
+		previous := se.zoneMembers[ent.ID]
+		if sameZones(current, previous) {
+			continue
+		}
+
+		for _, name := range previous {
+			if !hasZone(current, name) {
+				se.publishZoneCrossed(ent, name, false)
+				crossings = append(crossings, zoneCrossing{entityID: ent.ID, zone: name})
+			}
+		}
+		for _, name := range current {
+			if !hasZone(previous, name) {
+				se.publishZoneCrossed(ent, name, true)
+				crossings = append(crossings, zoneCrossing{entityID: ent.ID, zone: name, entered: true})
+			}
+		}
+
+		if len(current) == 0 {
+			delete(se.zoneMembers, ent.ID)
+		} else {
+			se.zoneMembers[ent.ID] = current
+		}
+	}
+
+	if se.scripts == nil {
+		return
+	}
+
+	// Scripts run after the loop, since they may spawn and despawn entities
+	for _, crossing := range crossings {
+		if crossing.entered {
+			se.scripts.OnZoneEnter(se.scriptAPI, crossing.entityID, crossing.zone)
+		} else {
+			se.scripts.OnZoneExit(se.scriptAPI, crossing.entityID, crossing.zone)
+		}
+	}
+}
+
+// speedMultiplier returns the combined speed multiplier of the zones at
+// position.
+func (se *SpatialEngine) speedMultiplier(position entity.Vector3) float64 {
+	multiplier := 1.0
+	for _, z := range se.zones.At(position) {
+		multiplier *= z.SpeedMultiplier
+	}
+	return multiplier
+}
+
+// inSafeZone reports whether position lies in a zone where projectiles do
+// not hit.
+func (se *SpatialEngine) inSafeZone(position entity.Vector3) bool {
+	for _, z := range se.zones.At(position) {
+		if z.Safe {
+			return true
+		}
+	}
+	return false
+}
+
+// ZonesAt returns the names of the zones containing position, in name order.
+func (se *SpatialEngine) ZonesAt(position entity.Vector3) []string {
+	return zoneNames(se.zones.At(position))
+}
+
+// ZonesInRadius returns the names of the zones overlapping a circle around
+// center, in name order.
+func (se *SpatialEngine) ZonesInRadius(center entity.Vector3, radius float64) []string {
+	return zoneNames(se.zones.QueryRadius(center, radius))
+}
+
+// EntityZones returns the zones an entity was in at the end of the last
+// tick.
+func (se *SpatialEngine) EntityZones(entityID uint32) []string {
+	se.mu.RLock()
+	defer se.mu.RUnlock()
+
+	return append([]string(nil), se.zoneMembers[entityID]...)
+}
+
+func zoneNames(zones []*spatial.Zone) []string {
+	if len(zones) == 0 {
+		return nil
+	}
+
+	names := make([]string, len(zones))
+	for i, z := range zones {
+		names[i] = z.Name
+	}
+	return names
+}
+
+// sameZones compares two name-ordered zone lists.
+func sameZones(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func hasZone(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
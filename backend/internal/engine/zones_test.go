@@ -0,0 +1,163 @@
+package engine
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/akarsh-2004/aether/internal/config"
+	"github.com/akarsh-2004/aether/internal/engine/entity"
+	"github.com/akarsh-2004/aether/proto"
+	"go.uber.org/zap"
+)
+
+// zoneRecorder is a script host that records zone hooks.
+type zoneRecorder struct {
+	calls []string
+}
+
+func (r *zoneRecorder) OnSpawn(api *ScriptAPI, entityID uint32)          {}
+func (r *zoneRecorder) OnTick(api *ScriptAPI, tick uint64)               {}
+func (r *zoneRecorder) OnEnter(api *ScriptAPI, entityID, otherID uint32) {}
+func (r *zoneRecorder) OnExit(api *ScriptAPI, entityID, otherID uint32)  {}
+func (r *zoneRecorder) OnMessage(api *ScriptAPI, clientID string, entityID uint32, name, payload string) {
+}
+
+func (r *zoneRecorder) OnZoneEnter(api *ScriptAPI, entityID uint32, zone string) {
+	r.calls = append(r.calls, "enter "+zone)
+}
+
+func (r *zoneRecorder) OnZoneExit(api *ScriptAPI, entityID uint32, zone string) {
+	r.calls = append(r.calls, "exit "+zone)
+}
+
+func zoneEngine() *SpatialEngine {
+	cfg := config.Default().Engine
+	cfg.Zones = []config.ZoneConfig{
+		{Name: "town", MinX: 0, MinY: 0, MaxX: 20, MaxY: 20, Safe: true},
+		{Name: "market", MinX: 10, MinY: 10, MaxX: 20, MaxY: 20},
+	}
+	se := NewSpatialEngine(cfg, zap.NewNop())
+	se.EnableOutbox(100)
+	return se
+}
+
+// moveTo places an entity at x, y and runs the zone step of a tick.
+func moveTo(se *SpatialEngine, entityID uint32, x, y float64) {
+	se.mu.Lock()
+	defer se.mu.Unlock()
+
+	ent, _ := se.entityManager.GetEntity(entityID)
+	ent.Position = entity.Vector3{X: x, Y: y}
+	se.processZones()
+}
+
+// zoneEvents renders the pending zone events in order and acknowledges
+// everything pending.
+func zoneEvents(se *SpatialEngine) string {
+	var events []string
+	pending := se.PendingEvents()
+	for _, e := range pending {
+		switch event := e.Event.(type) {
+		case *proto.ZoneEnteredEvent:
+			events = append(events, "enter "+event.Zone)
+		case *proto.ZoneExitedEvent:
+			events = append(events, "exit "+event.Zone)
+		}
+	}
+	if len(pending) > 0 {
+		se.AckEvents(pending[len(pending)-1].Seq)
+	}
+	return strings.Join(events, ",")
+}
+
+func TestProcessZones_Transitions(t *testing.T) {
+	se := zoneEngine()
+	scripts := &zoneRecorder{}
+	se.SetScriptHost(scripts)
+
+	id := se.SpawnEntity("npc", -50, -50, 0, "")
+	zoneEvents(se)
+
+	steps := []struct {
+		name      string
+		x, y      float64
+		wantZones string
+		want      string
+	}{
+		{"outside every zone", -50, -50, "", ""},
+		{"enter one zone", 5, 5, "town", "enter town"},
+		{"move within it", 6, 6, "town", ""},
+		{"enter an overlapping zone", 15, 15, "market,town", "enter market"},
+		{"far edge counts as inside", 20, 20, "market,town", ""},
+		{"leave the overlap", 5, 15, "town", "exit market"},
+		{"re-enter the overlap", 15, 15, "market,town", "enter market"},
+		{"leave every zone at once", 50, 50, "", "exit market,exit town"},
+	}
+
+	for _, step := range steps {
+		scripts.calls = nil
+		moveTo(se, id, step.x, step.y)
+
+		if got := strings.Join(se.EntityZones(id), ","); got != step.wantZones {
+			t.Errorf("%s: expected zones %q, got %q", step.name, step.wantZones, got)
+		}
+		if got := zoneEvents(se); got != step.want {
+			t.Errorf("%s: expected events %q, got %q", step.name, step.want, got)
+		}
+		if got := strings.Join(scripts.calls, ","); got != step.want {
+			t.Errorf("%s: expected hooks %q, got %q", step.name, step.want, got)
+		}
+	}
+}
+
+func TestProcessZones_SpawnInsideEntersOnFirstTick(t *testing.T) {
+	se := zoneEngine()
+
+	id := se.SpawnEntity("npc", 15, 15, 0, "")
+	if zones := se.EntityZones(id); len(zones) != 0 {
+		t.Fatalf("expected no zones before the first tick, got %v", zones)
+	}
+	zoneEvents(se)
+
+	moveTo(se, id, 15, 15)
+	if got := zoneEvents(se); got != "enter market,enter town" {
+		t.Errorf("expected both zones to be entered in name order, got %q", got)
+	}
+}
+
+func TestProcessZones_RemovedEntityForgotten(t *testing.T) {
+	se := zoneEngine()
+
+	id := se.SpawnEntity("npc", 5, 5, 0, "")
+	moveTo(se, id, 5, 5)
+	se.RemoveEntity(id, "test")
+
+	if zones := se.EntityZones(id); len(zones) != 0 {
+		t.Errorf("expected a removed entity to leave no zone membership, got %v", zones)
+	}
+	if got := zoneEvents(se); strings.Contains(got, "exit") {
+		t.Errorf("expected no exit event for a removed entity, got %q", got)
+	}
+}
+
+func TestZonesAt(t *testing.T) {
+	se := zoneEngine()
+
+	tests := []struct {
+		x, y float64
+		want string
+	}{
+		{5, 5, "town"},
+		{15, 15, "market,town"},
+		{25, 25, ""},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(se.ZonesAt(entity.Vector3{X: tt.x, Y: tt.y}), ","); got != tt.want {
+			t.Errorf("(%v, %v): expected %q, got %q", tt.x, tt.y, tt.want, got)
+		}
+	}
+
+	if !se.inSafeZone(entity.Vector3{X: 5, Y: 5}) || se.inSafeZone(entity.Vector3{X: 25, Y: 25}) {
+		t.Error("expected only the town to be safe")
+	}
+}
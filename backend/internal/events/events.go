@@ -0,0 +1,125 @@
+package events
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	protobuf "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/akarsh-2004/aether/proto"
+)
+
+// Outbox event types.
+const (
+	TypeEntitySpawned        = "entity_spawned"
+	TypeEntityDespawned      = "entity_despawned"
+	TypeMovementCorrection   = "movement_correction"
+	TypeEntityControlChanged = "entity_control_changed"
+	TypeProjectileHit        = "projectile_hit"
+	TypeZoneEntered          = "zone_entered"
+	TypeZoneExited           = "zone_exited"
+	TypeSessionStarted       = "session_started"
+	TypeSessionEnded         = "session_ended"
+)
+
+// LegacyVersion is the schema version of payloads written before events were
+// typed, when they were free-form JSON maps.
+const LegacyVersion = 1
+
+type definition struct {
+	eventType string
+	version   int
+	newEvent  func() protobuf.Message
+}
+
+var (
+	definitions = make(map[string]*definition)
+	byMessage   = make(map[protoreflect.FullName]*definition)
+)
+
+func init() {
+	register(TypeEntitySpawned, 2, func() protobuf.Message { return &proto.EntitySpawnedEvent{} })
+	register(TypeEntityDespawned, 2, func() protobuf.Message { return &proto.EntityDespawnedEvent{} })
+	register(TypeMovementCorrection, 2, func() protobuf.Message { return &proto.MovementCorrectionEvent{} })
+	register(TypeEntityControlChanged, 2, func() protobuf.Message { return &proto.EntityControlChangedEvent{} })
+	register(TypeProjectileHit, 2, func() protobuf.Message { return &proto.ProjectileHitEvent{} })
+	register(TypeZoneEntered, 2, func() protobuf.Message { return &proto.ZoneEnteredEvent{} })
+	register(TypeZoneExited, 2, func() protobuf.Message { return &proto.ZoneExitedEvent{} })
+	register(TypeSessionStarted, 2, func() protobuf.Message { return &proto.SessionStartedEvent{} })
+	register(TypeSessionEnded, 2, func() protobuf.Message { return &proto.SessionEndedEvent{} })
+}
+
+func register(eventType string, version int, newEvent func() protobuf.Message) {
+	def := &definition{eventType: eventType, version: version, newEvent: newEvent}
+	definitions[eventType] = def
+	byMessage[newEvent().ProtoReflect().Descriptor().FullName()] = def
+}
+
+var (
+	marshalOptions   = protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true}
+	unmarshalOptions = protojson.UnmarshalOptions{DiscardUnknown: true}
+)
+
+// TypeOf returns the outbox event type for an event message.
+func TypeOf(event protobuf.Message) (string, error) {
+	def, ok := byMessage[event.ProtoReflect().Descriptor().FullName()]
+	if !ok {
+		return "", fmt.Errorf("%s is not a registered event", event.ProtoReflect().Descriptor().FullName())
+	}
+	return def.eventType, nil
+}
+
+// CurrentVersion returns the schema version new events of eventType are
+// written with.
+func CurrentVersion(eventType string) (int, bool) {
+	def, ok := definitions[eventType]
+	if !ok {
+		return 0, false
+	}
+	return def.version, true
+}
+
+// Encode serializes an event for the outbox. It returns the event type and
+// schema version to store alongside the JSON payload.
+func Encode(event protobuf.Message) (eventType string, version int, payload []byte, err error) {
+	def, ok := byMessage[event.ProtoReflect().Descriptor().FullName()]
+	if !ok {
+		return "", 0, nil, fmt.Errorf("%s is not a registered event", event.ProtoReflect().Descriptor().FullName())
+	}
+
+	payload, err = marshalOptions.Marshal(event)
+	if err != nil {
+		return "", 0, nil, fmt.Errorf("failed to encode %s event: %w", def.eventType, err)
+	}
+
+	return def.eventType, def.version, payload, nil
+}
+
+// Decode parses a stored payload into its event message, upgrading payloads
+// written with an older schema version first.
+func Decode(eventType string, version int, payload []byte) (protobuf.Message, error) {
+	def, ok := definitions[eventType]
+	if !ok {
+		return nil, fmt.Errorf("unknown event type %q", eventType)
+	}
+
+	if version > def.version {
+		return nil, fmt.Errorf("%s event has schema version %d, newer than supported version %d", eventType, version, def.version)
+	}
+
+	if version < def.version {
+		upgraded, err := upgrade(eventType, version, def.version, payload)
+		if err != nil {
+			return nil, err
+		}
+		payload = upgraded
+	}
+
+	event := def.newEvent()
+	if err := unmarshalOptions.Unmarshal(payload, event); err != nil {
+		return nil, fmt.Errorf("failed to decode %s event: %w", eventType, err)
+	}
+
+	return event, nil
+}
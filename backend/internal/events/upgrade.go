@@ -0,0 +1,58 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Upgrader rewrites a decoded JSON payload from one schema version to the
+// next.
+type Upgrader func(payload map[string]interface{}) error
+
+type upgradeKey struct {
+	eventType string
+	from      int
+}
+
+var upgraders = make(map[upgradeKey]Upgrader)
+
+func init() {
+	// Legacy payloads already use the proto field names; the only gap is
+	// despawns written without a reason.
+	RegisterUpgrader(TypeEntityDespawned, LegacyVersion, func(payload map[string]interface{}) error {
+		if reason, ok := payload["reason"].(string); !ok || reason == "" {
+			payload["reason"] = "unknown"
+		}
+		return nil
+	})
+}
+
+// RegisterUpgrader installs the step that moves eventType payloads from
+// version from to from+1. Steps without an upgrader are treated as
+// compatible and pass the payload through unchanged.
+func RegisterUpgrader(eventType string, from int, upgrader Upgrader) {
+	upgraders[upgradeKey{eventType: eventType, from: from}] = upgrader
+}
+
+func upgrade(eventType string, from, to int, payload []byte) ([]byte, error) {
+	var fields map[string]interface{}
+	if err := json.Unmarshal(payload, &fields); err != nil {
+		return nil, fmt.Errorf("failed to parse %s v%d payload: %w", eventType, from, err)
+	}
+
+	for version := from; version < to; version++ {
+		upgrader, ok := upgraders[upgradeKey{eventType: eventType, from: version}]
+		if !ok {
+			continue
+		}
+		if err := upgrader(fields); err != nil {
+			return nil, fmt.Errorf("failed to upgrade %s from v%d: %w", eventType, version, err)
+		}
+	}
+
+	upgraded, err := json.Marshal(fields)
+	if err != nil {
+		return nil, fmt.Errorf("failed to re-encode %s payload: %w", eventType, err)
+	}
+	return upgraded, nil
+}
@@ -0,0 +1,226 @@
+package gateway
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/akarsh-2004/aether/internal/channels"
+	"github.com/akarsh-2004/aether/proto"
+	"go.uber.org/zap"
+)
+
+// SetChannels enables SUBSCRIBE / UNSUBSCRIBE / PUBLISH and the channel admin
+// routes. It should be called before Start.
+func (g *WebSocketGateway) SetChannels(broker *channels.Broker) {
+	g.broker = broker
+}
+
+// registerChannelRoutes adds the channel routes to the admin listener. A
+// broadcast skips channel ACLs and rate limits, so clients must not reach it.
+func (g *WebSocketGateway) registerChannelRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/channels/", g.handleChannelPublish)
+	mux.HandleFunc("/teams/", g.handleTeam)
+}
+
+func (g *WebSocketGateway) handleSubscribe(client *Client, req *proto.Subscribe) {
+	if g.broker == nil {
+		g.sendChannelStatus(client, req.Channel, false, "channels disabled")
+		return
+	}
+
+	if err := g.broker.Subscribe(client.id, req.Channel); err != nil {
+		g.sendChannelStatus(client, req.Channel, false, err.Error())
+		return
+	}
+	g.sendChannelStatus(client, req.Channel, true, "")
+}
+
+func (g *WebSocketGateway) handleUnsubscribe(client *Client, req *proto.Unsubscribe) {
+	if g.broker == nil {
+		return
+	}
+
+	if err := g.broker.Unsubscribe(client.id, req.Channel); err != nil {
+		g.sendChannelStatus(client, req.Channel, false, err.Error())
+		return
+	}
+	g.sendChannelStatus(client, req.Channel, false, "")
+}
+
+func (g *WebSocketGateway) handlePublish(client *Client, req *proto.Publish) {
+	if g.broker == nil {
+		g.sendChannelStatus(client, req.Channel, false, "channels disabled")
+		return
+	}
+
+	delivery, err := g.broker.Publish(client.id, req.Channel, len(req.Payload))
+	if err != nil {
+		if errors.Is(err, channels.ErrRateLimited) {
+			g.logger.Debug("Channel publish rate limited", zap.String("client_id", client.id), zap.String("channel", req.Channel))
+		}
+		g.sendChannelStatus(client, req.Channel, false, err.Error())
+		return
+	}
+
+	if delivery.Kind == channels.KindProximity {
+		client.mu.RLock()
+		w := client.world
+		client.mu.RUnlock()
+
+		if w == nil {
+			g.sendChannelStatus(client, req.Channel, false, "client has not joined a world")
+			return
+		}
+		delivery.Recipients = withinReach(delivery.Recipients, w.Engine.NearbyClients(client.id))
+	}
+
+	g.deliver(delivery, client.id, req.Payload, req.ContentType)
+}
+
+func withinReach(recipients []string, nearby map[string]struct{}) []string {
+	filtered := recipients[:0]
+	for _, clientID := range recipients {
+		if _, ok := nearby[clientID]; ok {
+			filtered = append(filtered, clientID)
+		}
+	}
+	return filtered
+}
+
+// deliver encodes a channel message once and queues it for every recipient
+// connected here.
+func (g *WebSocketGateway) deliver(delivery *channels.Delivery, senderID string, payload []byte, contentType string) int {
+	message := &proto.Message{
+		Type: proto.MessageType_CHANNEL_MESSAGE,
+		Payload: &proto.Message_ChannelMessage{
+			ChannelMessage: &proto.ChannelMessage{
+				Channel:        delivery.Channel,
+				SenderClientId: senderID,
+				Payload:        payload,
+				ContentType:    contentType,
+				Timestamp:      uint64(time.Now().UnixMilli()),
+			},
+		},
+	}
+
+	data, err := g.codec.Encode(message)
+	if err != nil {
+		g.logger.Error("Failed to encode channel message", zap.String("channel", delivery.Channel), zap.Error(err))
+		return 0
+	}
+
+	delivered := 0
+	for _, clientID := range delivery.Recipients {
+		value, ok := g.clients.Load(clientID)
+		if !ok {
+			continue
+		}
+		client := value.(*Client)
+
+		if g.trySend(client, data, "channel message", zap.String("channel", delivery.Channel)) {
+			delivered++
+		}
+	}
+	return delivered
+}
+
+func (g *WebSocketGateway) sendChannelStatus(client *Client, channel string, subscribed bool, errorMsg string) {
+	message := &proto.Message{
+		Type: proto.MessageType_CHANNEL_STATUS,
+		Payload: &proto.Message_ChannelStatus{
+			ChannelStatus: &proto.ChannelStatus{
+				Channel:      channel,
+				Subscribed:   subscribed,
+				ErrorMessage: errorMsg,
+			},
+		},
+	}
+
+	data, err := g.codec.Encode(message)
+	if err != nil {
+		g.logger.Error("Failed to encode channel status", zap.String("client_id", client.id), zap.Error(err))
+		return
+	}
+
+	g.trySend(client, data, "channel status")
+}
+
+// SetTeam assigns a connected client to a team, or removes it from its team
+// when team is empty. The client is told about team channels it loses.
+func (g *WebSocketGateway) SetTeam(clientID, team string) bool {
+	value, ok := g.clients.Load(clientID)
+	if !ok || g.broker == nil {
+		return false
+	}
+
+	for _, channel := range g.broker.SetTeam(clientID, team) {
+		g.sendChannelStatus(value.(*Client), channel, false, "left team")
+	}
+	return true
+}
+
+// POST /channels/{channel} {"payload": "...", "content_type": "text/plain"}
+// publishes a message from the server, e.g. an announcement.
+func (g *WebSocketGateway) handleChannelPublish(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	channel := strings.TrimPrefix(r.URL.Path, "/channels/")
+
+	var req struct {
+		Payload     string `json:"payload"`
+		ContentType string `json:"content_type"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	delivery, err := g.broker.Broadcast(channel)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if delivery.Kind == channels.KindProximity {
+		http.Error(w, "proximity channels need a publisher", http.StatusBadRequest)
+		return
+	}
+
+	delivered := g.deliver(delivery, "", []byte(req.Payload), req.ContentType)
+	writeJSON(w, map[string]interface{}{
+		"channel":   channel,
+		"delivered": delivered,
+	})
+}
+
+// PUT /teams/{client_id} {"team": "red"} assigns a client to a team; an empty
+// team removes it from its team.
+func (g *WebSocketGateway) handleTeam(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		w.Header().Set("Allow", "PUT")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	clientID := strings.TrimPrefix(r.URL.Path, "/teams/")
+
+	var req struct {
+		Team string `json:"team"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || strings.Contains(req.Team, ":") {
+		http.Error(w, "invalid team", http.StatusBadRequest)
+		return
+	}
+
+	if !g.SetTeam(clientID, req.Team) {
+		http.Error(w, "client not found", http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,183 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/akarsh-2004/aether/internal/chat"
+	"github.com/akarsh-2004/aether/proto"
+	"go.uber.org/zap"
+)
+
+// SetChat enables CHAT_SEND / CHAT_MUTE and the admin chat history route. It
+// should be called before Start.
+func (g *WebSocketGateway) SetChat(service *chat.Service) {
+	g.chat = service
+}
+
+// registerChatRoutes adds the chat routes to the admin listener. The history
+// is the moderation audit log, rejected messages included, so clients must
+// not reach it.
+func (g *WebSocketGateway) registerChatRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/chat/history", g.handleChatHistory)
+}
+
+func (g *WebSocketGateway) handleChatSend(client *Client, req *proto.ChatSend) {
+	if g.chat == nil {
+		g.sendChatError(client, req.ClientMsgId, "chat disabled")
+		return
+	}
+
+	client.mu.RLock()
+	w, entityID := client.world, client.entityID
+	client.mu.RUnlock()
+
+	if w == nil || entityID == 0 {
+		g.sendChatError(client, req.ClientMsgId, "client has not spawned")
+		return
+	}
+	ent, exists := w.Engine.GetEntity(entityID)
+	if !exists {
+		g.sendChatError(client, req.ClientMsgId, "client has not spawned")
+		return
+	}
+
+	msg := &chat.Message{
+		WorldID:        w.ID,
+		SenderClientID: client.id,
+		SenderEntityID: entityID,
+		X:              ent.Position.X,
+		Y:              ent.Position.Y,
+		Text:           req.Text,
+	}
+
+	audience := w.Engine.ClientsInRadius(client.id, g.chat.Radius())
+	recipients, err := g.chat.Send(msg, audience)
+	if err != nil {
+		g.sendChatError(client, req.ClientMsgId, err.Error())
+		return
+	}
+
+	for _, clientID := range recipients {
+		value, ok := g.clients.Load(clientID)
+		if !ok {
+			continue
+		}
+
+		var ack uint64
+		if clientID == client.id {
+			ack = req.ClientMsgId
+		}
+		g.sendChatMessage(value.(*Client), &proto.ChatMessage{
+			SenderClientId: client.id,
+			SenderEntityId: entityID,
+			Text:           msg.Text,
+			Timestamp:      uint64(msg.CreatedAt.UnixMilli()),
+			ClientMsgId:    ack,
+		})
+	}
+}
+
+func (g *WebSocketGateway) handleChatMute(client *Client, req *proto.ChatMute) {
+	if g.chat == nil || req.TargetClientId == client.id {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	if err := g.chat.UpdateLists(ctx, client.id, req.Action, req.TargetClientId); err != nil {
+		g.logger.Warn("Failed to update chat lists",
+			zap.String("client_id", client.id),
+			zap.String("action", req.Action),
+			zap.Error(err),
+		)
+	}
+}
+
+// resumeChat carries a reconnecting client's mute and block lists over.
+func (g *WebSocketGateway) resumeChat(prevClientID, clientID string) {
+	if g.chat == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	if err := g.chat.Resume(ctx, prevClientID, clientID); err != nil {
+		g.logger.Warn("Failed to resume chat lists", zap.String("client_id", clientID), zap.Error(err))
+	}
+}
+
+func (g *WebSocketGateway) sendChatError(client *Client, clientMsgID uint64, errorMsg string) {
+	g.sendChatMessage(client, &proto.ChatMessage{
+		SenderClientId: client.id,
+		Timestamp:      uint64(time.Now().UnixMilli()),
+		ClientMsgId:    clientMsgID,
+		ErrorMessage:   errorMsg,
+	})
+}
+
+func (g *WebSocketGateway) sendChatMessage(client *Client, chatMsg *proto.ChatMessage) {
+	message := &proto.Message{
+		Type: proto.MessageType_CHAT_MESSAGE,
+		Payload: &proto.Message_ChatMessage{
+			ChatMessage: chatMsg,
+		},
+	}
+
+	data, err := g.codec.Encode(message)
+	if err != nil {
+		g.logger.Error("Failed to encode chat message", zap.String("client_id", client.id), zap.Error(err))
+		return
+	}
+
+	g.trySend(client, data, "chat message")
+}
+
+// GET /chat/history?world_id=&client_id=&before=<unix ms>&limit= returns
+// audited chat messages, newest first, including rejected ones.
+func (g *WebSocketGateway) handleChatHistory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", "GET")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	params := r.URL.Query()
+	query := chat.HistoryQuery{
+		WorldID:        params.Get("world_id"),
+		SenderClientID: params.Get("client_id"),
+	}
+
+	if before := params.Get("before"); before != "" {
+		ms, err := strconv.ParseInt(before, 10, 64)
+		if err != nil {
+			http.Error(w, "before must be unix milliseconds", http.StatusBadRequest)
+			return
+		}
+		query.Before = time.UnixMilli(ms)
+	}
+	if limit := params.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 {
+			http.Error(w, "limit must be a positive integer", http.StatusBadRequest)
+			return
+		}
+		query.Limit = n
+	}
+
+	messages, err := g.chat.History(r.Context(), query)
+	if err != nil {
+		if !errors.Is(err, context.Canceled) {
+			g.logger.Error("Failed to load chat history", zap.Error(err))
+		}
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, messages)
+}
@@ -0,0 +1,177 @@
+package gateway
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/akarsh-2004/aether/internal/observability"
+	"github.com/akarsh-2004/aether/proto"
+	"go.uber.org/zap"
+)
+
+// maxClockRTT bounds round trips accepted from heartbeat echoes; anything
+// longer is a stale or forged echo.
+const maxClockRTT = 10 * time.Second
+
+// Latency is a client's measured connection quality. RTT is smoothed as in
+// RFC 6298 and jitter is the RFC 3550 mean deviation between consecutive
+// round trips.
+type Latency struct {
+	RTT         time.Duration
+	Jitter      time.Duration
+	ClockOffset time.Duration // Client clock minus server clock
+	Samples     int
+	UpdatedAt   time.Time
+
+	lastRTT time.Duration
+}
+
+func (l *Latency) observe(rtt, offset time.Duration, now time.Time) {
+	if l.Samples == 0 {
+		l.RTT = rtt
+		l.ClockOffset = offset
+	} else {
+		diff := rtt - l.lastRTT
+		if diff < 0 {
+			diff = -diff
+		}
+		l.Jitter += (diff - l.Jitter) / 16
+		l.RTT += (rtt - l.RTT) / 8
+
+		// Slow round trips are the most likely to be asymmetric, so they
+		// only nudge the offset
+		if rtt <= l.RTT {
+			l.ClockOffset += (offset - l.ClockOffset) / 2
+		} else {
+			l.ClockOffset += (offset - l.ClockOffset) / 8
+		}
+	}
+
+	l.lastRTT = rtt
+	l.Samples++
+	l.UpdatedAt = now
+}
+
+// SetMetrics enables latency metrics. It should be called before Start.
+func (g *WebSocketGateway) SetMetrics(metrics *observability.Metrics) {
+	g.metrics = metrics
+}
+
+// registerClientRoutes adds the connection list to the admin listener. It
+// exposes every client's world, entity and network quality, so clients must
+// not reach it.
+func (g *WebSocketGateway) registerClientRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/clients", g.handleClients)
+	mux.HandleFunc("/clients/", g.handleClients)
+}
+
+// observeClock measures a round trip from a heartbeat echoing the previous
+// TimeSync. With t0 the echoed server send time, t1 the client's receive
+// time, t2 the heartbeat's send time and t3 its arrival, the round trip is
+// (t3 - t0) - (t2 - t1) and the client is ((t1 - t0) + (t2 - t3)) / 2 ahead.
+func (g *WebSocketGateway) observeClock(client *Client, heartbeat *proto.Heartbeat, received time.Time) {
+	if heartbeat.EchoServerTime == 0 || heartbeat.EchoReceiveTime == 0 {
+		return
+	}
+
+	t0 := int64(heartbeat.EchoServerTime)
+	t1 := int64(heartbeat.EchoReceiveTime)
+	t2 := int64(heartbeat.Timestamp)
+	t3 := received.UnixMilli()
+
+	rtt := time.Duration((t3-t0)-(t2-t1)) * time.Millisecond
+	if rtt < 0 || rtt > maxClockRTT {
+		g.logger.Debug("Ignoring implausible heartbeat echo",
+			zap.String("client_id", client.id),
+			zap.Duration("rtt", rtt),
+		)
+		return
+	}
+	offset := time.Duration(((t1-t0)+(t2-t3))/2) * time.Millisecond
+
+	client.mu.Lock()
+	client.latency.observe(rtt, offset, received)
+	latency, w := client.latency, client.world
+	client.mu.Unlock()
+
+	if w != nil {
+		w.Engine.SetClientLatency(client.id, latency.RTT/2)
+	}
+	if g.metrics != nil {
+		g.metrics.RecordClientLatency(rtt, latency.Jitter, latency.ClockOffset)
+	}
+}
+
+// Latency returns a connected client's latency measurements.
+func (g *WebSocketGateway) Latency(clientID string) (Latency, bool) {
+	value, ok := g.clients.Load(clientID)
+	if !ok {
+		return Latency{}, false
+	}
+
+	client := value.(*Client)
+	client.mu.RLock()
+	defer client.mu.RUnlock()
+
+	return client.latency, true
+}
+
+type clientInfo struct {
+	ClientID      string  `json:"client_id"`
+	WorldID       string  `json:"world_id,omitempty"`
+	EntityID      uint32  `json:"entity_id,omitempty"`
+	RTTMs         float64 `json:"rtt_ms"`
+	JitterMs      float64 `json:"jitter_ms"`
+	ClockOffsetMs float64 `json:"clock_offset_ms"`
+	Samples       int     `json:"samples"`
+}
+
+func (c *Client) info() clientInfo {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	info := clientInfo{
+		ClientID:      c.id,
+		EntityID:      c.entityID,
+		RTTMs:         milliseconds(c.latency.RTT),
+		JitterMs:      milliseconds(c.latency.Jitter),
+		ClockOffsetMs: milliseconds(c.latency.ClockOffset),
+		Samples:       c.latency.Samples,
+	}
+	if c.world != nil {
+		info.WorldID = c.world.ID
+	}
+	return info
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// GET /clients lists the clients connected to this instance with their
+// latency; GET /clients/{id} returns one of them.
+func (g *WebSocketGateway) handleClients(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", "GET")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if id := strings.TrimPrefix(r.URL.Path, "/clients/"); id != "" && id != r.URL.Path {
+		value, ok := g.clients.Load(id)
+		if !ok {
+			http.Error(w, "client not connected", http.StatusNotFound)
+			return
+		}
+		writeJSON(w, value.(*Client).info())
+		return
+	}
+
+	clients := make([]clientInfo, 0)
+	g.clients.Range(func(_, value interface{}) bool {
+		clients = append(clients, value.(*Client).info())
+		return true
+	})
+	writeJSON(w, clients)
+}
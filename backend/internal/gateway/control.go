@@ -0,0 +1,144 @@
+package gateway
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/akarsh-2004/aether/internal/engine"
+	"github.com/akarsh-2004/aether/proto"
+	"go.uber.org/zap"
+)
+
+// Control request actions.
+const (
+	controlPossess  = "possess"
+	controlRelease  = "release"
+	controlTransfer = "transfer"
+)
+
+func (g *WebSocketGateway) handleControlRequest(client *Client, req *proto.ControlRequest) {
+	client.mu.RLock()
+	w := client.world
+	client.mu.RUnlock()
+
+	if w == nil {
+		g.sendControlUpdate(client, req.EntityId, false, "refused", "client has not joined a world")
+		return
+	}
+
+	var err error
+	switch req.Action {
+	case controlPossess:
+		err = w.Engine.Possess(req.EntityId, client.id)
+
+	case controlRelease:
+		err = w.Engine.ReleaseControl(req.EntityId, client.id)
+
+	case controlTransfer:
+		// Only hand entities to clients playing in the same world
+		value, ok := g.clients.Load(req.TargetClientId)
+		if !ok || req.TargetClientId == client.id {
+			err = errors.New("target client not found")
+			break
+		}
+		target := value.(*Client)
+		target.mu.RLock()
+		sameWorld := target.world == w
+		target.mu.RUnlock()
+		if !sameWorld {
+			err = errors.New("target client is in another world")
+			break
+		}
+		err = w.Engine.TransferControl(req.EntityId, client.id, req.TargetClientId)
+
+	default:
+		err = errors.New("unknown control action: " + req.Action)
+	}
+
+	if err != nil {
+		g.logger.Debug("Control request refused",
+			zap.String("client_id", client.id),
+			zap.Uint32("entity_id", req.EntityId),
+			zap.String("action", req.Action),
+			zap.Error(err),
+		)
+		g.sendControlUpdate(client, req.EntityId, false, "refused", err.Error())
+	}
+}
+
+// OnControlChanged tells the previous and new controller of an entity about
+// the change. It is installed as every world's engine.ControlListener.
+func (g *WebSocketGateway) OnControlChanged(change engine.ControlChange) {
+	if change.PreviousClientID != "" {
+		if value, ok := g.clients.Load(change.PreviousClientID); ok {
+			g.sendControlUpdate(value.(*Client), change.EntityID, false, change.Reason, "")
+		}
+	}
+	if change.ClientID != "" {
+		if value, ok := g.clients.Load(change.ClientID); ok {
+			g.sendControlUpdate(value.(*Client), change.EntityID, true, change.Reason, "")
+		}
+	}
+}
+
+func (g *WebSocketGateway) sendControlUpdate(client *Client, entityID uint32, controlled bool, reason, errorMsg string) {
+	message := &proto.Message{
+		Type: proto.MessageType_CONTROL_UPDATE,
+		Payload: &proto.Message_ControlUpdate{
+			ControlUpdate: &proto.ControlUpdate{
+				EntityId:     entityID,
+				Controlled:   controlled,
+				Reason:       reason,
+				ErrorMessage: errorMsg,
+			},
+		},
+	}
+
+	data, err := g.codec.Encode(message)
+	if err != nil {
+		g.logger.Error("Failed to encode control update", zap.String("client_id", client.id), zap.Error(err))
+		return
+	}
+
+	g.trySend(client, data, "control update")
+}
+
+// POST /worlds/{id}/entities/{entity_id}/revoke takes an entity away from
+// its controller, e.g. for moderation.
+func (g *WebSocketGateway) handleRevokeControl(w http.ResponseWriter, r *http.Request, worldID, rest string) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	idPart, ok := strings.CutSuffix(rest, "/revoke")
+	if !ok {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	entityID, err := strconv.ParseUint(idPart, 10, 32)
+	if err != nil {
+		http.Error(w, "invalid entity id", http.StatusBadRequest)
+		return
+	}
+
+	target, exists := g.worlds.Get(worldID)
+	if !exists {
+		http.Error(w, "world not found", http.StatusNotFound)
+		return
+	}
+
+	previous, err := target.Engine.RevokeControl(uint32(entityID))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	writeJSON(w, map[string]interface{}{
+		"entity_id":          entityID,
+		"previous_client_id": previous,
+	})
+}
@@ -0,0 +1,262 @@
+package gateway
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/akarsh-2004/aether/internal/persistence/redis"
+	"github.com/akarsh-2004/aether/internal/world"
+	"go.uber.org/zap"
+)
+
+// Presence publishes which clients this instance hosts and answers
+// cluster-wide directory queries.
+type Presence interface {
+	Touch(ctx context.Context, clientID, worldID string, entityID uint32, x, y float64, tokenHash string) error
+	Remove(ctx context.Context, clientID string) error
+	Locate(ctx context.Context, clientID string) (*redis.PresenceData, error)
+	Nearby(ctx context.Context, worldID string, x, y, radius float64, limit int) ([]redis.PresenceData, error)
+	Instances(ctx context.Context) ([]string, error)
+}
+
+// maxNearbyResults caps /directory/nearby responses.
+const maxNearbyResults = 500
+
+// SetPresence enables presence tracking and the /directory endpoints. It
+// should be called before Start.
+func (g *WebSocketGateway) SetPresence(presence Presence) {
+	g.presence = presence
+	g.presenceQueue = make(chan presenceUpdate, presenceQueueSize)
+}
+
+// registerDirectoryRoutes adds the client directory routes to the public
+// listener. Callers authenticate as a client and only see their own world.
+func (g *WebSocketGateway) registerDirectoryRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/directory/clients/", g.handleLocateClient)
+	mux.HandleFunc("/directory/nearby", g.handleNearby)
+}
+
+// registerDirectoryAdminRoutes adds the instance list to the admin listener.
+func (g *WebSocketGateway) registerDirectoryAdminRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/directory/instances", g.handleInstances)
+}
+
+// presenceUpdate is a queued presence write: a heartbeat, or a removal.
+type presenceUpdate struct {
+	world       *world.World
+	clientID    string
+	entityID    uint32
+	resumeToken string
+	remove      bool
+}
+
+// presenceQueueSize bounds the presence writes waiting for Redis.
+const presenceQueueSize = 1024
+
+// touchPresence queues a heartbeat for the presence worker, so a slow Redis
+// never stalls clients. If the worker falls behind the heartbeat is dropped;
+// the next one catches up. The resume token's hash is published so directory
+// callers can prove who they are to any instance.
+func (g *WebSocketGateway) touchPresence(w *world.World, clientID string, entityID uint32, resumeToken string) {
+	if g.presence == nil {
+		return
+	}
+
+	update := presenceUpdate{world: w, clientID: clientID, entityID: entityID, resumeToken: resumeToken}
+	select {
+	case g.presenceQueue <- update:
+	default:
+		g.logger.Warn("Presence queue full, dropping heartbeat", zap.String("client_id", clientID))
+	}
+}
+
+// removePresence queues a client's removal behind its pending heartbeats, so
+// a late heartbeat cannot bring a disconnected client back. Removals wait for
+// room rather than being dropped.
+func (g *WebSocketGateway) removePresence(clientID string) {
+	if g.presence == nil {
+		return
+	}
+
+	select {
+	case g.presenceQueue <- presenceUpdate{clientID: clientID, remove: true}:
+	case <-g.shutdown:
+		// The directory drops this instance's clients when it stops
+	}
+}
+
+// presenceWorker writes queued presence updates one at a time.
+func (g *WebSocketGateway) presenceWorker() {
+	defer g.wg.Done()
+
+	for {
+		select {
+		case <-g.shutdown:
+			return
+		case update := <-g.presenceQueue:
+			g.writePresence(update)
+		}
+	}
+}
+
+func (g *WebSocketGateway) writePresence(update presenceUpdate) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	if update.remove {
+		if err := g.presence.Remove(ctx, update.clientID); err != nil {
+			g.logger.Warn("Failed to remove presence", zap.String("client_id", update.clientID), zap.Error(err))
+		}
+		return
+	}
+
+	var worldID string
+	var x, y float64
+	if update.world != nil {
+		worldID = update.world.ID
+		if ent, ok := update.world.Engine.GetEntity(update.entityID); ok {
+			x, y = ent.Position.X, ent.Position.Y
+		}
+	}
+
+	var tokenHash string
+	if update.resumeToken != "" {
+		sum := sha256.Sum256([]byte(update.resumeToken))
+		tokenHash = hex.EncodeToString(sum[:])
+	}
+
+	if err := g.presence.Touch(ctx, update.clientID, worldID, update.entityID, x, y, tokenHash); err != nil {
+		g.logger.Warn("Failed to update presence", zap.String("client_id", update.clientID), zap.Error(err))
+	}
+}
+
+// directoryCaller authenticates a directory request. The caller names its
+// client ID in X-Aether-Client-Id and presents its current resume token as
+// a bearer token. It returns the caller's presence, or writes an error.
+func (g *WebSocketGateway) directoryCaller(w http.ResponseWriter, r *http.Request) (*redis.PresenceData, bool) {
+	clientID := r.Header.Get("X-Aether-Client-Id")
+	token, hasToken := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if clientID == "" || !hasToken || token == "" {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, "client id and resume token required", http.StatusUnauthorized)
+		return nil, false
+	}
+
+	caller, err := g.presence.Locate(r.Context(), clientID)
+	if err != nil {
+		g.logger.Error("Failed to locate caller", zap.String("client_id", clientID), zap.Error(err))
+		http.Error(w, "directory unavailable", http.StatusServiceUnavailable)
+		return nil, false
+	}
+
+	sum := sha256.Sum256([]byte(token))
+	if caller == nil || caller.TokenHash == "" ||
+		subtle.ConstantTimeCompare([]byte(caller.TokenHash), []byte(hex.EncodeToString(sum[:]))) != 1 {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, "invalid client id or resume token", http.StatusUnauthorized)
+		return nil, false
+	}
+
+	return caller, true
+}
+
+// GET /directory/clients/{client_id} locates a client in the caller's world.
+func (g *WebSocketGateway) handleLocateClient(w http.ResponseWriter, r *http.Request) {
+	clientID := strings.TrimPrefix(r.URL.Path, "/directory/clients/")
+	if clientID == "" {
+		http.Error(w, "client id required", http.StatusBadRequest)
+		return
+	}
+
+	caller, ok := g.directoryCaller(w, r)
+	if !ok {
+		return
+	}
+
+	presence, err := g.presence.Locate(r.Context(), clientID)
+	if err != nil {
+		g.logger.Error("Failed to locate client", zap.String("client_id", clientID), zap.Error(err))
+		http.Error(w, "directory unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	// Clients in other worlds are reported the same as offline ones
+	if presence == nil || presence.WorldID != caller.WorldID {
+		http.Error(w, "client not online", http.StatusNotFound)
+		return
+	}
+
+	writeJSON(w, presence)
+}
+
+// GET /directory/nearby?radius=..[&limit=..] lists the other clients in the
+// caller's world within radius of the caller.
+func (g *WebSocketGateway) handleNearby(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	radius, err := strconv.ParseFloat(query.Get("radius"), 64)
+	if err != nil || radius <= 0 {
+		http.Error(w, "a positive radius is required", http.StatusBadRequest)
+		return
+	}
+
+	limit := maxNearbyResults
+	if raw := query.Get("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			http.Error(w, "limit must be a positive integer", http.StatusBadRequest)
+			return
+		}
+		if parsed < limit {
+			limit = parsed
+		}
+	}
+
+	caller, ok := g.directoryCaller(w, r)
+	if !ok {
+		return
+	}
+
+	// One extra, since the caller finds itself
+	found, err := g.presence.Nearby(r.Context(), caller.WorldID, caller.PositionX, caller.PositionY, radius, limit+1)
+	if err != nil {
+		g.logger.Error("Failed to search nearby clients", zap.Error(err))
+		http.Error(w, "directory unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	nearby := make([]redis.PresenceData, 0, len(found))
+	for _, presence := range found {
+		if presence.ClientID != caller.ClientID && len(nearby) < limit {
+			nearby = append(nearby, presence)
+		}
+	}
+	writeJSON(w, nearby)
+}
+
+// GET /directory/instances
+func (g *WebSocketGateway) handleInstances(w http.ResponseWriter, r *http.Request) {
+	instances, err := g.presence.Instances(r.Context())
+	if err != nil {
+		g.logger.Error("Failed to list instances", zap.Error(err))
+		http.Error(w, "directory unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	if instances == nil {
+		instances = []string{}
+	}
+	writeJSON(w, instances)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,165 @@
+package gateway
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/akarsh-2004/aether/internal/config"
+	"github.com/akarsh-2004/aether/internal/persistence/redis"
+	"go.uber.org/zap"
+)
+
+// fakePresence is a directory of clients keyed by ID.
+type fakePresence struct {
+	clients map[string]*redis.PresenceData
+}
+
+func (p *fakePresence) Touch(ctx context.Context, clientID, worldID string, entityID uint32, x, y float64, tokenHash string) error {
+	return nil
+}
+
+func (p *fakePresence) Remove(ctx context.Context, clientID string) error { return nil }
+
+func (p *fakePresence) Locate(ctx context.Context, clientID string) (*redis.PresenceData, error) {
+	return p.clients[clientID], nil
+}
+
+// Nearby returns every client of the world in ID order; tests keep them
+// within range.
+func (p *fakePresence) Nearby(ctx context.Context, worldID string, x, y, radius float64, limit int) ([]redis.PresenceData, error) {
+	var found []redis.PresenceData
+	for _, id := range []string{"alice", "bob", "carol", "dave"} {
+		if c, ok := p.clients[id]; ok && c.WorldID == worldID && len(found) < limit {
+			found = append(found, *c)
+		}
+	}
+	return found, nil
+}
+
+func (p *fakePresence) Instances(ctx context.Context) ([]string, error) { return nil, nil }
+
+func tokenHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func directoryGateway() *WebSocketGateway {
+	g := NewWebSocketGateway(config.Default().Gateway, nil, zap.NewNop())
+	g.SetPresence(&fakePresence{clients: map[string]*redis.PresenceData{
+		"alice": {ClientID: "alice", WorldID: "arena", TokenHash: tokenHash("alice-token")},
+		"bob":   {ClientID: "bob", WorldID: "arena", PositionX: 3},
+		"carol": {ClientID: "carol", WorldID: "arena", PositionX: 4},
+		"dave":  {ClientID: "dave", WorldID: "lobby"},
+	}})
+	return g
+}
+
+func directoryRequest(g *WebSocketGateway, path, clientID, token string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodGet, path, nil)
+	if clientID != "" {
+		r.Header.Set("X-Aether-Client-Id", clientID)
+	}
+	if token != "" {
+		r.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	w := httptest.NewRecorder()
+	mux := http.NewServeMux()
+	g.registerDirectoryRoutes(mux)
+	mux.ServeHTTP(w, r)
+	return w
+}
+
+func TestDirectory_Auth(t *testing.T) {
+	g := directoryGateway()
+
+	tests := []struct {
+		name     string
+		clientID string
+		token    string
+		want     int
+	}{
+		{"no credentials", "", "", http.StatusUnauthorized},
+		{"no token", "alice", "", http.StatusUnauthorized},
+		{"wrong token", "alice", "bob-token", http.StatusUnauthorized},
+		{"unknown client", "mallory", "alice-token", http.StatusUnauthorized},
+		{"client without a token", "bob", "anything", http.StatusUnauthorized},
+		{"valid", "alice", "alice-token", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := directoryRequest(g, "/directory/clients/bob", tt.clientID, tt.token)
+			if w.Code != tt.want {
+				t.Errorf("expected status %d, got %d: %s", tt.want, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestDirectory_LocateScopedToWorld(t *testing.T) {
+	g := directoryGateway()
+
+	tests := []struct {
+		target string
+		want   int
+	}{
+		{"bob", http.StatusOK},
+		{"dave", http.StatusNotFound}, // another world
+		{"nobody", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			w := directoryRequest(g, "/directory/clients/"+tt.target, "alice", "alice-token")
+			if w.Code != tt.want {
+				t.Errorf("expected status %d, got %d", tt.want, w.Code)
+			}
+		})
+	}
+}
+
+func TestDirectory_NearbyExcludesCaller(t *testing.T) {
+	g := directoryGateway()
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"?radius=10", []string{"bob", "carol"}},
+		{"?radius=10&limit=1", []string{"bob"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			w := directoryRequest(g, "/directory/nearby"+tt.query, "alice", "alice-token")
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+			}
+
+			var found []redis.PresenceData
+			if err := json.NewDecoder(w.Body).Decode(&found); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if len(found) != len(tt.want) {
+				t.Fatalf("expected %v, got %+v", tt.want, found)
+			}
+			for i, id := range tt.want {
+				if found[i].ClientID != id {
+					t.Errorf("expected %v, got %+v", tt.want, found)
+				}
+			}
+		})
+	}
+
+	for _, query := range []string{"", "?radius=-1", "?radius=10&limit=0"} {
+		if w := directoryRequest(g, "/directory/nearby"+query, "alice", "alice-token"); w.Code != http.StatusBadRequest {
+			t.Errorf("%q: expected status 400, got %d", query, w.Code)
+		}
+	}
+}
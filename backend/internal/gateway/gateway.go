@@ -3,14 +3,23 @@ package gateway
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
+	"github.com/akarsh-2004/aether/internal/channels"
+	"github.com/akarsh-2004/aether/internal/chat"
 	"github.com/akarsh-2004/aether/internal/config"
 	"github.com/akarsh-2004/aether/internal/engine"
+	"github.com/akarsh-2004/aether/internal/engine/entity"
+	"github.com/akarsh-2004/aether/internal/observability"
 	"github.com/akarsh-2004/aether/internal/protocol"
+	"github.com/akarsh-2004/aether/internal/utils"
+	"github.com/akarsh-2004/aether/internal/world"
 	"github.com/akarsh-2004/aether/proto"
 	"go.uber.org/zap"
 )
@@ -20,31 +29,54 @@ var (
 	ErrMessageTooLarge  = errors.New("message too large")
 )
 
+// SessionStore records connection lifetimes. StartGameSession and
+// EndGameSession also queue session_started / session_ended outbox events.
+// A session starts on connect, before the client has an entity;
+// SetSessionEntity records the entity once it spawns or resumes.
+type SessionStore interface {
+	StartGameSession(ctx context.Context, sessionID, clientID string, entityID *uint32) error
+	SetSessionEntity(ctx context.Context, sessionID string, entityID uint32) error
+	EndGameSession(ctx context.Context, sessionID string) error
+}
+
 type WebSocketGateway struct {
-	config    config.GatewayConfig
-	engine    *engine.SpatialEngine
-	codec     *protocol.Codec
-	logger    *zap.Logger
-	upgrader  websocket.Upgrader
-	clients   sync.Map // map[string]*Client
-	shutdown  chan struct{}
-	wg        sync.WaitGroup
+	config        config.GatewayConfig
+	worlds        *world.Manager
+	sessions      SessionStore
+	presence      Presence
+	presenceQueue chan presenceUpdate // drained by presenceWorker
+	lobby         Lobby
+	broker        *channels.Broker
+	chat          *chat.Service
+	metrics       *observability.Metrics
+	codec         *protocol.Codec
+	logger        *zap.Logger
+	upgrader      websocket.Upgrader
+	clients       sync.Map // map[string]*Client
+	draining      atomic.Bool
+	shutdown      chan struct{}
+	wg            sync.WaitGroup
 }
 
 type Client struct {
-	id         string
-	conn       *websocket.Conn
-	sendChan   chan []byte
-	closeChan  chan struct{}
-	entityID   uint32
-	lastSeq    uint64
-	mu         sync.RWMutex
+	id          string
+	sessionID   string
+	conn        *websocket.Conn
+	sendChan    chan []byte
+	closeChan   chan struct{}
+	closeOnce   sync.Once
+	world       *world.World
+	entityID    uint32
+	resumeToken string // secret the client presents to resume its session elsewhere
+	lastSeq     uint64
+	latency     Latency // measured from heartbeat echoes, see clock.go
+	mu          sync.RWMutex
 }
 
-func NewWebSocketGateway(cfg config.GatewayConfig, eng *engine.SpatialEngine, logger *zap.Logger) *WebSocketGateway {
+func NewWebSocketGateway(cfg config.GatewayConfig, worlds *world.Manager, logger *zap.Logger) *WebSocketGateway {
 	return &WebSocketGateway{
 		config:   cfg,
-		engine:   eng,
+		worlds:   worlds,
 		codec:    protocol.NewCodec(),
 		logger:   logger,
 		shutdown: make(chan struct{}),
@@ -59,9 +91,19 @@ func NewWebSocketGateway(cfg config.GatewayConfig, eng *engine.SpatialEngine, lo
 	}
 }
 
+// SetSessionStore enables game session tracking. It should be called before Start.
+func (g *WebSocketGateway) SetSessionStore(store SessionStore) {
+	g.sessions = store
+}
+
 func (g *WebSocketGateway) Start(ctx context.Context) error {
 	mux := http.NewServeMux()
 	mux.HandleFunc("/ws", g.handleWebSocket)
+	mux.HandleFunc("/readyz", g.handleReady)
+	g.registerWorldRoutes(mux)
+	if g.presence != nil {
+		g.registerDirectoryRoutes(mux)
+	}
 
 	server := &http.Server{
 		Addr:    g.config.BindAddr,
@@ -78,15 +120,67 @@ func (g *WebSocketGateway) Start(ctx context.Context) error {
 		}
 	}()
 
+	if g.presence != nil {
+		g.wg.Add(1)
+		go g.presenceWorker()
+	}
+
+	admin := g.startAdminServer()
+
 	<-ctx.Done()
 	g.logger.Info("WebSocket gateway shutting down")
 	
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
+
+	if admin != nil {
+		if err := admin.Shutdown(shutdownCtx); err != nil {
+			g.logger.Warn("Admin server shutdown incomplete", zap.Error(err))
+		}
+	}
 	
 	return server.Shutdown(shutdownCtx)
 }
 
+// startAdminServer serves the operator routes on AdminBindAddr, apart from
+// the public listener clients connect to. Returns nil when it is disabled.
+func (g *WebSocketGateway) startAdminServer() *http.Server {
+	if g.config.AdminBindAddr == "" {
+		g.logger.Info("Admin listener disabled")
+		return nil
+	}
+
+	mux := http.NewServeMux()
+	g.registerWorldAdminRoutes(mux)
+	g.registerClientRoutes(mux)
+	if g.presence != nil {
+		g.registerDirectoryAdminRoutes(mux)
+	}
+	if g.broker != nil {
+		g.registerChannelRoutes(mux)
+	}
+	if g.chat != nil {
+		g.registerChatRoutes(mux)
+	}
+
+	server := &http.Server{
+		Addr:    g.config.AdminBindAddr,
+		Handler: mux,
+	}
+
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		g.logger.Info("Admin listener starting", zap.String("addr", g.config.AdminBindAddr))
+
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			g.logger.Error("Admin server error", zap.Error(err))
+		}
+	}()
+
+	return server
+}
+
 func (g *WebSocketGateway) Shutdown(ctx context.Context) error {
 	close(g.shutdown)
 	
@@ -111,7 +205,68 @@ func (g *WebSocketGateway) Shutdown(ctx context.Context) error {
 	}
 }
 
+// Drain stops accepting new connections and asks every connected client to
+// reconnect to reconnectTo. It returns once all clients have left or ctx
+// expires; entities of clients that leave while draining are kept so they can
+// be snapshotted and resumed by the next process.
+func (g *WebSocketGateway) Drain(ctx context.Context, reconnectTo string) error {
+	if !g.draining.CompareAndSwap(false, true) {
+		return fmt.Errorf("gateway is already draining")
+	}
+
+	notified := 0
+	g.clients.Range(func(key, value interface{}) bool {
+		if client, ok := value.(*Client); ok {
+			g.sendReconnect(client, reconnectTo, "server_draining", uint64(g.config.DrainTimeout)*1000)
+			notified++
+		}
+		return true
+	})
+
+	g.logger.Info("Gateway draining",
+		zap.String("reconnect_to", reconnectTo),
+		zap.Int("clients_notified", notified),
+	)
+
+	ticker := time.NewTicker(250 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		if g.connectedClients() == 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			g.logger.Warn("Drain timed out with clients still connected",
+				zap.Int("remaining_clients", g.connectedClients()),
+			)
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// IsDraining reports whether the gateway has stopped accepting new clients.
+func (g *WebSocketGateway) IsDraining() bool {
+	return g.draining.Load()
+}
+
+func (g *WebSocketGateway) handleReady(w http.ResponseWriter, r *http.Request) {
+	if g.draining.Load() {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
 func (g *WebSocketGateway) handleWebSocket(w http.ResponseWriter, r *http.Request) {
+	if g.draining.Load() {
+		w.Header().Set("Retry-After", strconv.Itoa(g.config.DrainTimeout))
+		http.Error(w, "server is draining", http.StatusServiceUnavailable)
+		return
+	}
+
 	conn, err := g.upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		g.logger.Error("Failed to upgrade WebSocket connection", zap.Error(err))
@@ -121,13 +276,16 @@ func (g *WebSocketGateway) handleWebSocket(w http.ResponseWriter, r *http.Reques
 	clientID := g.generateClientID()
 	client := &Client{
 		id:        clientID,
+		sessionID: utils.GenerateSessionID(),
 		conn:      conn,
 		sendChan:  make(chan []byte, 256), // Buffered channel for non-blocking sends
 		closeChan: make(chan struct{}),
 	}
 
 	g.clients.Store(clientID, client)
-	g.logger.Info("Client connected", zap.String("client_id", clientID))
+	g.logger.Info("Client connected", zap.String("client_id", clientID), zap.String("session_id", client.sessionID))
+
+	g.startSession(client)
 
 	g.wg.Add(2)
 	go g.readPump(client)
@@ -137,13 +295,39 @@ func (g *WebSocketGateway) handleWebSocket(w http.ResponseWriter, r *http.Reques
 func (g *WebSocketGateway) readPump(client *Client) {
 	defer g.wg.Done()
 	defer func() {
-		client.Close()
+		// Unregister first so engine, channel and lobby senders stop finding it
 		g.clients.Delete(client.id)
+		client.Close()
 		g.logger.Info("Client disconnected", zap.String("client_id", client.id))
 		
-		if client.entityID != 0 {
-			g.engine.RemoveEntity(client.entityID)
+		if client.world != nil {
+			// While draining, keep the entity so it is snapshotted and can be resumed
+			if !g.draining.Load() {
+				// An avatar the client gave away belongs to its new controller
+				if client.entityID != 0 {
+					client.world.Engine.RemoveControlledEntity(client.entityID, client.id, "client_disconnected")
+				}
+				// Possessed and transferred entities stay in the world uncontrolled
+				client.world.Engine.ReleaseClient(client.id)
+			}
+			client.world.Engine.SetClientLatency(client.id, 0)
+			g.worlds.Leave(client.world)
+		}
+
+		if g.lobby != nil {
+			g.leaveQueue(client.id)
+		}
+
+		if g.broker != nil {
+			g.broker.RemoveClient(client.id)
+		}
+
+		if g.chat != nil {
+			g.chat.Disconnect(client.id)
 		}
+
+		g.removePresence(client.id)
+		g.endSession(client)
 	}()
 
 	client.conn.SetReadLimit(int64(g.config.MaxMessageSize))
@@ -197,13 +381,8 @@ func (g *WebSocketGateway) writePump(client *Client) {
 			return
 		case <-client.closeChan:
 			return
-		case message, ok := <-client.sendChan:
+		case message := <-client.sendChan:
 			client.conn.SetWriteDeadline(time.Now().Add(time.Duration(g.config.WriteWait) * time.Second))
-			if !ok {
-				client.conn.WriteMessage(websocket.CloseMessage, []byte{})
-				return
-			}
-
 			if err := client.conn.WriteMessage(websocket.BinaryMessage, message); err != nil {
 				g.logger.Error("Failed to write message", zap.String("client_id", client.id), zap.Error(err))
 				return
@@ -239,6 +418,39 @@ func (g *WebSocketGateway) handleMessage(client *Client, data []byte) {
 		
 	case proto.MessageType_HEARTBEAT:
 		g.handleHeartbeat(client, msg.Heartbeat)
+
+	case proto.MessageType_QUEUE_REQUEST:
+		g.handleQueueRequest(client, msg.QueueRequest)
+
+	case proto.MessageType_LEAVE_QUEUE:
+		g.handleLeaveQueue(client, msg.LeaveQueue)
+
+	case proto.MessageType_CONTROL_REQUEST:
+		g.handleControlRequest(client, msg.ControlRequest)
+
+	case proto.MessageType_SUBSCRIBE:
+		g.handleSubscribe(client, msg.Subscribe)
+
+	case proto.MessageType_UNSUBSCRIBE:
+		g.handleUnsubscribe(client, msg.Unsubscribe)
+
+	case proto.MessageType_PUBLISH:
+		g.handlePublish(client, msg.Publish)
+
+	case proto.MessageType_CHAT_SEND:
+		g.handleChatSend(client, msg.ChatSend)
+
+	case proto.MessageType_CHAT_MUTE:
+		g.handleChatMute(client, msg.ChatMute)
+
+	case proto.MessageType_INPUT_COMMAND:
+		g.handleInputCommand(client, msg.InputCommand)
+
+	case proto.MessageType_FIRE:
+		g.handleFire(client, msg.Fire)
+
+	case proto.MessageType_SCRIPT_MESSAGE:
+		g.handleScriptMessage(client, msg.ScriptMessage)
 		
 	default:
 		g.logger.Warn("Unhandled message type", zap.String("client_id", client.id), zap.String("type", msg.Type.String()))
@@ -249,18 +461,93 @@ func (g *WebSocketGateway) handleMovementDelta(client *Client, delta *proto.Move
 	client.mu.Lock()
 	defer client.mu.Unlock()
 
-	if client.entityID == 0 {
+	if client.world == nil {
 		g.logger.Warn("Movement delta from unspawned client", zap.String("client_id", client.id))
 		return
 	}
 
+	// Deltas without an entity move the client's own entity
+	if delta.EntityId == 0 {
+		delta.EntityId = client.entityID
+	}
+
 	// Update last sequence number for reconciliation
 	if delta.Sequence > client.lastSeq {
 		client.lastSeq = delta.Sequence
 	}
 
-	// Forward movement intent to engine
-	g.engine.ProcessMovementIntent(client.entityID, delta)
+	// Forward movement intent to the client's world
+	client.world.Engine.ProcessMovementIntent(client.id, delta)
+}
+
+func (g *WebSocketGateway) handleInputCommand(client *Client, cmd *proto.InputCommand) {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+
+	if client.world == nil {
+		g.logger.Warn("Input command from unspawned client", zap.String("client_id", client.id))
+		return
+	}
+
+	// Commands without an entity drive the client's own entity
+	if cmd.EntityId == 0 {
+		cmd.EntityId = client.entityID
+	}
+
+	if cmd.Sequence > client.lastSeq {
+		client.lastSeq = cmd.Sequence
+	}
+
+	client.world.Engine.ProcessInput(client.id, cmd)
+}
+
+func (g *WebSocketGateway) handleFire(client *Client, req *proto.Fire) {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+
+	if client.world == nil {
+		g.sendFireResult(client, req.ClientMsgId, 0, "client not spawned")
+		return
+	}
+
+	// Fire without an entity shoots from the client's own entity
+	shooterID := req.EntityId
+	if shooterID == 0 {
+		shooterID = client.entityID
+	}
+
+	direction := entity.Vector3{X: float64(req.DirectionX), Y: float64(req.DirectionY), Z: float64(req.DirectionZ)}
+	projectileID, err := client.world.Engine.FireProjectile(client.id, shooterID, req.ProjectileType, direction)
+	if err != nil {
+		g.logger.Debug("Fire rejected", zap.String("client_id", client.id), zap.Error(err))
+		g.sendFireResult(client, req.ClientMsgId, 0, err.Error())
+		return
+	}
+
+	g.sendFireResult(client, req.ClientMsgId, projectileID, "")
+}
+
+func (g *WebSocketGateway) handleScriptMessage(client *Client, msg *proto.ScriptMessage) {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+
+	if client.world == nil {
+		g.logger.Warn("Script message from unspawned client", zap.String("client_id", client.id))
+		return
+	}
+
+	entityID := msg.EntityId
+	if entityID == 0 {
+		entityID = client.entityID
+	}
+
+	if err := client.world.Engine.HandleScriptMessage(client.id, entityID, msg.Name, msg.Payload); err != nil {
+		g.logger.Debug("Script message rejected",
+			zap.String("client_id", client.id),
+			zap.String("name", msg.Name),
+			zap.Error(err),
+		)
+	}
 }
 
 func (g *WebSocketGateway) handleSpawnRequest(client *Client, req *proto.SpawnRequest) {
@@ -269,26 +556,143 @@ func (g *WebSocketGateway) handleSpawnRequest(client *Client, req *proto.SpawnRe
 
 	if client.entityID != 0 {
 		g.logger.Warn("Spawn request from already spawned client", zap.String("client_id", client.id))
-		g.sendSpawnResponse(client, false, 0, "client already spawned", 0, 0)
+		g.sendSpawnResponse(client, false, 0, "client already spawned", entity.Vector3{})
 		return
 	}
 
-	entityID := g.engine.SpawnEntity(req.EntityType, req.SpawnX, req.SpawnY, client.id)
-	if entityID == 0 {
-		g.sendSpawnResponse(client, false, 0, "failed to spawn entity", 0, 0)
+	if req.JoinTicket != "" {
+		w, err := g.redeemTicket(req.JoinTicket)
+		if err != nil {
+			g.logger.Warn("Failed to redeem join ticket", zap.String("client_id", client.id), zap.Error(err))
+			g.sendSpawnResponse(client, false, 0, err.Error(), entity.Vector3{})
+			return
+		}
+		if client.world != nil {
+			g.worlds.Leave(client.world)
+		}
+		client.world = w
+	}
+
+	// A client keeps its world across failed spawns and only joins once
+	if client.world == nil {
+		w, err := g.worlds.Join(req.WorldId, req.WorldTemplate)
+		if err != nil {
+			g.logger.Warn("Failed to join world",
+				zap.String("client_id", client.id),
+				zap.String("world_id", req.WorldId),
+				zap.String("world_template", req.WorldTemplate),
+				zap.Error(err),
+			)
+			g.sendSpawnResponse(client, false, 0, err.Error(), entity.Vector3{})
+			return
+		}
+		client.world = w
+	}
+	eng := client.world.Engine
+
+	// A client reconnecting after a drain or handoff presents its previous
+	// client ID and the resume token it was given
+	if req.ClientId != "" && req.ClientId != client.id {
+		if ent, resumed := eng.ResumeEntity(req.ClientId, req.ResumeToken, client.id); resumed {
+			client.entityID = ent.ID
+			g.issueResumeToken(client)
+			g.resumeChat(req.ClientId, client.id)
+			g.setSessionEntity(client)
+			g.sendSpawnResponse(client, true, ent.ID, "", ent.Position)
+			g.touchPresence(client.world, client.id, ent.ID, client.resumeToken)
+			return
+		}
+	}
+
+	// The world's spawn policy decides where the entity appears
+	entityID, err := eng.SpawnClientEntity(req.EntityType, client.id, engine.SpawnOptions{
+		Point:       req.SpawnPoint,
+		Position:    entity.Vector3{X: float64(req.SpawnX), Y: float64(req.SpawnY), Z: float64(req.SpawnZ)},
+		UsePosition: req.UsePosition,
+	})
+	if err != nil {
+		g.logger.Warn("Failed to spawn entity", zap.String("client_id", client.id), zap.Error(err))
+		g.sendSpawnResponse(client, false, 0, err.Error(), entity.Vector3{})
 		return
 	}
 
 	client.entityID = entityID
-	g.sendSpawnResponse(client, true, entityID, "", req.SpawnX, req.SpawnY)
+	g.issueResumeToken(client)
+	g.setSessionEntity(client)
+
+	// Report where the entity actually stands
+	ent, _ := eng.GetEntity(entityID)
+	g.sendSpawnResponse(client, true, entityID, "", ent.Position)
+	g.touchPresence(client.world, client.id, entityID, client.resumeToken)
+}
+
+// issueResumeToken gives the client a new resume token for its avatar. The
+// caller must hold client.mu. Without one the session just can't be resumed.
+func (g *WebSocketGateway) issueResumeToken(client *Client) {
+	token, err := client.world.Engine.IssueResumeToken(client.id)
+	if err != nil {
+		g.logger.Warn("Failed to issue resume token", zap.String("client_id", client.id), zap.Error(err))
+		token = ""
+	}
+	client.resumeToken = token
 }
 
 func (g *WebSocketGateway) handleHeartbeat(client *Client, heartbeat *proto.Heartbeat) {
-	// TODO: Update last heartbeat time for client
+	received := time.Now()
 	g.logger.Debug("Received heartbeat", zap.String("client_id", client.id))
+
+	client.mu.RLock()
+	w, entityID, resumeToken := client.world, client.entityID, client.resumeToken
+	client.mu.RUnlock()
+
+	g.observeClock(client, heartbeat, received)
+	g.sendTimeSync(client, w, heartbeat.Timestamp, received)
+	g.touchPresence(w, client.id, entityID, resumeToken)
+}
+
+// sendTimeSync echoes a heartbeat with the server's clock and, once the
+// client is in a world, its tick timing.
+func (g *WebSocketGateway) sendTimeSync(client *Client, w *world.World, clientTime uint64, received time.Time) {
+	timeSync := &proto.TimeSync{
+		ClientTime:        clientTime,
+		ServerReceiveTime: uint64(received.UnixMilli()),
+	}
+
+	if w != nil {
+		tick, started, rate := w.Engine.TickTime()
+		timeSync.ServerTick = tick
+		timeSync.ServerTickTime = uint64(started.UnixMilli())
+		timeSync.TickRateMs = uint32(rate.Milliseconds())
+		timeSync.InterpolationDelayMs = uint32(w.Engine.InterpolationDelay().Milliseconds())
+	}
+
+	message := &proto.Message{
+		Type: proto.MessageType_TIME_SYNC,
+		Payload: &proto.Message_TimeSync{
+			TimeSync: timeSync,
+		},
+	}
+
+	// Stamped as late as possible so encoding time counts as server time
+	timeSync.ServerSendTime = uint64(time.Now().UnixMilli())
+	data, err := g.codec.Encode(message)
+	if err != nil {
+		g.logger.Error("Failed to encode time sync", zap.String("client_id", client.id), zap.Error(err))
+		return
+	}
+
+	g.trySend(client, data, "time sync")
 }
 
-func (g *WebSocketGateway) sendSpawnResponse(client *Client, success bool, entityID uint32, errorMsg string, x, y float64) {
+func (g *WebSocketGateway) sendSpawnResponse(client *Client, success bool, entityID uint32, errorMsg string, pos entity.Vector3) {
+	var worldID, resumeToken string
+	if client.world != nil {
+		worldID = client.world.ID
+	}
+	if success {
+		resumeToken = client.resumeToken
+	}
+
 	response := &proto.Message{
 		Type: proto.MessageType_SPAWN_RESPONSE,
 		Payload: &proto.Message_SpawnResponse{
@@ -296,8 +700,12 @@ func (g *WebSocketGateway) sendSpawnResponse(client *Client, success bool, entit
 				Success:      success,
 				EntityId:     entityID,
 				ErrorMessage: errorMsg,
-				SpawnX:       x,
-				SpawnY:       y,
+				SpawnX:       float32(pos.X),
+				SpawnY:       float32(pos.Y),
+				SpawnZ:       float32(pos.Z),
+				WorldId:      worldID,
+				ResumeToken:  resumeToken,
+				ClientId:     client.id,
 			},
 		},
 	}
@@ -308,21 +716,165 @@ func (g *WebSocketGateway) sendSpawnResponse(client *Client, success bool, entit
 		return
 	}
 
-	select {
-	case client.sendChan <- data:
-	default:
-		g.logger.Warn("Send buffer full, dropping spawn response", zap.String("client_id", client.id))
+	g.trySend(client, data, "spawn response")
+}
+
+func (g *WebSocketGateway) sendFireResult(client *Client, msgID uint64, projectileID uint32, errorMsg string) {
+	response := &proto.Message{
+		Type: proto.MessageType_FIRE_RESULT,
+		Payload: &proto.Message_FireResult{
+			FireResult: &proto.FireResult{
+				ClientMsgId:  msgID,
+				ProjectileId: projectileID,
+				ErrorMessage: errorMsg,
+			},
+		},
+	}
+
+	data, err := g.codec.Encode(response)
+	if err != nil {
+		g.logger.Error("Failed to encode fire result", zap.String("client_id", client.id), zap.Error(err))
+		return
+	}
+
+	g.trySend(client, data, "fire result")
+}
+
+func (g *WebSocketGateway) startSession(client *Client) {
+	if g.sessions == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := g.sessions.StartGameSession(ctx, client.sessionID, client.id, nil); err != nil {
+		g.logger.Error("Failed to start game session",
+			zap.String("client_id", client.id),
+			zap.String("session_id", client.sessionID),
+			zap.Error(err),
+		)
+	}
+}
+
+// setSessionEntity records the client's entity on its session. The caller
+// must hold client.mu.
+func (g *WebSocketGateway) setSessionEntity(client *Client) {
+	if g.sessions == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := g.sessions.SetSessionEntity(ctx, client.sessionID, client.entityID); err != nil {
+		g.logger.Error("Failed to record session entity",
+			zap.String("client_id", client.id),
+			zap.String("session_id", client.sessionID),
+			zap.Uint32("entity_id", client.entityID),
+			zap.Error(err),
+		)
+	}
+}
+
+func (g *WebSocketGateway) endSession(client *Client) {
+	if g.sessions == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := g.sessions.EndGameSession(ctx, client.sessionID); err != nil {
+		g.logger.Error("Failed to end game session",
+			zap.String("client_id", client.id),
+			zap.String("session_id", client.sessionID),
+			zap.Error(err),
+		)
+	}
+}
+
+// HandOff tells a client that its entity moved to another region and it
+// should reconnect to reconnectTo and resume there.
+func (g *WebSocketGateway) HandOff(clientID string, entityID uint32, reconnectTo string) {
+	value, ok := g.clients.Load(clientID)
+	if !ok {
+		return
+	}
+
+	client := value.(*Client)
+	g.logger.Info("Handing client off to another region",
+		zap.String("client_id", clientID),
+		zap.Uint32("entity_id", entityID),
+		zap.String("reconnect_to", reconnectTo),
+	)
+	g.sendReconnect(client, reconnectTo, "region_handoff", 0)
+}
+
+// SendToClient encodes an engine message and queues it on the client's
+// connection, if the client is connected here. It is installed as every
+// world's engine.Sender.
+func (g *WebSocketGateway) SendToClient(clientID string, message *proto.Message) {
+	value, ok := g.clients.Load(clientID)
+	if !ok {
+		return
+	}
+	client := value.(*Client)
+
+	data, err := g.codec.Encode(message)
+	if err != nil {
+		g.logger.Error("Failed to encode engine message",
+			zap.String("client_id", clientID),
+			zap.String("message_type", message.Type.String()),
+			zap.Error(err),
+		)
+		return
+	}
+
+	g.trySend(client, data, "engine message", zap.String("message_type", message.Type.String()))
+}
+
+func (g *WebSocketGateway) sendReconnect(client *Client, reconnectTo, reason string, retryAfterMs uint64) {
+	client.mu.RLock()
+	entityID, resumeToken := client.entityID, client.resumeToken
+	client.mu.RUnlock()
+
+	message := &proto.Message{
+		Type: proto.MessageType_RECONNECT,
+		Payload: &proto.Message_Reconnect{
+			Reconnect: &proto.Reconnect{
+				ReconnectTo:  reconnectTo,
+				ResumeToken:  resumeToken,
+				EntityId:     entityID,
+				RetryAfterMs: retryAfterMs,
+				Reason:       reason,
+				ClientId:     client.id,
+			},
+		},
 	}
+
+	data, err := g.codec.Encode(message)
+	if err != nil {
+		g.logger.Error("Failed to encode reconnect message", zap.String("client_id", client.id), zap.Error(err))
+		return
+	}
+
+	g.trySend(client, data, "reconnect message")
+}
+
+func (g *WebSocketGateway) connectedClients() int {
+	count := 0
+	g.clients.Range(func(key, value interface{}) bool {
+		count++
+		return true
+	})
+	return count
 }
 
 func (g *WebSocketGateway) BroadcastToClient(clientID string, data []byte) {
 	if client, ok := g.clients.Load(clientID); ok {
 		if c, ok := client.(*Client); ok {
-			select {
-			case c.sendChan <- data:
-			default:
-				g.logger.Warn("Send buffer full, dropping message", zap.String("client_id", clientID))
-			}
+			g.trySend(c, data, "message")
 		}
 	}
 }
@@ -331,10 +883,33 @@ func (g *WebSocketGateway) generateClientID() string {
 	return fmt.Sprintf("client_%d_%d", time.Now().UnixNano(), time.Now().Unix())
 }
 
+// Close stops both pumps. It is safe to call more than once. sendChan is
+// never closed, so senders on other goroutines cannot panic; see trySend.
 func (c *Client) Close() {
-	close(c.closeChan)
-	if c.conn != nil {
-		c.conn.Close()
+	c.closeOnce.Do(func() {
+		close(c.closeChan)
+		if c.conn != nil {
+			c.conn.Close()
+		}
+	})
+}
+
+// trySend queues data on a client's connection without blocking and reports
+// whether it was queued. Engine workers, channel fan-out, chat, the lobby and
+// handoffs all send through it; once the client is closed it drops silently.
+func (g *WebSocketGateway) trySend(client *Client, data []byte, what string, fields ...zap.Field) bool {
+	select {
+	case <-client.closeChan:
+		return false
+	default:
+	}
+
+	select {
+	case client.sendChan <- data:
+		return true
+	default:
+		fields = append([]zap.Field{zap.String("client_id", client.id)}, fields...)
+		g.logger.Warn("Send buffer full, dropping "+what, fields...)
+		return false
 	}
-	close(c.sendChan)
 }
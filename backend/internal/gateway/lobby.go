@@ -0,0 +1,137 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/akarsh-2004/aether/internal/lobby"
+	"github.com/akarsh-2004/aether/internal/world"
+	"github.com/akarsh-2004/aether/proto"
+	"go.uber.org/zap"
+)
+
+// Lobby queues clients for matchmaking and issues join tickets.
+type Lobby interface {
+	Enqueue(ctx context.Context, entry *lobby.Entry) error
+	Leave(ctx context.Context, clientID string) error
+	Redeem(ctx context.Context, ticketID string) (*lobby.Ticket, error)
+}
+
+// SetLobby enables QUEUE_REQUEST / LEAVE_QUEUE and join tickets. It should
+// be called before Start.
+func (g *WebSocketGateway) SetLobby(l Lobby) {
+	g.lobby = l
+}
+
+func (g *WebSocketGateway) handleQueueRequest(client *Client, req *proto.QueueRequest) {
+	if g.lobby == nil {
+		g.sendLobbyStatus(client, &lobby.Notice{Queue: req.Queue, State: lobby.StateRejected, Reason: "matchmaking disabled"})
+		return
+	}
+
+	client.mu.RLock()
+	spawned := client.entityID != 0
+	client.mu.RUnlock()
+
+	if spawned {
+		g.sendLobbyStatus(client, &lobby.Notice{Queue: req.Queue, State: lobby.StateRejected, Reason: "client already spawned"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	err := g.lobby.Enqueue(ctx, &lobby.Entry{
+		ClientID:  client.id,
+		Queue:     req.Queue,
+		Region:    req.Region,
+		Skill:     float64(req.Skill),
+		PartyID:   req.PartyId,
+		PartySize: int(req.PartySize),
+	})
+	if err != nil {
+		g.logger.Warn("Failed to queue client", zap.String("client_id", client.id), zap.String("queue", req.Queue), zap.Error(err))
+		g.sendLobbyStatus(client, &lobby.Notice{Queue: req.Queue, State: lobby.StateRejected, Reason: err.Error()})
+		return
+	}
+
+	g.sendLobbyStatus(client, &lobby.Notice{Queue: req.Queue, State: lobby.StateQueued})
+}
+
+func (g *WebSocketGateway) handleLeaveQueue(client *Client, req *proto.LeaveQueue) {
+	if g.lobby == nil {
+		return
+	}
+
+	if err := g.leaveQueue(client.id); err != nil {
+		if !errors.Is(err, lobby.ErrNotQueued) {
+			g.logger.Warn("Failed to remove client from queue", zap.String("client_id", client.id), zap.Error(err))
+		}
+		return
+	}
+
+	g.sendLobbyStatus(client, &lobby.Notice{Queue: req.Queue, State: lobby.StateLeft})
+}
+
+func (g *WebSocketGateway) leaveQueue(clientID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	return g.lobby.Leave(ctx, clientID)
+}
+
+// redeemTicket joins the world a lobby ticket was issued for.
+func (g *WebSocketGateway) redeemTicket(ticketID string) (*world.World, error) {
+	if g.lobby == nil {
+		return nil, lobby.ErrInvalidTicket
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	ticket, err := g.lobby.Redeem(ctx, ticketID)
+	if err != nil {
+		return nil, err
+	}
+	return g.worlds.JoinReserved(ticket.WorldID)
+}
+
+// NotifyLobby forwards a lobby notice to a client connected here.
+func (g *WebSocketGateway) NotifyLobby(notice *lobby.Notice) {
+	value, ok := g.clients.Load(notice.ClientID)
+	if !ok {
+		return
+	}
+
+	g.sendLobbyStatus(value.(*Client), notice)
+}
+
+func (g *WebSocketGateway) sendLobbyStatus(client *Client, notice *lobby.Notice) {
+	status := &proto.LobbyStatus{
+		State:       notice.State,
+		Queue:       notice.Queue,
+		JoinTicket:  notice.Ticket,
+		WorldId:     notice.WorldID,
+		ReconnectTo: notice.ReconnectTo,
+		Reason:      notice.Reason,
+	}
+	if !notice.ExpiresAt.IsZero() {
+		status.ExpiresAtMs = uint64(notice.ExpiresAt.UnixMilli())
+	}
+
+	message := &proto.Message{
+		Type: proto.MessageType_LOBBY_STATUS,
+		Payload: &proto.Message_LobbyStatus{
+			LobbyStatus: status,
+		},
+	}
+
+	data, err := g.codec.Encode(message)
+	if err != nil {
+		g.logger.Error("Failed to encode lobby status", zap.String("client_id", client.id), zap.Error(err))
+		return
+	}
+
+	g.trySend(client, data, "lobby status")
+}
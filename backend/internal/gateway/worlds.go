@@ -0,0 +1,122 @@
+package gateway
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/akarsh-2004/aether/internal/world"
+	"go.uber.org/zap"
+)
+
+// registerWorldRoutes adds the read-only world routes to the public
+// listener.
+func (g *WebSocketGateway) registerWorldRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/worlds", readOnly(g.handleWorlds))
+	mux.HandleFunc("/worlds/", readOnly(g.handleWorld))
+}
+
+// registerWorldAdminRoutes adds every world route to the admin listener,
+// including creating and removing worlds and revoking control.
+func (g *WebSocketGateway) registerWorldAdminRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/worlds", g.handleWorlds)
+	mux.HandleFunc("/worlds/", g.handleWorld)
+}
+
+// readOnly refuses every method but GET.
+func readOnly(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", "GET")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		handler(w, r)
+	}
+}
+
+// GET /worlds lists worlds; POST /worlds {"id": "", "template": "arena"}
+// creates one.
+func (g *WebSocketGateway) handleWorlds(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, g.worlds.List())
+
+	case http.MethodPost:
+		var req struct {
+			ID       string `json:"id"`
+			Template string `json:"template"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Template == "" {
+			http.Error(w, "template is required", http.StatusBadRequest)
+			return
+		}
+
+		created, err := g.worlds.Create(req.ID, req.Template)
+		if err != nil {
+			g.writeWorldError(w, err)
+			return
+		}
+
+		info, _ := g.worlds.Info(created.ID)
+		w.WriteHeader(http.StatusCreated)
+		writeJSON(w, info)
+
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// GET /worlds/{id} describes a world; DELETE /worlds/{id} removes an empty
+// on-demand world. Entity routes below a world are handled separately.
+func (g *WebSocketGateway) handleWorld(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, "/worlds/")
+	if id == "" {
+		http.Error(w, "world id required", http.StatusBadRequest)
+		return
+	}
+
+	if worldID, rest, found := strings.Cut(id, "/entities/"); found {
+		g.handleRevokeControl(w, r, worldID, rest)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		info, exists := g.worlds.Info(id)
+		if !exists {
+			http.Error(w, "world not found", http.StatusNotFound)
+			return
+		}
+		writeJSON(w, info)
+
+	case http.MethodDelete:
+		if err := g.worlds.Remove(r.Context(), id); err != nil {
+			g.writeWorldError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		w.Header().Set("Allow", "GET, DELETE")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (g *WebSocketGateway) writeWorldError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, world.ErrWorldNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, world.ErrUnknownTemplate):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, world.ErrWorldExists), errors.Is(err, world.ErrWorldOccupied), errors.Is(err, world.ErrWorldPersistent):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, world.ErrTooManyWorlds):
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+	default:
+		g.logger.Error("World operation failed", zap.Error(err))
+		http.Error(w, "internal error", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,94 @@
+package lobby
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+)
+
+var (
+	ErrUnknownQueue  = errors.New("unknown queue")
+	ErrAlreadyQueued = errors.New("already queued")
+	ErrNotQueued     = errors.New("not queued")
+	ErrInvalidTicket = errors.New("invalid or expired join ticket")
+	ErrWrongInstance = errors.New("join ticket belongs to another instance")
+)
+
+// Lobby states reported to clients.
+const (
+	StateQueued   = "queued"
+	StateMatched  = "matched"
+	StateTimedOut = "timed_out"
+	StateLeft     = "left"
+	StateRejected = "rejected"
+)
+
+// Entry is one queued client. Entries are keyed by client ID, so a client
+// can wait in one queue at a time.
+type Entry struct {
+	ClientID   string    `json:"client_id"`
+	Queue      string    `json:"queue"`
+	InstanceID string    `json:"instance_id"` // Gateway the client is connected to
+	Region     string    `json:"region"`
+	Skill      float64   `json:"skill"`
+	PartyID    string    `json:"party_id,omitempty"`
+	PartySize  int       `json:"party_size,omitempty"`
+	EnqueuedAt time.Time `json:"enqueued_at"`
+}
+
+// Ticket admits one matched client into a reserved world. Tickets are
+// single-use and redeemed on the instance hosting the world.
+type Ticket struct {
+	ID         string    `json:"id"`
+	ClientID   string    `json:"client_id"` // Client that queued; it may reconnect under a new ID
+	Queue      string    `json:"queue"`
+	WorldID    string    `json:"world_id"`
+	InstanceID string    `json:"instance_id"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// Notice tells the instance a client is connected to how its queue ended.
+type Notice struct {
+	ClientID    string    `json:"client_id"`
+	Queue       string    `json:"queue"`
+	State       string    `json:"state"`
+	Ticket      string    `json:"ticket,omitempty"`
+	WorldID     string    `json:"world_id,omitempty"`
+	ReconnectTo string    `json:"reconnect_to,omitempty"`
+	ExpiresAt   time.Time `json:"expires_at,omitempty"`
+	Reason      string    `json:"reason,omitempty"`
+}
+
+// Store keeps queues, tickets and notices where every gateway can see them.
+type Store interface {
+	Enqueue(ctx context.Context, entry *Entry, ttl time.Duration) error
+	Remove(ctx context.Context, queue, clientID string) (bool, error)
+	Entries(ctx context.Context, queue string) ([]*Entry, error)
+
+	// AcquireMatcher takes or renews the right to form matches for a queue.
+	AcquireMatcher(ctx context.Context, queue, holder string, lease time.Duration) (bool, error)
+
+	// Claim removes entries from a queue only if all of them are still
+	// queued, so a client who left is never matched.
+	Claim(ctx context.Context, queue string, clientIDs []string) (bool, error)
+
+	SaveTicket(ctx context.Context, ticket *Ticket) error
+	RedeemTicket(ctx context.Context, ticketID string) (*Ticket, error)
+
+	Notify(ctx context.Context, instanceID string, notice *Notice) error
+	Notices(ctx context.Context, instanceID string) <-chan *Notice
+}
+
+// Reserver holds places for matched clients in a new world.
+type Reserver interface {
+	Reserve(template string, slots int, ttl time.Duration) (string, error)
+	Release(worldID string)
+}
+
+func newTicketID() string {
+	bytes := make([]byte, 16)
+	rand.Read(bytes)
+	return hex.EncodeToString(bytes)
+}
@@ -0,0 +1,136 @@
+package lobby
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/akarsh-2004/aether/internal/config"
+)
+
+// unit is a solo player or a complete party; units are never split.
+type unit struct {
+	entries []*Entry
+	region  string
+	skill   float64 // average of the members
+	since   time.Time
+}
+
+// formGroups splits a queue into groups that satisfy rule, plus entries that
+// have waited longer than MaxWaitSec. Parties wait until PartySize members
+// have queued.
+func formGroups(rule config.QueueRule, entries []*Entry, now time.Time) (groups [][]*Entry, expired []*Entry) {
+	maxWait := time.Duration(rule.MaxWaitSec) * time.Second
+
+	waiting := make([]*Entry, 0, len(entries))
+	for _, e := range entries {
+		if now.Sub(e.EnqueuedAt) > maxWait {
+			expired = append(expired, e)
+			continue
+		}
+		waiting = append(waiting, e)
+	}
+
+	units := buildUnits(waiting)
+	sort.Slice(units, func(i, j int) bool { return units[i].since.Before(units[j].since) })
+
+	used := make([]bool, len(units))
+	for i, anchor := range units {
+		if used[i] {
+			continue
+		}
+
+		spread := rule.MaxSkillSpread + rule.SkillSpreadPerSec*now.Sub(anchor.since).Seconds()
+		members := []int{i}
+		size := len(anchor.entries)
+		low, high := anchor.skill, anchor.skill
+
+		// Closest skill first, so the tightest group is formed
+		candidates := make([]int, 0, len(units))
+		for j := range units {
+			if j == i || used[j] || (rule.SameRegion && units[j].region != anchor.region) {
+				continue
+			}
+			candidates = append(candidates, j)
+		}
+		sort.SliceStable(candidates, func(a, b int) bool {
+			return math.Abs(units[candidates[a]].skill-anchor.skill) < math.Abs(units[candidates[b]].skill-anchor.skill)
+		})
+
+		for _, j := range candidates {
+			if size >= rule.MaxPlayers {
+				break
+			}
+			candidate := units[j]
+			if size+len(candidate.entries) > rule.MaxPlayers {
+				continue
+			}
+
+			newLow, newHigh := math.Min(low, candidate.skill), math.Max(high, candidate.skill)
+			if rule.MaxSkillSpread > 0 && newHigh-newLow > spread {
+				continue
+			}
+
+			members = append(members, j)
+			size += len(candidate.entries)
+			low, high = newLow, newHigh
+		}
+
+		if size < rule.MinPlayers || size > rule.MaxPlayers {
+			continue
+		}
+
+		group := make([]*Entry, 0, size)
+		for _, idx := range members {
+			used[idx] = true
+			group = append(group, units[idx].entries...)
+		}
+		groups = append(groups, group)
+	}
+
+	return groups, expired
+}
+
+func buildUnits(entries []*Entry) []*unit {
+	var units []*unit
+	parties := make(map[string][]*Entry)
+
+	for _, e := range entries {
+		if e.PartyID == "" {
+			units = append(units, &unit{
+				entries: []*Entry{e},
+				region:  e.Region,
+				skill:   e.Skill,
+				since:   e.EnqueuedAt,
+			})
+			continue
+		}
+		parties[e.PartyID] = append(parties[e.PartyID], e)
+	}
+
+	for _, members := range parties {
+		sort.Slice(members, func(i, j int) bool { return members[i].EnqueuedAt.Before(members[j].EnqueuedAt) })
+
+		size := 0
+		total := 0.0
+		for _, m := range members {
+			if m.PartySize > size {
+				size = m.PartySize
+			}
+			total += m.Skill
+		}
+		if len(members) < size {
+			continue // rest of the party hasn't queued yet
+		}
+
+		// The first member to queue speaks for the party's region
+		units = append(units, &unit{
+			entries: members,
+			region:  members[0].Region,
+			skill:   total / float64(len(members)),
+			since:   members[0].EnqueuedAt,
+		})
+	}
+
+	return units
+}
@@ -0,0 +1,160 @@
+package lobby
+
+import (
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/akarsh-2004/aether/internal/config"
+)
+
+var testNow = time.Unix(1700000000, 0)
+
+// queued is a solo entry that has waited waitedSec.
+func queued(clientID, region string, skill float64, waitedSec int) *Entry {
+	return &Entry{
+		ClientID:   clientID,
+		Region:     region,
+		Skill:      skill,
+		EnqueuedAt: testNow.Add(-time.Duration(waitedSec) * time.Second),
+	}
+}
+
+func inParty(e *Entry, partyID string, size int) *Entry {
+	e.PartyID, e.PartySize = partyID, size
+	return e
+}
+
+// groupIDs renders groups as sorted, comma-joined client IDs.
+func groupIDs(groups [][]*Entry) []string {
+	result := make([]string, 0, len(groups))
+	for _, group := range groups {
+		ids := make([]string, 0, len(group))
+		for _, e := range group {
+			ids = append(ids, e.ClientID)
+		}
+		sort.Strings(ids)
+		result = append(result, strings.Join(ids, ","))
+	}
+	return result
+}
+
+func TestFormGroups(t *testing.T) {
+	pair := config.QueueRule{MinPlayers: 2, MaxPlayers: 2, MaxWaitSec: 60}
+	skilled := config.QueueRule{MinPlayers: 2, MaxPlayers: 2, MaxWaitSec: 60, MaxSkillSpread: 100}
+
+	tests := []struct {
+		name        string
+		rule        config.QueueRule
+		entries     []*Entry
+		wantGroups  []string
+		wantExpired []string
+	}{
+		{
+			name:       "too few players",
+			rule:       pair,
+			entries:    []*Entry{queued("a", "eu", 1000, 5)},
+			wantGroups: []string{},
+		},
+		{
+			name: "oldest anchors the closest skill",
+			rule: skilled,
+			entries: []*Entry{
+				queued("a", "eu", 1000, 30),
+				queued("b", "eu", 1500, 20),
+				queued("c", "eu", 1010, 10),
+				queued("d", "eu", 1490, 5),
+			},
+			wantGroups: []string{"a,c", "b,d"},
+		},
+		{
+			name:       "skill spread too wide",
+			rule:       skilled,
+			entries:    []*Entry{queued("a", "eu", 1000, 10), queued("b", "eu", 1300, 5)},
+			wantGroups: []string{},
+		},
+		{
+			name: "spread widens with waiting",
+			rule: config.QueueRule{MinPlayers: 2, MaxPlayers: 2, MaxWaitSec: 60, MaxSkillSpread: 100, SkillSpreadPerSec: 25},
+			// a has waited 10s, so its spread is 100 + 250
+			entries:    []*Entry{queued("a", "eu", 1000, 10), queued("b", "eu", 1300, 5)},
+			wantGroups: []string{"a,b"},
+		},
+		{
+			name:       "zero spread ignores skill",
+			rule:       pair,
+			entries:    []*Entry{queued("a", "eu", 0, 10), queued("b", "eu", 5000, 5)},
+			wantGroups: []string{"a,b"},
+		},
+		{
+			name: "same region",
+			rule: config.QueueRule{MinPlayers: 2, MaxPlayers: 2, MaxWaitSec: 60, SameRegion: true},
+			entries: []*Entry{
+				queued("a", "eu", 1000, 30),
+				queued("b", "us", 1000, 20),
+				queued("c", "eu", 1000, 10),
+			},
+			wantGroups: []string{"a,c"},
+		},
+		{
+			name: "incomplete party waits",
+			rule: config.QueueRule{MinPlayers: 2, MaxPlayers: 3, MaxWaitSec: 60},
+			entries: []*Entry{
+				inParty(queued("p1", "eu", 1000, 20), "party", 2),
+				queued("x", "eu", 1000, 10),
+			},
+			wantGroups: []string{},
+		},
+		{
+			name: "complete party joins as one",
+			rule: config.QueueRule{MinPlayers: 2, MaxPlayers: 3, MaxWaitSec: 60},
+			entries: []*Entry{
+				inParty(queued("p1", "eu", 1000, 20), "party", 2),
+				queued("x", "eu", 1000, 15),
+				inParty(queued("p2", "eu", 1000, 10), "party", 2),
+			},
+			wantGroups: []string{"p1,p2,x"},
+		},
+		{
+			name: "parties are never split",
+			rule: pair,
+			entries: []*Entry{
+				inParty(queued("p1", "eu", 1000, 20), "party", 3),
+				inParty(queued("p2", "eu", 1000, 20), "party", 3),
+				inParty(queued("p3", "eu", 1000, 20), "party", 3),
+				queued("x", "eu", 1000, 10),
+			},
+			wantGroups: []string{},
+		},
+		{
+			name: "expired entries leave the queue",
+			rule: pair,
+			entries: []*Entry{
+				queued("old", "eu", 1000, 61),
+				queued("a", "eu", 1000, 30),
+				queued("b", "eu", 1000, 20),
+			},
+			wantGroups:  []string{"a,b"},
+			wantExpired: []string{"old"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			groups, expired := formGroups(tt.rule, tt.entries, testNow)
+
+			if got := groupIDs(groups); strings.Join(got, " ") != strings.Join(tt.wantGroups, " ") {
+				t.Errorf("expected groups %v, got %v", tt.wantGroups, got)
+			}
+
+			var expiredIDs []string
+			for _, e := range expired {
+				expiredIDs = append(expiredIDs, e.ClientID)
+			}
+			if strings.Join(expiredIDs, ",") != strings.Join(tt.wantExpired, ",") {
+				t.Errorf("expected expired %v, got %v", tt.wantExpired, expiredIDs)
+			}
+		})
+	}
+}
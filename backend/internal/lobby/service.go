@@ -0,0 +1,290 @@
+package lobby
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/akarsh-2004/aether/internal/config"
+	"go.uber.org/zap"
+)
+
+// Notifier delivers lobby notices to a client connected to this instance.
+type Notifier func(notice *Notice)
+
+// Service queues this instance's clients and, for every queue whose matcher
+// lease it holds, forms groups and reserves their worlds locally.
+type Service struct {
+	config     config.LobbyConfig
+	instanceID string
+	store      Store
+	reserver   Reserver
+	logger     *zap.Logger
+	notify     Notifier
+	queued     map[string]string // client_id -> queue, for local clients
+	mu         sync.Mutex
+	cancel     context.CancelFunc
+	wg         sync.WaitGroup
+}
+
+func NewService(cfg config.LobbyConfig, instanceID string, store Store, reserver Reserver, logger *zap.Logger) *Service {
+	return &Service{
+		config:     cfg,
+		instanceID: instanceID,
+		store:      store,
+		reserver:   reserver,
+		logger:     logger,
+		queued:     make(map[string]string),
+	}
+}
+
+// SetNotifier installs the callback that forwards notices to clients. It
+// should be called before Start.
+func (s *Service) SetNotifier(notify Notifier) {
+	s.notify = notify
+}
+
+func (s *Service) Start(ctx context.Context) {
+	ctx, s.cancel = context.WithCancel(ctx)
+
+	s.wg.Add(2)
+	go s.matchLoop(ctx)
+	go s.noticeLoop(ctx)
+}
+
+// Stop stops matching and removes this instance's clients from their queues.
+func (s *Service) Stop(ctx context.Context) {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+
+	s.mu.Lock()
+	queued := s.queued
+	s.queued = make(map[string]string)
+	s.mu.Unlock()
+
+	for clientID, queue := range queued {
+		if _, err := s.store.Remove(ctx, queue, clientID); err != nil {
+			s.logger.Warn("Failed to remove client from queue", zap.String("client_id", clientID), zap.Error(err))
+		}
+	}
+}
+
+// Enqueue adds a client of this instance to a queue.
+func (s *Service) Enqueue(ctx context.Context, entry *Entry) error {
+	rule, ok := s.config.Queues[entry.Queue]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownQueue, entry.Queue)
+	}
+
+	s.mu.Lock()
+	if _, queued := s.queued[entry.ClientID]; queued {
+		s.mu.Unlock()
+		return ErrAlreadyQueued
+	}
+	s.queued[entry.ClientID] = entry.Queue
+	s.mu.Unlock()
+
+	entry.InstanceID = s.instanceID
+	entry.EnqueuedAt = time.Now()
+
+	// Outlive max_wait_sec so the matcher can report the timeout
+	ttl := time.Duration(rule.MaxWaitSec)*time.Second + time.Minute
+	if err := s.store.Enqueue(ctx, entry, ttl); err != nil {
+		s.forget(entry.ClientID)
+		return err
+	}
+
+	s.logger.Debug("Client queued",
+		zap.String("client_id", entry.ClientID),
+		zap.String("queue", entry.Queue),
+		zap.String("region", entry.Region),
+		zap.Float64("skill", entry.Skill),
+	)
+	return nil
+}
+
+// Leave removes a client of this instance from its queue.
+func (s *Service) Leave(ctx context.Context, clientID string) error {
+	queue, ok := s.forget(clientID)
+	if !ok {
+		return ErrNotQueued
+	}
+
+	if _, err := s.store.Remove(ctx, queue, clientID); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Redeem consumes a join ticket issued for a world on this instance.
+func (s *Service) Redeem(ctx context.Context, ticketID string) (*Ticket, error) {
+	ticket, err := s.store.RedeemTicket(ctx, ticketID)
+	if err != nil {
+		return nil, err
+	}
+	if ticket == nil || time.Now().After(ticket.ExpiresAt) {
+		return nil, ErrInvalidTicket
+	}
+	if ticket.InstanceID != s.instanceID {
+		return nil, ErrWrongInstance
+	}
+	return ticket, nil
+}
+
+func (s *Service) forget(clientID string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	queue, ok := s.queued[clientID]
+	delete(s.queued, clientID)
+	return queue, ok
+}
+
+func (s *Service) matchLoop(ctx context.Context) {
+	defer s.wg.Done()
+
+	interval := time.Duration(s.config.PollIntervalMs) * time.Millisecond
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for queue, rule := range s.config.Queues {
+				// The lease outlives a few missed passes before another instance takes over
+				held, err := s.store.AcquireMatcher(ctx, queue, s.instanceID, 3*interval)
+				if err != nil {
+					s.logger.Warn("Failed to acquire matcher lease", zap.String("queue", queue), zap.Error(err))
+					continue
+				}
+				if held {
+					s.matchQueue(ctx, queue, rule)
+				}
+			}
+		}
+	}
+}
+
+func (s *Service) matchQueue(ctx context.Context, queue string, rule config.QueueRule) {
+	entries, err := s.store.Entries(ctx, queue)
+	if err != nil {
+		s.logger.Warn("Failed to load queue", zap.String("queue", queue), zap.Error(err))
+		return
+	}
+
+	groups, expired := formGroups(rule, entries, time.Now())
+
+	for _, e := range expired {
+		if removed, err := s.store.Remove(ctx, queue, e.ClientID); err != nil || !removed {
+			continue
+		}
+		s.send(ctx, e.InstanceID, &Notice{
+			ClientID: e.ClientID,
+			Queue:    queue,
+			State:    StateTimedOut,
+			Reason:   "no match found",
+		})
+	}
+
+	for _, group := range groups {
+		s.startMatch(ctx, queue, rule, group)
+	}
+}
+
+func (s *Service) startMatch(ctx context.Context, queue string, rule config.QueueRule, group []*Entry) {
+	ttl := time.Duration(s.config.TicketTTLSec) * time.Second
+
+	worldID, err := s.reserver.Reserve(rule.Template, len(group), ttl)
+	if err != nil {
+		s.logger.Warn("Failed to reserve world for match", zap.String("queue", queue), zap.Error(err))
+		return
+	}
+
+	clientIDs := make([]string, len(group))
+	for i, e := range group {
+		clientIDs[i] = e.ClientID
+	}
+
+	claimed, err := s.store.Claim(ctx, queue, clientIDs)
+	if err != nil || !claimed {
+		// Someone left meanwhile; they'll be regrouped next pass
+		s.reserver.Release(worldID)
+		if err != nil {
+			s.logger.Warn("Failed to claim matched clients", zap.String("queue", queue), zap.Error(err))
+		}
+		return
+	}
+
+	expiresAt := time.Now().Add(ttl)
+	for _, e := range group {
+		ticket := &Ticket{
+			ID:         newTicketID(),
+			ClientID:   e.ClientID,
+			Queue:      queue,
+			WorldID:    worldID,
+			InstanceID: s.instanceID,
+			ExpiresAt:  expiresAt,
+		}
+		if err := s.store.SaveTicket(ctx, ticket); err != nil {
+			s.logger.Error("Failed to save join ticket", zap.String("client_id", e.ClientID), zap.Error(err))
+			continue
+		}
+
+		notice := &Notice{
+			ClientID:  e.ClientID,
+			Queue:     queue,
+			State:     StateMatched,
+			Ticket:    ticket.ID,
+			WorldID:   worldID,
+			ExpiresAt: expiresAt,
+		}
+		if e.InstanceID != s.instanceID {
+			notice.ReconnectTo = s.config.AdvertiseAddr
+		}
+		s.send(ctx, e.InstanceID, notice)
+	}
+
+	s.logger.Info("Match formed",
+		zap.String("queue", queue),
+		zap.String("world_id", worldID),
+		zap.Int("players", len(group)),
+	)
+}
+
+func (s *Service) send(ctx context.Context, instanceID string, notice *Notice) {
+	if err := s.store.Notify(ctx, instanceID, notice); err != nil {
+		s.logger.Warn("Failed to send lobby notice",
+			zap.String("client_id", notice.ClientID),
+			zap.String("instance_id", instanceID),
+			zap.Error(err),
+		)
+	}
+}
+
+func (s *Service) noticeLoop(ctx context.Context) {
+	defer s.wg.Done()
+
+	for notice := range s.store.Notices(ctx, s.instanceID) {
+		if _, ok := s.forget(notice.ClientID); !ok {
+			continue // client left or disconnected meanwhile
+		}
+		if s.notify != nil {
+			s.notify(notice)
+		}
+	}
+}
+
+func (s *Service) GetStats() map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return map[string]interface{}{
+		"queued_clients": len(s.queued),
+		"queues":         len(s.config.Queues),
+	}
+}
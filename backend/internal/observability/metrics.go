@@ -23,17 +23,24 @@ type Metrics struct {
 	MessagesReceived  prometheus.Counter
 	MessagesSent      prometheus.Counter
 	ConnectionErrors   prometheus.Counter
+	ClientRTT          prometheus.Histogram
+	ClientJitter       prometheus.Histogram
+	ClientClockOffset  prometheus.Histogram
 
 	// Persistence metrics
-	RedisOperations    prometheus.CounterVec
-	PostgresOperations prometheus.CounterVec
-	OutboxEvents       prometheus.Counter
+	RedisOperations    *prometheus.CounterVec
+	PostgresOperations *prometheus.CounterVec
+	OutboxEvents       *prometheus.CounterVec
+	OutboxLatency      prometheus.Histogram
 
 	// System metrics
 	MemoryUsage prometheus.Gauge
 	GCCount     prometheus.Counter
 }
 
+// latencyBuckets cover LAN to poor mobile connections.
+var latencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.075, 0.1, 0.15, 0.2, 0.3, 0.5, 1, 2}
+
 func NewMetrics(logger *zap.Logger) *Metrics {
 	return &Metrics{
 		logger: logger,
@@ -79,9 +86,29 @@ func NewMetrics(logger *zap.Logger) *Metrics {
 			Name: "aether_postgres_operations_total",
 			Help: "Total number of PostgreSQL operations",
 		}, []string{"operation", "status"}),
-		OutboxEvents: prometheus.NewCounter(prometheus.CounterOpts{
+		ClientRTT: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "aether_client_rtt_seconds",
+			Help:    "Round trip time measured from client heartbeats",
+			Buckets: latencyBuckets,
+		}),
+		ClientJitter: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "aether_client_jitter_seconds",
+			Help:    "Smoothed round trip time variation per client",
+			Buckets: latencyBuckets,
+		}),
+		ClientClockOffset: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "aether_client_clock_offset_seconds",
+			Help:    "Absolute difference between client and server clocks",
+			Buckets: []float64{0.001, 0.01, 0.05, 0.1, 0.5, 1, 5, 30, 300},
+		}),
+		OutboxEvents: prometheus.NewCounterVec(prometheus.CounterOpts{
 			Name: "aether_outbox_events_total",
-			Help: "Total number of outbox events processed",
+			Help: "Total number of outbox events handled, by outcome",
+		}, []string{"event_type", "status"}),
+		OutboxLatency: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "aether_outbox_delivery_latency_seconds",
+			Help:    "Time from outbox insert to successful processing",
+			Buckets: []float64{0.1, 0.5, 1, 2, 5, 10, 30, 60, 300},
 		}),
 		MemoryUsage: prometheus.NewGauge(prometheus.GaugeOpts{
 			Name: "aether_memory_usage_bytes",
@@ -105,9 +132,13 @@ func (m *Metrics) Register() error {
 		m.MessagesReceived,
 		m.MessagesSent,
 		m.ConnectionErrors,
+		m.ClientRTT,
+		m.ClientJitter,
+		m.ClientClockOffset,
 		m.RedisOperations,
 		m.PostgresOperations,
 		m.OutboxEvents,
+		m.OutboxLatency,
 		m.MemoryUsage,
 		m.GCCount,
 	}
@@ -170,6 +201,17 @@ func (m *Metrics) IncrementConnectionErrors() {
 	m.ConnectionErrors.Inc()
 }
 
+// RecordClientLatency observes one heartbeat's round trip along with the
+// client's current jitter and clock offset estimates.
+func (m *Metrics) RecordClientLatency(rtt, jitter, offset time.Duration) {
+	if offset < 0 {
+		offset = -offset
+	}
+	m.ClientRTT.Observe(rtt.Seconds())
+	m.ClientJitter.Observe(jitter.Seconds())
+	m.ClientClockOffset.Observe(offset.Seconds())
+}
+
 func (m *Metrics) RecordRedisOperation(operation, status string) {
 	m.RedisOperations.WithLabelValues(operation, status).Inc()
 }
@@ -178,8 +220,14 @@ func (m *Metrics) RecordPostgresOperation(operation, status string) {
 	m.PostgresOperations.WithLabelValues(operation, status).Inc()
 }
 
-func (m *Metrics) IncrementOutboxEvents() {
-	m.OutboxEvents.Inc()
+// RecordOutboxEvent counts an outbox event outcome: processed, retried or
+// dead_lettered.
+func (m *Metrics) RecordOutboxEvent(eventType, status string) {
+	m.OutboxEvents.WithLabelValues(eventType, status).Inc()
+}
+
+func (m *Metrics) RecordOutboxLatency(latency time.Duration) {
+	m.OutboxLatency.Observe(latency.Seconds())
 }
 
 func (m *Metrics) SetMemoryUsage(bytes int64) {
@@ -2,19 +2,41 @@ package outbox
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"math/rand"
+	"os"
 	"sync"
 	"time"
 
+	"github.com/akarsh-2004/aether/internal/config"
+	"github.com/akarsh-2004/aether/internal/events"
+	"github.com/akarsh-2004/aether/internal/observability"
 	"github.com/akarsh-2004/aether/internal/persistence/postgres"
+	"github.com/akarsh-2004/aether/internal/utils"
+	"github.com/akarsh-2004/aether/proto"
 	"go.uber.org/zap"
+	protobuf "google.golang.org/protobuf/proto"
 )
 
+// Store is the outbox table events are leased from, implemented by
+// postgres.PostgresClient. ClaimOutboxEvents must only return the oldest
+// pending event of each aggregate key.
+type Store interface {
+	ClaimOutboxEvents(ctx context.Context, workerID string, lease time.Duration, limit int) ([]*postgres.OutboxEvent, error)
+	CompleteOutboxEvent(ctx context.Context, eventID int64, workerID string) error
+	RetryOutboxEvent(ctx context.Context, eventID int64, workerID string, nextAttempt time.Time, lastError string) error
+	DeadLetterOutboxEvent(ctx context.Context, eventID int64, workerID string, lastError string) error
+	SaveOutboxEvent(ctx context.Context, aggregateKey string, event protobuf.Message) error
+}
+
 type OutboxProcessor struct {
-	pgClient   *postgres.PostgresClient
+	store      Store
+	config     config.OutboxConfig
+	metrics    *observability.Metrics
 	logger     *zap.Logger
+	workerID   string
 	handlers   map[string]EventHandler
+	sinks      []Sink
 	buffer     []OutboxEvent
 	bufferSize int
 	mu         sync.RWMutex
@@ -24,20 +46,30 @@ type OutboxProcessor struct {
 }
 
 type OutboxEvent struct {
-	ID        int64                  `json:"id"`
-	EventType string                 `json:"event_type"`
-	Payload   map[string]interface{} `json:"payload"`
-	CreatedAt time.Time              `json:"created_at"`
+	ID            int64            `json:"id"`
+	EventType     string           `json:"event_type"`
+	SchemaVersion int              `json:"schema_version"`
+	Event         protobuf.Message `json:"event"`
+	CreatedAt     time.Time        `json:"created_at"`
 }
 
-type EventHandler func(ctx context.Context, payload map[string]interface{}) error
+// EventHandler receives a decoded event message. Use Handle to register a
+// handler for a concrete message type.
+type EventHandler func(ctx context.Context, event protobuf.Message) error
+
+// NewOutboxProcessor creates a processor that leases events from store,
+// normally Postgres. metrics may be nil.
+func NewOutboxProcessor(store Store, cfg config.OutboxConfig, metrics *observability.Metrics, logger *zap.Logger) *OutboxProcessor {
+	hostname, _ := os.Hostname()
 
-func NewOutboxProcessor(pgClient *postgres.PostgresClient, logger *zap.Logger) *OutboxProcessor {
 	return &OutboxProcessor{
-		pgClient:   pgClient,
+		store:      store,
+		config:     cfg,
+		metrics:    metrics,
 		logger:     logger,
+		workerID:   fmt.Sprintf("%s-%d-%s", hostname, os.Getpid(), utils.GenerateID()),
 		handlers:   make(map[string]EventHandler),
-		bufferSize: 100,
+		bufferSize: cfg.BatchSize,
 		stopChan:   make(chan struct{}),
 	}
 }
@@ -50,6 +82,24 @@ func (op *OutboxProcessor) RegisterHandler(eventType string, handler EventHandle
 	op.logger.Info("Registered outbox event handler", zap.String("event_type", eventType))
 }
 
+// Handle registers fn for the event type carried by message type T, e.g.
+// Handle(op, func(ctx context.Context, e *proto.SessionEndedEvent) error {...}).
+func Handle[T protobuf.Message](op *OutboxProcessor, fn func(ctx context.Context, event T) error) {
+	var zero T
+	eventType, err := events.TypeOf(zero)
+	if err != nil {
+		panic(fmt.Sprintf("outbox: %v", err))
+	}
+
+	op.RegisterHandler(eventType, func(ctx context.Context, event protobuf.Message) error {
+		typed, ok := event.(T)
+		if !ok {
+			return fmt.Errorf("unexpected message %T for %s handler", event, eventType)
+		}
+		return fn(ctx, typed)
+	})
+}
+
 func (op *OutboxProcessor) Start(ctx context.Context) error {
 	op.mu.Lock()
 	if op.running {
@@ -77,6 +127,7 @@ func (op *OutboxProcessor) Stop() {
 
 	close(op.stopChan)
 	op.wg.Wait()
+	op.closeSinks()
 
 	op.logger.Info("Outbox processor stopped")
 }
@@ -84,7 +135,7 @@ func (op *OutboxProcessor) Stop() {
 func (op *OutboxProcessor) processLoop(ctx context.Context) {
 	defer op.wg.Done()
 
-	ticker := time.NewTicker(1 * time.Second) // Process every second
+	ticker := time.NewTicker(time.Duration(op.config.PollIntervalMs) * time.Millisecond)
 	defer ticker.Stop()
 
 	for {
@@ -100,10 +151,12 @@ func (op *OutboxProcessor) processLoop(ctx context.Context) {
 }
 
 func (op *OutboxProcessor) processBatch(ctx context.Context) {
-	// Get unprocessed events
-	events, err := op.pgClient.GetUnprocessedOutboxEvents(ctx, op.bufferSize)
+	lease := time.Duration(op.config.LeaseMs) * time.Millisecond
+
+	// Claim a batch; other instances skip rows we hold
+	events, err := op.store.ClaimOutboxEvents(ctx, op.workerID, lease, op.bufferSize)
 	if err != nil {
-		op.logger.Error("Failed to get unprocessed outbox events", zap.Error(err))
+		op.logger.Error("Failed to claim outbox events", zap.Error(err))
 		return
 	}
 
@@ -115,37 +168,101 @@ func (op *OutboxProcessor) processBatch(ctx context.Context) {
 
 	for _, event := range events {
 		if err := op.processEvent(ctx, event); err != nil {
-			op.logger.Error("Failed to process outbox event",
-				zap.Int64("event_id", event.ID),
-				zap.String("event_type", event.EventType),
-				zap.Error(err),
-			)
+			op.handleFailure(ctx, event, err)
 			continue
 		}
 
-		// Mark as processed
-		if err := op.pgClient.MarkOutboxEventProcessed(ctx, event.ID); err != nil {
+		if err := op.store.CompleteOutboxEvent(ctx, event.ID, op.workerID); err != nil {
 			op.logger.Error("Failed to mark outbox event as processed",
 				zap.Int64("event_id", event.ID),
 				zap.Error(err),
 			)
+			continue
+		}
+
+		if op.metrics != nil {
+			op.metrics.RecordOutboxEvent(event.EventType, "processed")
+			op.metrics.RecordOutboxLatency(time.Since(event.CreatedAt))
 		}
 	}
 }
 
+func (op *OutboxProcessor) handleFailure(ctx context.Context, event *postgres.OutboxEvent, cause error) {
+	if event.Attempts >= op.config.MaxAttempts {
+		op.logger.Error("Outbox event exhausted retries, dead-lettering",
+			zap.Int64("event_id", event.ID),
+			zap.String("event_type", event.EventType),
+			zap.Int("attempts", event.Attempts),
+			zap.Error(cause),
+		)
+
+		if err := op.store.DeadLetterOutboxEvent(ctx, event.ID, op.workerID, cause.Error()); err != nil {
+			op.logger.Error("Failed to dead-letter outbox event", zap.Int64("event_id", event.ID), zap.Error(err))
+			return
+		}
+
+		if op.metrics != nil {
+			op.metrics.RecordOutboxEvent(event.EventType, "dead_lettered")
+		}
+		return
+	}
+
+	delay := op.backoff(event.Attempts)
+	op.logger.Warn("Failed to process outbox event, will retry",
+		zap.Int64("event_id", event.ID),
+		zap.String("event_type", event.EventType),
+		zap.Int("attempts", event.Attempts),
+		zap.Duration("retry_in", delay),
+		zap.Error(cause),
+	)
+
+	if err := op.store.RetryOutboxEvent(ctx, event.ID, op.workerID, time.Now().Add(delay), cause.Error()); err != nil {
+		op.logger.Error("Failed to reschedule outbox event", zap.Int64("event_id", event.ID), zap.Error(err))
+		return
+	}
+
+	if op.metrics != nil {
+		op.metrics.RecordOutboxEvent(event.EventType, "retried")
+	}
+}
+
+// backoff returns the delay before the next attempt: base * 2^(attempts-1),
+// capped at MaxBackoffMs, with up to 20% jitter so failing events spread out.
+func (op *OutboxProcessor) backoff(attempts int) time.Duration {
+	base := time.Duration(op.config.BaseBackoffMs) * time.Millisecond
+	maxDelay := time.Duration(op.config.MaxBackoffMs) * time.Millisecond
+
+	delay := base
+	for i := 1; i < attempts && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+
+	jitter := time.Duration(rand.Int63n(int64(delay)/5 + 1))
+	return delay - jitter
+}
+
 func (op *OutboxProcessor) processEvent(ctx context.Context, pgEvent *postgres.OutboxEvent) error {
-	// Parse payload
-	var payload map[string]interface{}
-	if err := json.Unmarshal([]byte(pgEvent.Payload), &payload); err != nil {
-		return fmt.Errorf("failed to unmarshal payload: %w", err)
+	// Decode into the typed event, upgrading rows written by older versions.
+	// Unknown types are still forwarded to sinks untouched.
+	var event protobuf.Message
+	if _, known := events.CurrentVersion(pgEvent.EventType); known {
+		decoded, err := events.Decode(pgEvent.EventType, pgEvent.SchemaVersion, []byte(pgEvent.Payload))
+		if err != nil {
+			return err
+		}
+		event = decoded
 	}
 
 	// Get handler
 	op.mu.RLock()
 	handler, exists := op.handlers[pgEvent.EventType]
+	hasSinks := len(op.sinks) > 0
 	op.mu.RUnlock()
 
-	if !exists {
+	if !exists && !hasSinks {
 		op.logger.Warn("No handler registered for event type",
 			zap.String("event_type", pgEvent.EventType),
 		)
@@ -153,8 +270,18 @@ func (op *OutboxProcessor) processEvent(ctx context.Context, pgEvent *postgres.O
 	}
 
 	// Execute handler
-	if err := handler(ctx, payload); err != nil {
-		return fmt.Errorf("handler failed: %w", err)
+	if exists {
+		if event == nil {
+			return fmt.Errorf("no schema registered for event type %q", pgEvent.EventType)
+		}
+		if err := handler(ctx, event); err != nil {
+			return fmt.Errorf("handler failed: %w", err)
+		}
+	}
+
+	// Forward to external brokers
+	if err := op.deliverToSinks(ctx, pgEvent, event); err != nil {
+		return err
 	}
 
 	op.logger.Debug("Processed outbox event",
@@ -165,110 +292,95 @@ func (op *OutboxProcessor) processEvent(ctx context.Context, pgEvent *postgres.O
 	return nil
 }
 
-func (op *OutboxProcessor) PublishEvent(ctx context.Context, eventType string, payload map[string]interface{}) error {
-	return op.pgClient.SaveOutboxEvent(ctx, eventType, payload)
+// PublishEvent queues a typed event. Events with the same aggregateKey (e.g.
+// an entity or session ID) are processed in order; pass "" if order doesn't
+// matter.
+func (op *OutboxProcessor) PublishEvent(ctx context.Context, aggregateKey string, event protobuf.Message) error {
+	return op.store.SaveOutboxEvent(ctx, aggregateKey, event)
 }
 
 // Built-in event handlers
 
 func (op *OutboxProcessor) RegisterBuiltInHandlers() {
 	// Entity spawn event handler
-	op.RegisterHandler("entity_spawned", func(ctx context.Context, payload map[string]interface{}) error {
-		entityID, ok := payload["entity_id"].(float64)
-		if !ok {
-			return fmt.Errorf("invalid entity_id in payload")
-		}
-
-		clientID, ok := payload["client_id"].(string)
-		if !ok {
-			return fmt.Errorf("invalid client_id in payload")
-		}
-
+	Handle(op, func(ctx context.Context, e *proto.EntitySpawnedEvent) error {
 		op.logger.Info("Entity spawned event processed",
-			zap.Uint32("entity_id", uint32(entityID)),
-			zap.String("client_id", clientID),
+			zap.Uint32("entity_id", e.EntityId),
+			zap.String("client_id", e.ClientId),
 		)
-
 		return nil
 	})
 
 	// Entity despawn event handler
-	op.RegisterHandler("entity_despawned", func(ctx context.Context, payload map[string]interface{}) error {
-		entityID, ok := payload["entity_id"].(float64)
-		if !ok {
-			return fmt.Errorf("invalid entity_id in payload")
-		}
-
-		reason, ok := payload["reason"].(string)
-		if !ok {
-			reason = "unknown"
-		}
-
+	Handle(op, func(ctx context.Context, e *proto.EntityDespawnedEvent) error {
 		op.logger.Info("Entity despawned event processed",
-			zap.Uint32("entity_id", uint32(entityID)),
-			zap.String("reason", reason),
+			zap.Uint32("entity_id", e.EntityId),
+			zap.String("reason", e.Reason),
 		)
-
 		return nil
 	})
 
 	// Movement correction event handler
-	op.RegisterHandler("movement_correction", func(ctx context.Context, payload map[string]interface{}) error {
-		entityID, ok := payload["entity_id"].(float64)
-		if !ok {
-			return fmt.Errorf("invalid entity_id in payload")
-		}
-
-		clientID, ok := payload["client_id"].(string)
-		if !ok {
-			return fmt.Errorf("invalid client_id in payload")
-		}
-
+	Handle(op, func(ctx context.Context, e *proto.MovementCorrectionEvent) error {
 		op.logger.Info("Movement correction event processed",
-			zap.Uint32("entity_id", uint32(entityID)),
-			zap.String("client_id", clientID),
+			zap.Uint32("entity_id", e.EntityId),
+			zap.String("client_id", e.ClientId),
 		)
+		return nil
+	})
 
+	// Entity control change event handler
+	Handle(op, func(ctx context.Context, e *proto.EntityControlChangedEvent) error {
+		op.logger.Info("Entity control changed event processed",
+			zap.Uint32("entity_id", e.EntityId),
+			zap.String("client_id", e.ClientId),
+			zap.String("reason", e.Reason),
+		)
 		return nil
 	})
 
-	// Session started event handler
-	op.RegisterHandler("session_started", func(ctx context.Context, payload map[string]interface{}) error {
-		sessionID, ok := payload["session_id"].(string)
-		if !ok {
-			return fmt.Errorf("invalid session_id in payload")
-		}
+	// Projectile hit event handler
+	Handle(op, func(ctx context.Context, e *proto.ProjectileHitEvent) error {
+		op.logger.Info("Projectile hit event processed",
+			zap.Uint32("projectile_id", e.ProjectileId),
+			zap.Uint32("target_entity_id", e.TargetEntityId),
+			zap.String("shooter_client_id", e.ShooterClientId),
+		)
+		return nil
+	})
 
-		clientID, ok := payload["client_id"].(string)
-		if !ok {
-			return fmt.Errorf("invalid client_id in payload")
-		}
+	// Zone events handlers
+	Handle(op, func(ctx context.Context, e *proto.ZoneEnteredEvent) error {
+		op.logger.Info("Zone entered event processed",
+			zap.Uint32("entity_id", e.EntityId),
+			zap.String("zone", e.Zone),
+		)
+		return nil
+	})
 
-		op.logger.Info("Session started event processed",
-			zap.String("session_id", sessionID),
-			zap.String("client_id", clientID),
+	Handle(op, func(ctx context.Context, e *proto.ZoneExitedEvent) error {
+		op.logger.Info("Zone exited event processed",
+			zap.Uint32("entity_id", e.EntityId),
+			zap.String("zone", e.Zone),
 		)
+		return nil
+	})
 
+	// Session started event handler
+	Handle(op, func(ctx context.Context, e *proto.SessionStartedEvent) error {
+		op.logger.Info("Session started event processed",
+			zap.String("session_id", e.SessionId),
+			zap.String("client_id", e.ClientId),
+		)
 		return nil
 	})
 
 	// Session ended event handler
-	op.RegisterHandler("session_ended", func(ctx context.Context, payload map[string]interface{}) error {
-		sessionID, ok := payload["session_id"].(string)
-		if !ok {
-			return fmt.Errorf("invalid session_id in payload")
-		}
-
-		duration, ok := payload["duration_seconds"].(float64)
-		if !ok {
-			duration = 0
-		}
-
+	Handle(op, func(ctx context.Context, e *proto.SessionEndedEvent) error {
 		op.logger.Info("Session ended event processed",
-			zap.String("session_id", sessionID),
-			zap.Float64("duration_seconds", duration),
+			zap.String("session_id", e.SessionId),
+			zap.Int32("duration_seconds", e.DurationSeconds),
 		)
-
 		return nil
 	})
 }
@@ -281,6 +393,8 @@ func (op *OutboxProcessor) GetStats() map[string]interface{} {
 		"running":       op.running,
 		"handlers":      len(op.handlers),
 		"buffer_size":   op.bufferSize,
+		"worker_id":     op.workerID,
+		"sinks":         len(op.sinks),
 		"registered_events": func() []string {
 			events := make([]string, 0, len(op.handlers))
 			for eventType := range op.handlers {
@@ -0,0 +1,241 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/akarsh-2004/aether/internal/config"
+	"github.com/akarsh-2004/aether/internal/persistence/postgres"
+	"go.uber.org/zap"
+	protobuf "google.golang.org/protobuf/proto"
+)
+
+// memStore follows ClaimOutboxEvents' rules in memory: only the oldest
+// pending event of each aggregate key is claimable, and claimed events stay
+// leased until they are completed, retried or dead-lettered. Retries are due
+// at once; their delays are recorded instead.
+type memStore struct {
+	mu           sync.Mutex
+	events       []*postgres.OutboxEvent // by ID
+	leased       map[int64]bool
+	deadLettered map[int64]bool
+	retryDelays  map[int64][]time.Duration
+}
+
+func newMemStore(events ...*postgres.OutboxEvent) *memStore {
+	return &memStore{
+		events:       events,
+		leased:       make(map[int64]bool),
+		deadLettered: make(map[int64]bool),
+		retryDelays:  make(map[int64][]time.Duration),
+	}
+}
+
+func (s *memStore) pending(e *postgres.OutboxEvent) bool {
+	return !e.Processed && !s.deadLettered[e.ID]
+}
+
+func (s *memStore) ClaimOutboxEvents(ctx context.Context, workerID string, lease time.Duration, limit int) ([]*postgres.OutboxEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var claimed []*postgres.OutboxEvent
+	blocked := make(map[string]bool)
+	for _, e := range s.events {
+		if !s.pending(e) {
+			continue
+		}
+		head := e.AggregateKey == "" || !blocked[e.AggregateKey]
+		if e.AggregateKey != "" {
+			blocked[e.AggregateKey] = true
+		}
+		if !head || s.leased[e.ID] || len(claimed) == limit {
+			continue
+		}
+
+		e.Attempts++
+		s.leased[e.ID] = true
+		copied := *e
+		claimed = append(claimed, &copied)
+	}
+	return claimed, nil
+}
+
+func (s *memStore) find(eventID int64) *postgres.OutboxEvent {
+	for _, e := range s.events {
+		if e.ID == eventID {
+			return e
+		}
+	}
+	return nil
+}
+
+func (s *memStore) CompleteOutboxEvent(ctx context.Context, eventID int64, workerID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.find(eventID).Processed = true
+	delete(s.leased, eventID)
+	return nil
+}
+
+func (s *memStore) RetryOutboxEvent(ctx context.Context, eventID int64, workerID string, nextAttempt time.Time, lastError string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.retryDelays[eventID] = append(s.retryDelays[eventID], time.Until(nextAttempt))
+	delete(s.leased, eventID)
+	return nil
+}
+
+func (s *memStore) DeadLetterOutboxEvent(ctx context.Context, eventID int64, workerID string, lastError string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.deadLettered[eventID] = true
+	delete(s.leased, eventID)
+	return nil
+}
+
+func (s *memStore) SaveOutboxEvent(ctx context.Context, aggregateKey string, event protobuf.Message) error {
+	return errors.New("not supported")
+}
+
+// recordingSink records delivered event IDs and fails the first failures[id]
+// deliveries of an event, or all of them when the count is negative.
+type recordingSink struct {
+	delivered []int64
+	failures  map[int64]int
+}
+
+func (s *recordingSink) Name() string { return "recording" }
+func (s *recordingSink) Close() error { return nil }
+
+func (s *recordingSink) Deliver(ctx context.Context, envelope *Envelope) error {
+	if n := s.failures[envelope.ID]; n != 0 {
+		s.failures[envelope.ID] = n - 1
+		return errors.New("sink unavailable")
+	}
+	s.delivered = append(s.delivered, envelope.ID)
+	return nil
+}
+
+func testEvent(id int64, aggregateKey string) *postgres.OutboxEvent {
+	return &postgres.OutboxEvent{ID: id, EventType: "test_event", AggregateKey: aggregateKey, Payload: "{}"}
+}
+
+func testOutboxConfig() config.OutboxConfig {
+	return config.OutboxConfig{
+		BatchSize:     10,
+		LeaseMs:       1000,
+		MaxAttempts:   3,
+		BaseBackoffMs: 100,
+		MaxBackoffMs:  1000,
+	}
+}
+
+func TestBackoff_Bounds(t *testing.T) {
+	op := NewOutboxProcessor(nil, testOutboxConfig(), nil, zap.NewNop())
+
+	tests := []struct {
+		attempts int
+		max      time.Duration
+	}{
+		{1, 100 * time.Millisecond},
+		{2, 200 * time.Millisecond},
+		{4, 800 * time.Millisecond},
+		{5, 1000 * time.Millisecond}, // 1600ms capped
+		{30, 1000 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		// Jitter takes off at most 20%
+		min := tt.max - tt.max/5
+		seen := make(map[time.Duration]bool)
+		for i := 0; i < 200; i++ {
+			delay := op.backoff(tt.attempts)
+			if delay < min || delay > tt.max {
+				t.Fatalf("attempt %d: expected a delay in [%v, %v], got %v", tt.attempts, min, tt.max, delay)
+			}
+			seen[delay] = true
+		}
+		if len(seen) < 2 {
+			t.Errorf("attempt %d: expected jitter to vary the delay", tt.attempts)
+		}
+	}
+}
+
+func TestProcessBatch_HeadOfLine(t *testing.T) {
+	store := newMemStore(
+		testEvent(1, "entity:7"),
+		testEvent(2, "entity:7"),
+		testEvent(3, "entity:9"),
+		testEvent(4, ""),
+	)
+	sink := &recordingSink{failures: map[int64]int{1: 1}}
+
+	op := NewOutboxProcessor(store, testOutboxConfig(), nil, zap.NewNop())
+	op.AddSink(sink)
+
+	// Event 1 fails once; event 2 waits behind it while other keys go on
+	op.processBatch(context.Background())
+	if want := []int64{3, 4}; !equalIDs(sink.delivered, want) {
+		t.Fatalf("expected first batch %v, got %v", want, sink.delivered)
+	}
+
+	op.processBatch(context.Background())
+	op.processBatch(context.Background())
+	if want := []int64{3, 4, 1, 2}; !equalIDs(sink.delivered, want) {
+		t.Errorf("expected %v, got %v", want, sink.delivered)
+	}
+	if delays := store.retryDelays[1]; len(delays) != 1 {
+		t.Errorf("expected event 1 to be retried once, got %v", delays)
+	}
+}
+
+func TestProcessBatch_DeadLettersAfterMaxAttempts(t *testing.T) {
+	store := newMemStore(testEvent(1, "entity:7"), testEvent(2, "entity:7"))
+	sink := &recordingSink{failures: map[int64]int{1: -1}}
+
+	op := NewOutboxProcessor(store, testOutboxConfig(), nil, zap.NewNop())
+	op.AddSink(sink)
+
+	for i := 0; i < 3; i++ {
+		op.processBatch(context.Background())
+	}
+	if !store.deadLettered[1] {
+		t.Fatal("expected event 1 to be dead-lettered after 3 attempts")
+	}
+
+	// Each retry waits about twice as long as the one before
+	delays := store.retryDelays[1]
+	if len(delays) != 2 {
+		t.Fatalf("expected 2 retries before dead-lettering, got %v", delays)
+	}
+	for i, max := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond} {
+		if delays[i] > max || delays[i] < max-max/5-10*time.Millisecond {
+			t.Errorf("retry %d: expected a delay just under %v, got %v", i+1, max, delays[i])
+		}
+	}
+
+	// The dead letter no longer blocks its key
+	op.processBatch(context.Background())
+	if want := []int64{2}; !equalIDs(sink.delivered, want) {
+		t.Errorf("expected %v delivered, got %v", want, sink.delivered)
+	}
+}
+
+func equalIDs(a, b []int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
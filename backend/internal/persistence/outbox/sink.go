@@ -0,0 +1,122 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/akarsh-2004/aether/internal/config"
+	"github.com/akarsh-2004/aether/internal/events"
+	"github.com/akarsh-2004/aether/internal/persistence/postgres"
+	"go.uber.org/zap"
+	protobuf "google.golang.org/protobuf/proto"
+)
+
+// Sink delivers outbox events to an external system. Delivery is
+// at-least-once: an event is redelivered to every sink if any sink or handler
+// fails, so consumers should deduplicate on Envelope.ID.
+type Sink interface {
+	Name() string
+	Deliver(ctx context.Context, envelope *Envelope) error
+	Close() error
+}
+
+// Envelope is the wire format every sink emits, one JSON object per event.
+type Envelope struct {
+	ID            int64           `json:"id"`
+	Type          string          `json:"type"`
+	AggregateKey  string          `json:"aggregate_key,omitempty"`
+	SchemaVersion int             `json:"schema_version"`
+	CreatedAt     time.Time       `json:"created_at"`
+	Payload       json.RawMessage `json:"payload"`
+}
+
+// newEnvelope builds the sink envelope for a stored event. Decoded events are
+// re-encoded at the current schema version so sinks never see legacy payloads.
+func newEnvelope(stored *postgres.OutboxEvent, event protobuf.Message) (*Envelope, error) {
+	envelope := &Envelope{
+		ID:            stored.ID,
+		Type:          stored.EventType,
+		AggregateKey:  stored.AggregateKey,
+		SchemaVersion: stored.SchemaVersion,
+		CreatedAt:     stored.CreatedAt,
+		Payload:       json.RawMessage(stored.Payload),
+	}
+
+	if event != nil {
+		_, version, payload, err := events.Encode(event)
+		if err != nil {
+			return nil, err
+		}
+		envelope.SchemaVersion = version
+		envelope.Payload = payload
+	}
+
+	return envelope, nil
+}
+
+// NewSink builds a sink from configuration.
+func NewSink(cfg config.SinkConfig, logger *zap.Logger) (Sink, error) {
+	timeout := time.Duration(cfg.TimeoutMs) * time.Millisecond
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+
+	switch cfg.Type {
+	case "file":
+		return NewFileSink(cfg.Path)
+	case "nats":
+		return NewNATSSink(cfg.URL, cfg.Subject, timeout, logger), nil
+	case "kafka":
+		return NewKafkaRESTSink(cfg.URL, cfg.Topic, timeout), nil
+	case "webhook":
+		return NewWebhookSink(cfg.URL, cfg.Secret, timeout), nil
+	default:
+		return nil, fmt.Errorf("unknown outbox sink type %q", cfg.Type)
+	}
+}
+
+// AddSink registers a sink that receives every processed event.
+func (op *OutboxProcessor) AddSink(sink Sink) {
+	op.mu.Lock()
+	defer op.mu.Unlock()
+
+	op.sinks = append(op.sinks, sink)
+	op.logger.Info("Registered outbox sink", zap.String("sink", sink.Name()))
+}
+
+func (op *OutboxProcessor) deliverToSinks(ctx context.Context, stored *postgres.OutboxEvent, event protobuf.Message) error {
+	op.mu.RLock()
+	sinks := make([]Sink, len(op.sinks))
+	copy(sinks, op.sinks)
+	op.mu.RUnlock()
+
+	if len(sinks) == 0 {
+		return nil
+	}
+
+	envelope, err := newEnvelope(stored, event)
+	if err != nil {
+		return err
+	}
+
+	for _, sink := range sinks {
+		if err := sink.Deliver(ctx, envelope); err != nil {
+			return fmt.Errorf("sink %s failed: %w", sink.Name(), err)
+		}
+	}
+
+	return nil
+}
+
+func (op *OutboxProcessor) closeSinks() {
+	op.mu.RLock()
+	defer op.mu.RUnlock()
+
+	for _, sink := range op.sinks {
+		if err := sink.Close(); err != nil {
+			op.logger.Warn("Failed to close outbox sink", zap.String("sink", sink.Name()), zap.Error(err))
+		}
+	}
+}
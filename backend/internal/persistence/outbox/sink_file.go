@@ -0,0 +1,58 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// FileSink appends events as JSON lines to a local file. It is meant for
+// local development and tests, where running a broker is overkill.
+type FileSink struct {
+	path string
+	file *os.File
+	mu   sync.Mutex
+}
+
+func NewFileSink(path string) (*FileSink, error) {
+	if path == "" {
+		return nil, fmt.Errorf("file sink requires a path")
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open outbox file sink: %w", err)
+	}
+
+	return &FileSink{path: path, file: file}, nil
+}
+
+func (fs *FileSink) Name() string {
+	return "file:" + fs.path
+}
+
+func (fs *FileSink) Deliver(ctx context.Context, envelope *Envelope) error {
+	line, err := json.Marshal(envelope)
+	if err != nil {
+		return fmt.Errorf("failed to marshal envelope: %w", err)
+	}
+	line = append(line, '\n')
+
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	if _, err := fs.file.Write(line); err != nil {
+		return fmt.Errorf("failed to write event: %w", err)
+	}
+
+	return nil
+}
+
+func (fs *FileSink) Close() error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	return fs.file.Close()
+}
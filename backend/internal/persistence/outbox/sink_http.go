@@ -0,0 +1,132 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WebhookSink POSTs each event as JSON to a URL. When a secret is set the body
+// is signed with HMAC-SHA256 in the X-Aether-Signature header.
+type WebhookSink struct {
+	url    string
+	secret []byte
+	client *http.Client
+}
+
+func NewWebhookSink(url, secret string, timeout time.Duration) *WebhookSink {
+	return &WebhookSink{
+		url:    url,
+		secret: []byte(secret),
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (ws *WebhookSink) Name() string {
+	return "webhook:" + ws.url
+}
+
+func (ws *WebhookSink) Deliver(ctx context.Context, envelope *Envelope) error {
+	body, err := json.Marshal(envelope)
+	if err != nil {
+		return fmt.Errorf("failed to marshal envelope: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, ws.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build webhook request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Aether-Event-Id", strconv.FormatInt(envelope.ID, 10))
+	req.Header.Set("X-Aether-Event-Type", envelope.Type)
+	if len(ws.secret) > 0 {
+		mac := hmac.New(sha256.New, ws.secret)
+		mac.Write(body)
+		req.Header.Set("X-Aether-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	return doPost(ws.client, req)
+}
+
+func (ws *WebhookSink) Close() error {
+	ws.client.CloseIdleConnections()
+	return nil
+}
+
+// KafkaRESTSink produces events to a Kafka topic through a Kafka REST Proxy
+// (v2 API), which keeps the server free of a native Kafka client. The
+// aggregate key becomes the record key so per-key ordering carries over to
+// Kafka partitions.
+type KafkaRESTSink struct {
+	url    string
+	topic  string
+	client *http.Client
+}
+
+func NewKafkaRESTSink(url, topic string, timeout time.Duration) *KafkaRESTSink {
+	return &KafkaRESTSink{
+		url:    strings.TrimRight(url, "/"),
+		topic:  topic,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (ks *KafkaRESTSink) Name() string {
+	return "kafka:" + ks.topic
+}
+
+func (ks *KafkaRESTSink) Deliver(ctx context.Context, envelope *Envelope) error {
+	type record struct {
+		Key   string    `json:"key,omitempty"`
+		Value *Envelope `json:"value"`
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"records": []record{{Key: envelope.AggregateKey, Value: envelope}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal kafka records: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, ks.url+"/topics/"+ks.topic, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build kafka request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/vnd.kafka.json.v2+json")
+	req.Header.Set("Accept", "application/vnd.kafka.v2+json")
+
+	return doPost(ks.client, req)
+}
+
+func (ks *KafkaRESTSink) Close() error {
+	ks.client.CloseIdleConnections()
+	return nil
+}
+
+func doPost(client *http.Client, req *http.Request) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+
+	// Drain so the connection can be reused
+	io.Copy(io.Discard, resp.Body)
+	return nil
+}
@@ -0,0 +1,165 @@
+package outbox
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// NATSSink publishes events to subject "<prefix>.<event type>" using the NATS
+// text protocol directly over TCP. Each publish is followed by a PING so a
+// delivery only succeeds once the server has read the message.
+type NATSSink struct {
+	url     string
+	prefix  string
+	timeout time.Duration
+	logger  *zap.Logger
+	conn    net.Conn
+	reader  *bufio.Reader
+	mu      sync.Mutex
+}
+
+func NewNATSSink(serverURL, subjectPrefix string, timeout time.Duration, logger *zap.Logger) *NATSSink {
+	if subjectPrefix == "" {
+		subjectPrefix = "aether.events"
+	}
+
+	return &NATSSink{
+		url:     serverURL,
+		prefix:  subjectPrefix,
+		timeout: timeout,
+		logger:  logger,
+	}
+}
+
+func (ns *NATSSink) Name() string {
+	return "nats:" + ns.prefix
+}
+
+func (ns *NATSSink) Deliver(ctx context.Context, envelope *Envelope) error {
+	data, err := json.Marshal(envelope)
+	if err != nil {
+		return fmt.Errorf("failed to marshal envelope: %w", err)
+	}
+
+	ns.mu.Lock()
+	defer ns.mu.Unlock()
+
+	if ns.conn == nil {
+		if err := ns.connect(ctx); err != nil {
+			return err
+		}
+	}
+
+	if err := ns.publish(ns.prefix+"."+envelope.Type, data); err != nil {
+		// Drop the connection; the next delivery reconnects
+		ns.closeConn()
+		return err
+	}
+
+	return nil
+}
+
+func (ns *NATSSink) Close() error {
+	ns.mu.Lock()
+	defer ns.mu.Unlock()
+
+	ns.closeConn()
+	return nil
+}
+
+func (ns *NATSSink) connect(ctx context.Context) error {
+	parsed, err := url.Parse(ns.url)
+	if err != nil {
+		return fmt.Errorf("invalid nats url: %w", err)
+	}
+
+	dialer := net.Dialer{Timeout: ns.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", parsed.Host)
+	if err != nil {
+		return fmt.Errorf("failed to connect to nats: %w", err)
+	}
+
+	ns.conn = conn
+	ns.reader = bufio.NewReader(conn)
+	conn.SetDeadline(time.Now().Add(ns.timeout))
+
+	// Server greets with INFO before accepting CONNECT
+	line, err := ns.reader.ReadString('\n')
+	if err != nil || !strings.HasPrefix(line, "INFO ") {
+		ns.closeConn()
+		return fmt.Errorf("unexpected nats greeting: %q", strings.TrimSpace(line))
+	}
+
+	options := map[string]interface{}{
+		"verbose":  false,
+		"pedantic": false,
+		"name":     "aether-outbox",
+		"lang":     "go",
+		"version":  "1.0.0",
+	}
+	if parsed.User != nil {
+		options["user"] = parsed.User.Username()
+		if pass, ok := parsed.User.Password(); ok {
+			options["pass"] = pass
+		}
+	}
+
+	connectJSON, err := json.Marshal(options)
+	if err != nil {
+		ns.closeConn()
+		return fmt.Errorf("failed to marshal nats connect options: %w", err)
+	}
+
+	if _, err := fmt.Fprintf(conn, "CONNECT %s\r\n", connectJSON); err != nil {
+		ns.closeConn()
+		return fmt.Errorf("failed to send nats connect: %w", err)
+	}
+
+	ns.logger.Info("Connected to NATS", zap.String("server", parsed.Host))
+	return nil
+}
+
+func (ns *NATSSink) publish(subject string, data []byte) error {
+	ns.conn.SetDeadline(time.Now().Add(ns.timeout))
+
+	if _, err := fmt.Fprintf(ns.conn, "PUB %s %d\r\n%s\r\nPING\r\n", subject, len(data), data); err != nil {
+		return fmt.Errorf("failed to publish to nats: %w", err)
+	}
+
+	for {
+		line, err := ns.reader.ReadString('\n')
+		if err != nil {
+			return fmt.Errorf("failed to read nats reply: %w", err)
+		}
+
+		switch {
+		case strings.HasPrefix(line, "PONG"):
+			return nil
+		case strings.HasPrefix(line, "PING"):
+			if _, err := ns.conn.Write([]byte("PONG\r\n")); err != nil {
+				return fmt.Errorf("failed to answer nats ping: %w", err)
+			}
+		case strings.HasPrefix(line, "-ERR"):
+			return fmt.Errorf("nats error: %s", strings.TrimSpace(strings.TrimPrefix(line, "-ERR")))
+		default:
+			// +OK and INFO updates need no action
+		}
+	}
+}
+
+func (ns *NATSSink) closeConn() {
+	if ns.conn != nil {
+		ns.conn.Close()
+		ns.conn = nil
+		ns.reader = nil
+	}
+}
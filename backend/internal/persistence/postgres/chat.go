@@ -0,0 +1,111 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/akarsh-2004/aether/internal/chat"
+	"github.com/jackc/pgx/v5"
+)
+
+// SaveChatMessages appends messages to the chat audit log.
+func (p *PostgresClient) SaveChatMessages(ctx context.Context, messages []*chat.Message) error {
+	columns := []string{
+		"world_id", "sender_client_id", "sender_entity_id", "position_x", "position_y",
+		"text", "original_text", "rejected_by", "recipients", "created_at",
+	}
+
+	_, err := p.pool.CopyFrom(ctx, pgx.Identifier{"chat_messages"}, columns,
+		pgx.CopyFromSlice(len(messages), func(i int) ([]interface{}, error) {
+			msg := messages[i]
+			return []interface{}{
+				msg.WorldID,
+				msg.SenderClientID,
+				int64(msg.SenderEntityID),
+				msg.X,
+				msg.Y,
+				msg.Text,
+				msg.OriginalText,
+				msg.RejectedBy,
+				int32(msg.Recipients),
+				msg.CreatedAt,
+			}, nil
+		}),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save chat messages: %w", err)
+	}
+
+	return nil
+}
+
+// ChatHistory returns audited chat messages matching query, newest first.
+func (p *PostgresClient) ChatHistory(ctx context.Context, query chat.HistoryQuery) ([]*chat.Message, error) {
+	var conditions []string
+	var args []interface{}
+	addCondition := func(clause string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(clause, len(args)))
+	}
+
+	if query.WorldID != "" {
+		addCondition("world_id = $%d", query.WorldID)
+	}
+	if query.SenderClientID != "" {
+		addCondition("sender_client_id = $%d", query.SenderClientID)
+	}
+	if !query.Before.IsZero() {
+		addCondition("created_at < $%d", query.Before)
+	}
+
+	sql := `
+		SELECT id, world_id, sender_client_id, sender_entity_id, position_x, position_y,
+			text, original_text, rejected_by, recipients, created_at
+		FROM chat_messages
+	`
+	if len(conditions) > 0 {
+		sql += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	args = append(args, query.Limit)
+	sql += fmt.Sprintf(" ORDER BY created_at DESC, id DESC LIMIT $%d", len(args))
+
+	rows, err := p.pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query chat history: %w", err)
+	}
+	defer rows.Close()
+
+	messages := make([]*chat.Message, 0, query.Limit)
+	for rows.Next() {
+		var msg chat.Message
+		var entityID int64
+		var recipients int32
+		if err := rows.Scan(
+			&msg.ID,
+			&msg.WorldID,
+			&msg.SenderClientID,
+			&entityID,
+			&msg.X,
+			&msg.Y,
+			&msg.Text,
+			&msg.OriginalText,
+			&msg.RejectedBy,
+			&recipients,
+			&msg.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan chat message: %w", err)
+		}
+		msg.SenderEntityID = uint32(entityID)
+		msg.Recipients = int(recipients)
+		messages = append(messages, &msg)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read chat history: %w", err)
+	}
+
+	return messages, nil
+}
+
+var _ chat.Store = (*PostgresClient)(nil)
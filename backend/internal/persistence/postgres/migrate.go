@@ -0,0 +1,338 @@
+package postgres
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+// migrationLockID is the advisory lock key held while migrating so that
+// several server instances booting at once don't race each other.
+const migrationLockID = 0x61657468 // "aeth"
+
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version  int64
+	Name     string
+	UpSQL    string
+	DownSQL  string
+	Checksum string
+}
+
+type MigrationStatus struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+}
+
+type Migrator struct {
+	pool       *pgxpool.Pool
+	logger     *zap.Logger
+	migrations []*Migration
+}
+
+// NewMigrator loads and validates all migrations in fsys.
+func NewMigrator(pool *pgxpool.Pool, fsys fs.FS, logger *zap.Logger) (*Migrator, error) {
+	migrations, err := loadMigrations(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{
+		pool:       pool,
+		logger:     logger,
+		migrations: migrations,
+	}, nil
+}
+
+func loadMigrations(fsys fs.FS) ([]*Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		match := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %s: %w", entry.Name(), err)
+		}
+
+		data, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", entry.Name(), err)
+		}
+
+		migration, exists := byVersion[version]
+		if !exists {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		} else if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration version %d used by both %s and %s", version, migration.Name, match[2])
+		}
+
+		if match[3] == "up" {
+			migration.UpSQL = string(data)
+			sum := sha256.Sum256(data)
+			migration.Checksum = hex.EncodeToString(sum[:])
+		} else {
+			migration.DownSQL = string(data)
+		}
+	}
+
+	migrations := make([]*Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.UpSQL == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up file", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// Up applies every pending migration in version order. Each migration runs in
+// its own transaction together with its schema_migrations row.
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	applied := 0
+
+	err := m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		existing, err := m.appliedMigrations(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if checksum, done := existing[migration.Version]; done {
+				if checksum != migration.Checksum {
+					return fmt.Errorf("migration %d_%s was modified after being applied", migration.Version, migration.Name)
+				}
+				continue
+			}
+
+			if err := m.apply(ctx, conn, migration); err != nil {
+				return err
+			}
+			applied++
+		}
+
+		return nil
+	})
+
+	return applied, err
+}
+
+// Down reverts the most recently applied migrations, up to steps of them.
+func (m *Migrator) Down(ctx context.Context, steps int) (int, error) {
+	reverted := 0
+
+	err := m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		existing, err := m.appliedMigrations(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && reverted < steps; i-- {
+			migration := m.migrations[i]
+			if _, done := existing[migration.Version]; !done {
+				continue
+			}
+
+			if migration.DownSQL == "" {
+				return fmt.Errorf("migration %d_%s has no down file", migration.Version, migration.Name)
+			}
+
+			if err := m.revert(ctx, conn, migration); err != nil {
+				return err
+			}
+			reverted++
+		}
+
+		return nil
+	})
+
+	return reverted, err
+}
+
+// Status lists every known migration and whether it has been applied.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	conn, err := m.pool.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	if err := m.ensureTable(ctx, conn); err != nil {
+		return nil, err
+	}
+
+	rows, err := conn.Query(ctx, `SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	appliedAt := make(map[int64]time.Time)
+	for rows.Next() {
+		var version int64
+		var at time.Time
+		if err := rows.Scan(&version, &at); err != nil {
+			return nil, fmt.Errorf("failed to scan schema_migrations: %w", err)
+		}
+		appliedAt[version] = at
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		at, applied := appliedAt[migration.Version]
+		statuses = append(statuses, MigrationStatus{
+			Version:   migration.Version,
+			Name:      migration.Name,
+			Applied:   applied,
+			AppliedAt: at,
+		})
+	}
+
+	return statuses, nil
+}
+
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *pgxpool.Conn) error) error {
+	// Advisory locks are per session, so everything runs on one connection
+	conn, err := m.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, `SELECT pg_advisory_lock($1)`, migrationLockID); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	defer func() {
+		if _, err := conn.Exec(context.Background(), `SELECT pg_advisory_unlock($1)`, migrationLockID); err != nil {
+			m.logger.Warn("Failed to release migration lock", zap.Error(err))
+		}
+	}()
+
+	if err := m.ensureTable(ctx, conn); err != nil {
+		return err
+	}
+
+	return fn(conn)
+}
+
+func (m *Migrator) ensureTable(ctx context.Context, conn *pgxpool.Conn) error {
+	query := `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version BIGINT PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			checksum VARCHAR(64) NOT NULL,
+			applied_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		)
+	`
+
+	if _, err := conn.Exec(ctx, query); err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	return nil
+}
+
+func (m *Migrator) appliedMigrations(ctx context.Context, conn *pgxpool.Conn) (map[int64]string, error) {
+	rows, err := conn.Query(ctx, `SELECT version, checksum FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int64]string)
+	for rows.Next() {
+		var version int64
+		var checksum string
+		if err := rows.Scan(&version, &checksum); err != nil {
+			return nil, fmt.Errorf("failed to scan schema_migrations: %w", err)
+		}
+		applied[version] = checksum
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+
+	return applied, nil
+}
+
+func (m *Migrator) apply(ctx context.Context, conn *pgxpool.Conn, migration *Migration) error {
+	start := time.Now()
+
+	err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, migration.UpSQL); err != nil {
+			return err
+		}
+
+		_, err := tx.Exec(ctx,
+			`INSERT INTO schema_migrations (version, name, checksum) VALUES ($1, $2, $3)`,
+			migration.Version, migration.Name, migration.Checksum,
+		)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to apply migration %d_%s: %w", migration.Version, migration.Name, err)
+	}
+
+	m.logger.Info("Applied migration",
+		zap.Int64("version", migration.Version),
+		zap.String("name", migration.Name),
+		zap.Duration("duration", time.Since(start)),
+	)
+
+	return nil
+}
+
+func (m *Migrator) revert(ctx context.Context, conn *pgxpool.Conn, migration *Migration) error {
+	err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, migration.DownSQL); err != nil {
+			return err
+		}
+
+		_, err := tx.Exec(ctx, `DELETE FROM schema_migrations WHERE version = $1`, migration.Version)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to revert migration %d_%s: %w", migration.Version, migration.Name, err)
+	}
+
+	m.logger.Info("Reverted migration",
+		zap.Int64("version", migration.Version),
+		zap.String("name", migration.Name),
+	)
+
+	return nil
+}
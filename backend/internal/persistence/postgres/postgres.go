@@ -7,9 +7,14 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/akarsh-2004/aether/internal/config"
+	"github.com/akarsh-2004/aether/internal/events"
+	"github.com/akarsh-2004/aether/migrations"
+	"github.com/akarsh-2004/aether/proto"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"go.uber.org/zap"
+	protobuf "google.golang.org/protobuf/proto"
 )
 
 type PostgresClient struct {
@@ -19,23 +24,48 @@ type PostgresClient struct {
 }
 
 type EntitySnapshot struct {
-	ID           uint32    `json:"id"`
-	Type         string    `json:"type"`
-	PositionX    float64   `json:"position_x"`
-	PositionY    float64   `json:"position_y"`
-	VelocityX    float64   `json:"velocity_x"`
-	VelocityY    float64   `json:"velocity_y"`
-	ClientID     string    `json:"client_id"`
-	LastUpdate   time.Time `json:"last_update"`
+	ID         uint32          `json:"id"`
+	Type       string          `json:"type"`
+	PositionX  float64         `json:"position_x"`
+	PositionY  float64         `json:"position_y"`
+	VelocityX  float64         `json:"velocity_x"`
+	VelocityY  float64         `json:"velocity_y"`
+	PositionZ  float64         `json:"position_z"`
+	VelocityZ  float64         `json:"velocity_z"`
+	Yaw        float64         `json:"yaw"`
+	ClientID   string          `json:"client_id"`
+	Avatar     bool            `json:"avatar"` // the client's own entity, see entity.Entity.Avatar
+	ResumeHash []byte          `json:"-"`      // SHA-256 of the avatar's resume token
+	LastUpdate time.Time       `json:"last_update"`
+	TickNumber uint64          `json:"tick_number"`
+	Attributes json.RawMessage `json:"attributes"` // JSON object of replicated attributes
+}
+
+// WorldSnapshot marks a complete snapshot of the world taken at TickNumber.
+// Its entities are the entity_snapshots rows with the same tick number.
+type WorldSnapshot struct {
+	RegionID     string    `json:"region_id"` // empty unless sharded
 	TickNumber   uint64    `json:"tick_number"`
+	NextEntityID uint32    `json:"next_entity_id"`
+	EntityCount  int       `json:"entity_count"`
+	CreatedAt    time.Time `json:"created_at"`
 }
 
 type OutboxEvent struct {
-	ID        int64     `json:"id"`
-	EventType string    `json:"event_type"`
-	Payload   string    `json:"payload"`
-	CreatedAt time.Time `json:"created_at"`
-	Processed bool      `json:"processed"`
+	ID            int64     `json:"id"`
+	EventType     string    `json:"event_type"`
+	AggregateKey  string    `json:"aggregate_key"`
+	SchemaVersion int       `json:"schema_version"`
+	Payload       string    `json:"payload"`
+	CreatedAt     time.Time `json:"created_at"`
+	Processed     bool      `json:"processed"`
+	Attempts      int       `json:"attempts"`
+}
+
+// PendingEvent is a domain event to be written to outbox_events.
+type PendingEvent struct {
+	AggregateKey string
+	Event        protobuf.Message
 }
 
 func NewPostgresClient(cfg config.PostgresConfig, logger *zap.Logger) (*PostgresClient, error) {
@@ -72,9 +102,13 @@ func NewPostgresClient(cfg config.PostgresConfig, logger *zap.Logger) (*Postgres
 		config: cfg,
 	}
 
-	// Initialize schema
-	if err := client.initializeSchema(ctx); err != nil {
-		return nil, fmt.Errorf("failed to initialize schema: %w", err)
+	if !cfg.SkipMigrations {
+		migrateCtx, migrateCancel := context.WithTimeout(context.Background(), 60*time.Second)
+		defer migrateCancel()
+
+		if err := client.migrate(migrateCtx); err != nil {
+			return nil, fmt.Errorf("failed to migrate schema: %w", err)
+		}
 	}
 
 	return client, nil
@@ -84,72 +118,35 @@ func (p *PostgresClient) Close() {
 	p.pool.Close()
 }
 
-func (p *PostgresClient) initializeSchema(ctx context.Context) error {
-	queries := []string{
-		`CREATE TABLE IF NOT EXISTS entity_snapshots (
-			id SERIAL PRIMARY KEY,
-			entity_id INTEGER NOT NULL,
-			entity_type VARCHAR(50) NOT NULL,
-			position_x DOUBLE PRECISION NOT NULL,
-			position_y DOUBLE PRECISION NOT NULL,
-			velocity_x DOUBLE PRECISION NOT NULL,
-			velocity_y DOUBLE PRECISION NOT NULL,
-			client_id VARCHAR(100) NOT NULL,
-			last_update TIMESTAMP WITH TIME ZONE NOT NULL,
-			tick_number BIGINT NOT NULL,
-			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
-		)`,
-		
-		`CREATE INDEX IF NOT EXISTS idx_entity_snapshots_entity_id ON entity_snapshots(entity_id)`,
-		`CREATE INDEX IF NOT EXISTS idx_entity_snapshots_client_id ON entity_snapshots(client_id)`,
-		`CREATE INDEX IF NOT EXISTS idx_entity_snapshots_created_at ON entity_snapshots(created_at)`,
-		
-		`CREATE TABLE IF NOT EXISTS outbox_events (
-			id SERIAL PRIMARY KEY,
-			event_type VARCHAR(50) NOT NULL,
-			payload JSONB NOT NULL,
-			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
-			processed BOOLEAN DEFAULT FALSE,
-			processed_at TIMESTAMP WITH TIME ZONE
-		)`,
-		
-		`CREATE INDEX IF NOT EXISTS idx_outbox_events_processed ON outbox_events(processed, created_at)`,
-		`CREATE INDEX IF NOT EXISTS idx_outbox_events_event_type ON outbox_events(event_type)`,
-		
-		`CREATE TABLE IF NOT EXISTS game_sessions (
-			id SERIAL PRIMARY KEY,
-			session_id VARCHAR(100) UNIQUE NOT NULL,
-			client_id VARCHAR(100) NOT NULL,
-			entity_id INTEGER,
-			start_time TIMESTAMP WITH TIME ZONE NOT NULL,
-			end_time TIMESTAMP WITH TIME ZONE,
-			duration_seconds INTEGER,
-			metadata JSONB
-		)`,
-		
-		`CREATE INDEX IF NOT EXISTS idx_game_sessions_client_id ON game_sessions(client_id)`,
-		`CREATE INDEX IF NOT EXISTS idx_game_sessions_start_time ON game_sessions(start_time)`,
-	}
-
-	for _, query := range queries {
-		if _, err := p.pool.Exec(ctx, query); err != nil {
-			return fmt.Errorf("failed to execute schema query: %w", err)
-		}
+// Migrator returns a migrator over the embedded SQL migrations.
+func (p *PostgresClient) Migrator() (*Migrator, error) {
+	return NewMigrator(p.pool, migrations.FS, p.logger)
+}
+
+func (p *PostgresClient) migrate(ctx context.Context) error {
+	migrator, err := p.Migrator()
+	if err != nil {
+		return err
+	}
+
+	applied, err := migrator.Up(ctx)
+	if err != nil {
+		return err
 	}
 
-	p.logger.Info("Database schema initialized")
+	p.logger.Info("Database schema up to date", zap.Int("migrations_applied", applied))
 	return nil
 }
 
 func (p *PostgresClient) SaveEntitySnapshot(ctx context.Context, snapshot *EntitySnapshot) error {
 	query := `
 		INSERT INTO entity_snapshots 
-		(entity_id, entity_type, position_x, position_y, velocity_x, velocity_y, client_id, last_update, tick_number)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		(entity_id, entity_type, position_x, position_y, velocity_x, velocity_y, client_id, last_update, tick_number, attributes, position_z, velocity_z, yaw, avatar, resume_hash)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
 	`
 
 	_, err := p.pool.Exec(ctx, query,
-		snapshot.ID,
+		int64(snapshot.ID),
 		snapshot.Type,
 		snapshot.PositionX,
 		snapshot.PositionY,
@@ -158,6 +155,12 @@ func (p *PostgresClient) SaveEntitySnapshot(ctx context.Context, snapshot *Entit
 		snapshot.ClientID,
 		snapshot.LastUpdate,
 		snapshot.TickNumber,
+		snapshot.Attributes,
+		snapshot.PositionZ,
+		snapshot.VelocityZ,
+		snapshot.Yaw,
+		snapshot.Avatar,
+		snapshot.ResumeHash,
 	)
 
 	if err != nil {
@@ -169,7 +172,7 @@ func (p *PostgresClient) SaveEntitySnapshot(ctx context.Context, snapshot *Entit
 
 func (p *PostgresClient) GetLatestEntitySnapshot(ctx context.Context, entityID uint32) (*EntitySnapshot, error) {
 	query := `
-		SELECT entity_id, entity_type, position_x, position_y, velocity_x, velocity_y, client_id, last_update, tick_number
+		SELECT entity_id, entity_type, position_x, position_y, velocity_x, velocity_y, client_id, last_update, tick_number, attributes, position_z, velocity_z, yaw, avatar, resume_hash
 		FROM entity_snapshots
 		WHERE entity_id = $1
 		ORDER BY created_at DESC
@@ -177,8 +180,9 @@ func (p *PostgresClient) GetLatestEntitySnapshot(ctx context.Context, entityID u
 	`
 
 	var snapshot EntitySnapshot
-	err := p.pool.QueryRow(ctx, query, entityID).Scan(
-		&snapshot.ID,
+	var id int64
+	err := p.pool.QueryRow(ctx, query, int64(entityID)).Scan(
+		&id,
 		&snapshot.Type,
 		&snapshot.PositionX,
 		&snapshot.PositionY,
@@ -187,6 +191,12 @@ func (p *PostgresClient) GetLatestEntitySnapshot(ctx context.Context, entityID u
 		&snapshot.ClientID,
 		&snapshot.LastUpdate,
 		&snapshot.TickNumber,
+		&snapshot.Attributes,
+		&snapshot.PositionZ,
+		&snapshot.VelocityZ,
+		&snapshot.Yaw,
+		&snapshot.Avatar,
+		&snapshot.ResumeHash,
 	)
 
 	if err != nil {
@@ -195,22 +205,173 @@ func (p *PostgresClient) GetLatestEntitySnapshot(ctx context.Context, entityID u
 		}
 		return nil, fmt.Errorf("failed to get entity snapshot: %w", err)
 	}
+	snapshot.ID = uint32(id)
 
 	return &snapshot, nil
 }
 
-func (p *PostgresClient) SaveOutboxEvent(ctx context.Context, eventType string, payload interface{}) error {
-	payloadJSON, err := json.Marshal(payload)
+// SaveWorldSnapshot writes all entities of a world snapshot with COPY and
+// records the snapshot marker in the same transaction, so a partially written
+// snapshot is never picked up on restore. The outbox events raised up to the
+// snapshot are written in that transaction too, so an event is published if
+// and only if the state it describes is persisted.
+func (p *PostgresClient) SaveWorldSnapshot(ctx context.Context, world *WorldSnapshot, snapshots []*EntitySnapshot, pending []PendingEvent) error {
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin snapshot transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Re-snapshotting the same tick (e.g. the final save on shutdown) replaces it
+	clearQuery := `DELETE FROM entity_snapshots WHERE region_id = $1 AND tick_number = $2`
+	if _, err := tx.Exec(ctx, clearQuery, world.RegionID, int64(world.TickNumber)); err != nil {
+		return fmt.Errorf("failed to clear previous snapshot at tick: %w", err)
+	}
+
+	columns := []string{
+		"entity_id", "entity_type", "position_x", "position_y", "velocity_x", "velocity_y",
+		"client_id", "last_update", "tick_number", "attributes", "position_z", "velocity_z", "yaw", "region_id", "avatar", "resume_hash",
+	}
+
+	_, err = tx.CopyFrom(ctx, pgx.Identifier{"entity_snapshots"}, columns,
+		pgx.CopyFromSlice(len(snapshots), func(i int) ([]interface{}, error) {
+			snapshot := snapshots[i]
+			return []interface{}{
+				int64(snapshot.ID),
+				snapshot.Type,
+				snapshot.PositionX,
+				snapshot.PositionY,
+				snapshot.VelocityX,
+				snapshot.VelocityY,
+				snapshot.ClientID,
+				snapshot.LastUpdate,
+				int64(world.TickNumber),
+				snapshot.Attributes,
+				snapshot.PositionZ,
+				snapshot.VelocityZ,
+				snapshot.Yaw,
+				world.RegionID,
+				snapshot.Avatar,
+				snapshot.ResumeHash,
+			}, nil
+		}),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to copy entity snapshots: %w", err)
+	}
+
+	query := `
+		INSERT INTO world_snapshots (region_id, tick_number, next_entity_id, entity_count)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (region_id, tick_number) DO UPDATE
+		SET next_entity_id = EXCLUDED.next_entity_id,
+			entity_count = EXCLUDED.entity_count,
+			created_at = NOW()
+	`
+
+	if _, err := tx.Exec(ctx, query, world.RegionID, int64(world.TickNumber), int64(world.NextEntityID), len(snapshots)); err != nil {
+		return fmt.Errorf("failed to save world snapshot: %w", err)
+	}
+
+	if err := saveOutboxEventsTx(ctx, tx, pending); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit world snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// GetLatestWorldSnapshot returns the most recent complete world snapshot of
+// a region and its entities. A nil snapshot means none has been taken yet.
+func (p *PostgresClient) GetLatestWorldSnapshot(ctx context.Context, regionID string) (*WorldSnapshot, []*EntitySnapshot, error) {
+	worldQuery := `
+		SELECT tick_number, next_entity_id, entity_count, created_at
+		FROM world_snapshots
+		WHERE region_id = $1
+		ORDER BY tick_number DESC
+		LIMIT 1
+	`
+
+	world := WorldSnapshot{RegionID: regionID}
+	var nextEntityID int64
+	err := p.pool.QueryRow(ctx, worldQuery, regionID).Scan(
+		&world.TickNumber,
+		&nextEntityID,
+		&world.EntityCount,
+		&world.CreatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil, nil // No snapshot yet
+		}
+		return nil, nil, fmt.Errorf("failed to get world snapshot: %w", err)
+	}
+	world.NextEntityID = uint32(nextEntityID)
+
+	query := `
+		SELECT entity_id, entity_type, position_x, position_y, velocity_x, velocity_y, client_id, last_update, tick_number, attributes, position_z, velocity_z, yaw, avatar, resume_hash
+		FROM entity_snapshots
+		WHERE region_id = $1 AND tick_number = $2
+		ORDER BY entity_id ASC
+	`
+
+	rows, err := p.pool.Query(ctx, query, regionID, int64(world.TickNumber))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query world snapshot entities: %w", err)
+	}
+	defer rows.Close()
+
+	snapshots := make([]*EntitySnapshot, 0, world.EntityCount)
+	for rows.Next() {
+		var snapshot EntitySnapshot
+		var id int64
+		if err := rows.Scan(
+			&id,
+			&snapshot.Type,
+			&snapshot.PositionX,
+			&snapshot.PositionY,
+			&snapshot.VelocityX,
+			&snapshot.VelocityY,
+			&snapshot.ClientID,
+			&snapshot.LastUpdate,
+			&snapshot.TickNumber,
+			&snapshot.Attributes,
+			&snapshot.PositionZ,
+			&snapshot.VelocityZ,
+			&snapshot.Yaw,
+			&snapshot.Avatar,
+			&snapshot.ResumeHash,
+		); err != nil {
+			return nil, nil, fmt.Errorf("failed to scan entity snapshot: %w", err)
+		}
+		snapshot.ID = uint32(id)
+		snapshots = append(snapshots, &snapshot)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to read world snapshot entities: %w", err)
+	}
+
+	return &world, snapshots, nil
+}
+
+// SaveOutboxEvent queues a typed event for the outbox processor. Events that
+// share a non-empty aggregateKey are delivered strictly in insertion order.
+func (p *PostgresClient) SaveOutboxEvent(ctx context.Context, aggregateKey string, event protobuf.Message) error {
+	eventType, version, payload, err := events.Encode(event)
 	if err != nil {
-		return fmt.Errorf("failed to marshal payload: %w", err)
+		return err
 	}
 
 	query := `
-		INSERT INTO outbox_events (event_type, payload)
-		VALUES ($1, $2)
+		INSERT INTO outbox_events (event_type, aggregate_key, schema_version, payload)
+		VALUES ($1, $2, $3, $4)
 	`
 
-	_, err = p.pool.Exec(ctx, query, eventType, payloadJSON)
+	_, err = p.pool.Exec(ctx, query, eventType, aggregateKey, version, payload)
 	if err != nil {
 		return fmt.Errorf("failed to save outbox event: %w", err)
 	}
@@ -218,18 +379,49 @@ func (p *PostgresClient) SaveOutboxEvent(ctx context.Context, eventType string,
 	return nil
 }
 
-func (p *PostgresClient) GetUnprocessedOutboxEvents(ctx context.Context, limit int) ([]*OutboxEvent, error) {
+// SaveOutboxEvents writes a batch of events in one transaction, for worlds
+// whose state is not persisted.
+func (p *PostgresClient) SaveOutboxEvents(ctx context.Context, pending []PendingEvent) error {
+	return pgx.BeginFunc(ctx, p.pool, func(tx pgx.Tx) error {
+		return saveOutboxEventsTx(ctx, tx, pending)
+	})
+}
+
+// ClaimOutboxEvents leases up to limit due events to workerID for the lease
+// duration. Rows are locked with SKIP LOCKED so concurrent workers never claim
+// the same event, and only the oldest pending event of each aggregate key is
+// eligible so per-key ordering is preserved. Each claim counts as an attempt.
+func (p *PostgresClient) ClaimOutboxEvents(ctx context.Context, workerID string, lease time.Duration, limit int) ([]*OutboxEvent, error) {
 	query := `
-		SELECT id, event_type, payload, created_at, processed
-		FROM outbox_events
-		WHERE processed = FALSE
-		ORDER BY created_at ASC
-		LIMIT $1
+		WITH candidates AS (
+			SELECT e.id
+			FROM outbox_events e
+			WHERE e.processed = FALSE
+				AND e.next_attempt_at <= NOW()
+				AND (e.locked_until IS NULL OR e.locked_until < NOW())
+				AND NOT EXISTS (
+					SELECT 1 FROM outbox_events prior
+					WHERE prior.processed = FALSE
+						AND prior.aggregate_key <> ''
+						AND prior.aggregate_key = e.aggregate_key
+						AND prior.id < e.id
+				)
+			ORDER BY e.id ASC
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE outbox_events o
+		SET locked_by = $1,
+			locked_until = NOW() + INTERVAL '1 millisecond' * $2,
+			attempts = o.attempts + 1
+		FROM candidates c
+		WHERE o.id = c.id
+		RETURNING o.id, o.event_type, o.aggregate_key, o.schema_version, o.payload, o.created_at, o.processed, o.attempts
 	`
 
-	rows, err := p.pool.Query(ctx, query, limit)
+	rows, err := p.pool.Query(ctx, query, workerID, lease.Milliseconds(), limit)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query outbox events: %w", err)
+		return nil, fmt.Errorf("failed to claim outbox events: %w", err)
 	}
 	defer rows.Close()
 
@@ -239,26 +431,35 @@ func (p *PostgresClient) GetUnprocessedOutboxEvents(ctx context.Context, limit i
 		if err := rows.Scan(
 			&event.ID,
 			&event.EventType,
+			&event.AggregateKey,
+			&event.SchemaVersion,
 			&event.Payload,
 			&event.CreatedAt,
 			&event.Processed,
+			&event.Attempts,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan outbox event: %w", err)
 		}
 		events = append(events, &event)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read claimed outbox events: %w", err)
+	}
+
 	return events, nil
 }
 
-func (p *PostgresClient) MarkOutboxEventProcessed(ctx context.Context, eventID int64) error {
+// CompleteOutboxEvent marks a claimed event as processed. It is a no-op if the
+// lease has since passed to another worker.
+func (p *PostgresClient) CompleteOutboxEvent(ctx context.Context, eventID int64, workerID string) error {
 	query := `
 		UPDATE outbox_events
-		SET processed = TRUE, processed_at = NOW()
-		WHERE id = $1
+		SET processed = TRUE, processed_at = NOW(), locked_by = NULL, locked_until = NULL, last_error = NULL
+		WHERE id = $1 AND locked_by = $2
 	`
 
-	_, err := p.pool.Exec(ctx, query, eventID)
+	_, err := p.pool.Exec(ctx, query, eventID, workerID)
 	if err != nil {
 		return fmt.Errorf("failed to mark outbox event as processed: %w", err)
 	}
@@ -266,31 +467,82 @@ func (p *PostgresClient) MarkOutboxEventProcessed(ctx context.Context, eventID i
 	return nil
 }
 
-func (p *PostgresClient) StartGameSession(ctx context.Context, sessionID, clientID string, entityID *uint32) error {
+// RetryOutboxEvent releases a claimed event after a failed attempt and
+// schedules the next attempt.
+func (p *PostgresClient) RetryOutboxEvent(ctx context.Context, eventID int64, workerID string, nextAttempt time.Time, lastError string) error {
 	query := `
-		INSERT INTO game_sessions (session_id, client_id, entity_id, start_time)
-		VALUES ($1, $2, $3, NOW())
+		UPDATE outbox_events
+		SET locked_by = NULL, locked_until = NULL, next_attempt_at = $3, last_error = $4
+		WHERE id = $1 AND locked_by = $2
 	`
 
-	_, err := p.pool.Exec(ctx, query, sessionID, clientID, entityID)
+	_, err := p.pool.Exec(ctx, query, eventID, workerID, nextAttempt, lastError)
 	if err != nil {
-		return fmt.Errorf("failed to start game session: %w", err)
+		return fmt.Errorf("failed to reschedule outbox event: %w", err)
 	}
 
 	return nil
 }
 
-func (p *PostgresClient) EndGameSession(ctx context.Context, sessionID string) error {
+// DeadLetterOutboxEvent moves a claimed event that exhausted its attempts to
+// outbox_dead_letters, unblocking later events with the same aggregate key.
+func (p *PostgresClient) DeadLetterOutboxEvent(ctx context.Context, eventID int64, workerID string, lastError string) error {
+	query := `
+		WITH moved AS (
+			DELETE FROM outbox_events
+			WHERE id = $1 AND locked_by = $2
+			RETURNING id, event_type, aggregate_key, schema_version, payload, attempts, created_at
+		)
+		INSERT INTO outbox_dead_letters (event_id, event_type, aggregate_key, schema_version, payload, attempts, last_error, created_at)
+		SELECT id, event_type, aggregate_key, schema_version, payload, attempts, $3, created_at FROM moved
+	`
+
+	_, err := p.pool.Exec(ctx, query, eventID, workerID, lastError)
+	if err != nil {
+		return fmt.Errorf("failed to dead-letter outbox event: %w", err)
+	}
+
+	return nil
+}
+
+// StartGameSession records a new session and queues a session_started outbox
+// event in the same transaction.
+func (p *PostgresClient) StartGameSession(ctx context.Context, sessionID, clientID string, entityID *uint32) error {
+	return pgx.BeginFunc(ctx, p.pool, func(tx pgx.Tx) error {
+		query := `
+			INSERT INTO game_sessions (session_id, client_id, entity_id, start_time)
+			VALUES ($1, $2, $3, NOW())
+		`
+
+		var storedID *int64
+		if entityID != nil {
+			id := int64(*entityID)
+			storedID = &id
+		}
+
+		if _, err := tx.Exec(ctx, query, sessionID, clientID, storedID); err != nil {
+			return fmt.Errorf("failed to start game session: %w", err)
+		}
+
+		return saveOutboxEventTx(ctx, tx, sessionID, &proto.SessionStartedEvent{
+			SessionId: sessionID,
+			ClientId:  clientID,
+		})
+	})
+}
+
+// SetSessionEntity records the entity a session's client controls. A client
+// that changes entities keeps the latest one.
+func (p *PostgresClient) SetSessionEntity(ctx context.Context, sessionID string, entityID uint32) error {
 	query := `
 		UPDATE game_sessions
-		SET end_time = NOW(),
-			duration_seconds = EXTRACT(EPOCH FROM (NOW() - start_time))::INTEGER
+		SET entity_id = $2
 		WHERE session_id = $1 AND end_time IS NULL
 	`
 
-	result, err := p.pool.Exec(ctx, query, sessionID)
+	result, err := p.pool.Exec(ctx, query, sessionID, int64(entityID))
 	if err != nil {
-		return fmt.Errorf("failed to end game session: %w", err)
+		return fmt.Errorf("failed to set session entity: %w", err)
 	}
 
 	if result.RowsAffected() == 0 {
@@ -300,10 +552,88 @@ func (p *PostgresClient) EndGameSession(ctx context.Context, sessionID string) e
 	return nil
 }
 
+// EndGameSession closes a session and queues a session_ended outbox event
+// carrying its duration in the same transaction.
+func (p *PostgresClient) EndGameSession(ctx context.Context, sessionID string) error {
+	return pgx.BeginFunc(ctx, p.pool, func(tx pgx.Tx) error {
+		query := `
+			UPDATE game_sessions
+			SET end_time = NOW(),
+				duration_seconds = EXTRACT(EPOCH FROM (NOW() - start_time))::INTEGER
+			WHERE session_id = $1 AND end_time IS NULL
+			RETURNING client_id, duration_seconds
+		`
+
+		var clientID string
+		var durationSeconds int
+		if err := tx.QueryRow(ctx, query, sessionID).Scan(&clientID, &durationSeconds); err != nil {
+			if err == pgx.ErrNoRows {
+				return fmt.Errorf("session not found or already ended")
+			}
+			return fmt.Errorf("failed to end game session: %w", err)
+		}
+
+		return saveOutboxEventTx(ctx, tx, sessionID, &proto.SessionEndedEvent{
+			SessionId:       sessionID,
+			ClientId:        clientID,
+			DurationSeconds: int32(durationSeconds),
+		})
+	})
+}
+
+func saveOutboxEventTx(ctx context.Context, tx pgx.Tx, aggregateKey string, event protobuf.Message) error {
+	eventType, version, payload, err := events.Encode(event)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO outbox_events (event_type, aggregate_key, schema_version, payload)
+		VALUES ($1, $2, $3, $4)
+	`
+
+	if _, err := tx.Exec(ctx, query, eventType, aggregateKey, version, payload); err != nil {
+		return fmt.Errorf("failed to save outbox event: %w", err)
+	}
+
+	return nil
+}
+
+// saveOutboxEventsTx writes pending events with COPY, in order, so the
+// outbox IDs keep the order they were raised in.
+func saveOutboxEventsTx(ctx context.Context, tx pgx.Tx, pending []PendingEvent) error {
+	if len(pending) == 0 {
+		return nil
+	}
+
+	rows := make([][]interface{}, 0, len(pending))
+	for _, e := range pending {
+		eventType, version, payload, err := events.Encode(e.Event)
+		if err != nil {
+			return err
+		}
+		rows = append(rows, []interface{}{eventType, e.AggregateKey, version, payload})
+	}
+
+	columns := []string{"event_type", "aggregate_key", "schema_version", "payload"}
+	if _, err := tx.CopyFrom(ctx, pgx.Identifier{"outbox_events"}, columns, pgx.CopyFromRows(rows)); err != nil {
+		return fmt.Errorf("failed to save outbox events: %w", err)
+	}
+
+	return nil
+}
+
 func (p *PostgresClient) CleanupOldSnapshots(ctx context.Context, olderThan time.Duration) error {
+	// Keep the newest world marker of each region and the entity rows of its
+	// tick even if they are old, so restore still works after a long idle
+	// period
 	query := `
-		DELETE FROM entity_snapshots
-		WHERE created_at < NOW() - INTERVAL '1 second' * $1
+		DELETE FROM entity_snapshots e
+		WHERE e.created_at < NOW() - INTERVAL '1 second' * $1
+		AND e.tick_number IS DISTINCT FROM (
+			SELECT MAX(tick_number) FROM world_snapshots latest
+			WHERE latest.region_id = e.region_id
+		)
 	`
 
 	result, err := p.pool.Exec(ctx, query, int64(olderThan.Seconds()))
@@ -311,6 +641,19 @@ func (p *PostgresClient) CleanupOldSnapshots(ctx context.Context, olderThan time
 		return fmt.Errorf("failed to cleanup old snapshots: %w", err)
 	}
 
+	worldQuery := `
+		DELETE FROM world_snapshots w
+		WHERE w.created_at < NOW() - INTERVAL '1 second' * $1
+		AND w.tick_number < (
+			SELECT MAX(tick_number) FROM world_snapshots latest
+			WHERE latest.region_id = w.region_id
+		)
+	`
+
+	if _, err := p.pool.Exec(ctx, worldQuery, int64(olderThan.Seconds())); err != nil {
+		return fmt.Errorf("failed to cleanup old world snapshots: %w", err)
+	}
+
 	p.logger.Info("Cleaned up old snapshots", zap.Int64("deleted_count", result.RowsAffected()))
 	return nil
 }
@@ -326,7 +669,7 @@ func (p *PostgresClient) GetStats(ctx context.Context) (map[string]interface{},
 	stats["acquired_connections"] = poolStats.AcquiredConns()
 
 	// Get table counts
-	tables := []string{"entity_snapshots", "outbox_events", "game_sessions"}
+	tables := []string{"entity_snapshots", "outbox_events", "outbox_dead_letters", "game_sessions"}
 	for _, table := range tables {
 		var count int64
 		query := fmt.Sprintf("SELECT COUNT(*) FROM %s", table)
@@ -0,0 +1,38 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/akarsh-2004/aether/internal/chat"
+)
+
+// chatListsKey is the session data key holding a client's mute and block
+// lists.
+const chatListsKey = "chat_lists"
+
+// LoadChatLists returns a client's mute and block lists, or nil if it has
+// none.
+func (r *RedisClient) LoadChatLists(ctx context.Context, clientID string) (*chat.Lists, error) {
+	data, err := r.GetSessionData(ctx, clientID, chatListsKey)
+	if err != nil || data == "" {
+		return nil, err
+	}
+
+	var lists chat.Lists
+	if err := json.Unmarshal([]byte(data), &lists); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal chat lists: %w", err)
+	}
+	return &lists, nil
+}
+
+func (r *RedisClient) SaveChatLists(ctx context.Context, clientID string, lists *chat.Lists) error {
+	data, err := json.Marshal(lists)
+	if err != nil {
+		return fmt.Errorf("failed to marshal chat lists: %w", err)
+	}
+	return r.SetSessionData(ctx, clientID, chatListsKey, data)
+}
+
+var _ chat.ListStore = (*RedisClient)(nil)
@@ -0,0 +1,244 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/akarsh-2004/aether/internal/lobby"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// Lobby keys: a sorted set per queue scored by enqueue time, an entry per
+// queued client, a matcher lease per queue, single-use join tickets and a
+// notice channel per instance.
+const (
+	lobbyQueuePrefix   = "lobby:queue:"
+	lobbyEntryPrefix   = "lobby:entry:"
+	lobbyMatcherPrefix = "lobby:matcher:"
+	lobbyTicketPrefix  = "lobby:ticket:"
+	lobbyNoticePrefix  = "lobby:notices:"
+)
+
+// claimScript removes queued clients only if every one of them is still in
+// the queue. KEYS[1] is the queue, KEYS[2..] their entries; ARGV the client IDs.
+var claimScript = redis.NewScript(`
+for i, id in ipairs(ARGV) do
+	if redis.call('ZSCORE', KEYS[1], id) == false then
+		return 0
+	end
+end
+for i, id in ipairs(ARGV) do
+	redis.call('ZREM', KEYS[1], id)
+	redis.call('DEL', KEYS[i + 1])
+end
+return 1
+`)
+
+// LobbyStore implements lobby.Store on Redis.
+type LobbyStore struct {
+	client *RedisClient
+	logger *zap.Logger
+}
+
+func NewLobbyStore(client *RedisClient, logger *zap.Logger) *LobbyStore {
+	return &LobbyStore{
+		client: client,
+		logger: logger,
+	}
+}
+
+func (ls *LobbyStore) Enqueue(ctx context.Context, entry *lobby.Entry, ttl time.Duration) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal queue entry: %w", err)
+	}
+
+	pipe := ls.client.client.TxPipeline()
+	pipe.Set(ctx, lobbyEntryPrefix+entry.ClientID, data, ttl)
+	pipe.ZAdd(ctx, lobbyQueuePrefix+entry.Queue, redis.Z{
+		Score:  float64(entry.EnqueuedAt.UnixMilli()),
+		Member: entry.ClientID,
+	})
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to enqueue client: %w", err)
+	}
+	return nil
+}
+
+func (ls *LobbyStore) Remove(ctx context.Context, queue, clientID string) (bool, error) {
+	pipe := ls.client.client.TxPipeline()
+	removed := pipe.ZRem(ctx, lobbyQueuePrefix+queue, clientID)
+	pipe.Del(ctx, lobbyEntryPrefix+clientID)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return false, fmt.Errorf("failed to remove client from queue: %w", err)
+	}
+	return removed.Val() > 0, nil
+}
+
+func (ls *LobbyStore) Entries(ctx context.Context, queue string) ([]*lobby.Entry, error) {
+	queueKey := lobbyQueuePrefix + queue
+
+	clientIDs, err := ls.client.client.ZRange(ctx, queueKey, 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list queue: %w", err)
+	}
+	if len(clientIDs) == 0 {
+		return nil, nil
+	}
+
+	pipe := ls.client.client.Pipeline()
+	cmds := make([]*redis.StringCmd, len(clientIDs))
+	for i, clientID := range clientIDs {
+		cmds[i] = pipe.Get(ctx, lobbyEntryPrefix+clientID)
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("failed to load queue entries: %w", err)
+	}
+
+	entries := make([]*lobby.Entry, 0, len(clientIDs))
+	var stale []interface{}
+	for i, cmd := range cmds {
+		data, err := cmd.Bytes()
+		if err != nil {
+			// Entry expired with its instance; drop it from the queue
+			stale = append(stale, clientIDs[i])
+			continue
+		}
+
+		var entry lobby.Entry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			ls.logger.Warn("Dropping malformed queue entry", zap.String("client_id", clientIDs[i]), zap.Error(err))
+			stale = append(stale, clientIDs[i])
+			continue
+		}
+		entries = append(entries, &entry)
+	}
+
+	if len(stale) > 0 {
+		if err := ls.client.client.ZRem(ctx, queueKey, stale...).Err(); err != nil {
+			ls.logger.Warn("Failed to drop stale queue entries", zap.String("queue", queue), zap.Error(err))
+		}
+	}
+
+	return entries, nil
+}
+
+func (ls *LobbyStore) AcquireMatcher(ctx context.Context, queue, holder string, lease time.Duration) (bool, error) {
+	key := lobbyMatcherPrefix + queue
+
+	acquired, err := ls.client.client.SetNX(ctx, key, holder, lease).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire matcher lease: %w", err)
+	}
+	if acquired {
+		return true, nil
+	}
+
+	renewed, err := renewScript.Run(ctx, ls.client.client, []string{key}, holder, lease.Milliseconds()).Int()
+	if err != nil {
+		return false, fmt.Errorf("failed to renew matcher lease: %w", err)
+	}
+	return renewed == 1, nil
+}
+
+func (ls *LobbyStore) Claim(ctx context.Context, queue string, clientIDs []string) (bool, error) {
+	keys := make([]string, 0, len(clientIDs)+1)
+	args := make([]interface{}, len(clientIDs))
+	keys = append(keys, lobbyQueuePrefix+queue)
+	for i, clientID := range clientIDs {
+		keys = append(keys, lobbyEntryPrefix+clientID)
+		args[i] = clientID
+	}
+
+	claimed, err := claimScript.Run(ctx, ls.client.client, keys, args...).Int()
+	if err != nil {
+		return false, fmt.Errorf("failed to claim queued clients: %w", err)
+	}
+	return claimed == 1, nil
+}
+
+func (ls *LobbyStore) SaveTicket(ctx context.Context, ticket *lobby.Ticket) error {
+	data, err := json.Marshal(ticket)
+	if err != nil {
+		return fmt.Errorf("failed to marshal join ticket: %w", err)
+	}
+
+	if err := ls.client.client.Set(ctx, lobbyTicketPrefix+ticket.ID, data, time.Until(ticket.ExpiresAt)).Err(); err != nil {
+		return fmt.Errorf("failed to save join ticket: %w", err)
+	}
+	return nil
+}
+
+// RedeemTicket returns and deletes a ticket, or nil if it doesn't exist.
+func (ls *LobbyStore) RedeemTicket(ctx context.Context, ticketID string) (*lobby.Ticket, error) {
+	key := lobbyTicketPrefix + ticketID
+
+	pipe := ls.client.client.TxPipeline()
+	get := pipe.Get(ctx, key)
+	pipe.Del(ctx, key)
+	if _, err := pipe.Exec(ctx); err != nil {
+		if err == redis.Nil {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to redeem join ticket: %w", err)
+	}
+
+	var ticket lobby.Ticket
+	if err := json.Unmarshal([]byte(get.Val()), &ticket); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal join ticket: %w", err)
+	}
+	return &ticket, nil
+}
+
+func (ls *LobbyStore) Notify(ctx context.Context, instanceID string, notice *lobby.Notice) error {
+	data, err := json.Marshal(notice)
+	if err != nil {
+		return fmt.Errorf("failed to marshal lobby notice: %w", err)
+	}
+
+	if err := ls.client.client.Publish(ctx, lobbyNoticePrefix+instanceID, data).Err(); err != nil {
+		return fmt.Errorf("failed to publish lobby notice: %w", err)
+	}
+	return nil
+}
+
+func (ls *LobbyStore) Notices(ctx context.Context, instanceID string) <-chan *lobby.Notice {
+	out := make(chan *lobby.Notice, 64)
+	pubsub := ls.client.client.Subscribe(ctx, lobbyNoticePrefix+instanceID)
+
+	go func() {
+		defer close(out)
+		defer pubsub.Close()
+
+		messages := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+
+				var notice lobby.Notice
+				if err := json.Unmarshal([]byte(msg.Payload), &notice); err != nil {
+					ls.logger.Warn("Dropping malformed lobby notice", zap.Error(err))
+					continue
+				}
+
+				select {
+				case out <- &notice:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return out
+}
+
+var _ lobby.Store = (*LobbyStore)(nil)
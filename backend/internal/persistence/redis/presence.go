@@ -0,0 +1,401 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// Presence keys. Each instance keeps a sorted set of its clients scored by
+// last heartbeat, so staleness is judged per client instead of by a TTL on a
+// shared set. Client details live in a hash and positions in a GEO set per
+// world, since coordinates of different worlds are unrelated.
+const (
+	instancesKey      = "presence:instances"
+	instanceKeyPrefix = "presence:instance:"
+	clientKeyPrefix   = "presence:client:"
+	geoKeyPrefix      = "presence:geo:"
+)
+
+// Mean earth radius Redis uses for GEO distance calculations, in meters.
+const geoEarthRadius = 6372797.560856
+
+type PresenceData struct {
+	ClientID   string    `json:"client_id"`
+	InstanceID string    `json:"instance_id"`
+	WorldID    string    `json:"world_id"`
+	EntityID   uint32    `json:"entity_id"`
+	LastSeen   time.Time `json:"last_seen"`
+	PositionX  float64   `json:"position_x"`
+	PositionY  float64   `json:"position_y"`
+	Distance   float64   `json:"distance,omitempty"` // Set by Nearby, in world units
+	TokenHash  string    `json:"-"`                  // hex SHA-256 of the client's resume token
+}
+
+// removeScript drops clients from an instance's set, and deletes their
+// details only if they still belong to that instance. A client that already
+// reconnected elsewhere keeps its newer presence.
+//
+// KEYS[1] instance set
+// ARGV[1] instance ID, ARGV[2] client key prefix, ARGV[3] geo key prefix,
+// ARGV[4...] client IDs
+var removeScript = redis.NewScript(`
+for i = 4, #ARGV do
+	local id = ARGV[i]
+	redis.call('ZREM', KEYS[1], id)
+	local key = ARGV[2] .. id
+	if redis.call('HGET', key, 'instance') == ARGV[1] then
+		local world = redis.call('HGET', key, 'world') or ''
+		redis.call('DEL', key)
+		redis.call('ZREM', ARGV[3] .. world, id)
+	end
+end
+return #ARGV - 3
+`)
+
+// PresenceDirectory tracks which clients are online on which gateway
+// instance and where their entities are. Every instance runs one; any
+// instance can answer directory queries for the whole cluster.
+type PresenceDirectory struct {
+	client          *RedisClient
+	instanceID      string
+	staleAfter      time.Duration
+	instanceTimeout time.Duration
+	unitsPerDegree  float64
+	logger          *zap.Logger
+	stopChan        chan struct{}
+	wg              sync.WaitGroup
+	stopOnce        sync.Once
+}
+
+// NewPresenceDirectory creates a directory for instanceID. World coordinates
+// are mapped to GEO coordinates at unitsPerDegree world units per degree;
+// keep the world within a few degrees of the origin so distances stay
+// accurate.
+func NewPresenceDirectory(client *RedisClient, instanceID string, unitsPerDegree float64, logger *zap.Logger) *PresenceDirectory {
+	return &PresenceDirectory{
+		client:          client,
+		instanceID:      instanceID,
+		staleAfter:      time.Duration(client.config.PresenceTimeoutSec) * time.Second,
+		instanceTimeout: time.Duration(client.config.InstanceTimeoutSec) * time.Second,
+		unitsPerDegree:  unitsPerDegree,
+		logger:          logger,
+		stopChan:        make(chan struct{}),
+	}
+}
+
+func (pd *PresenceDirectory) InstanceID() string {
+	return pd.instanceID
+}
+
+// Start registers the instance and runs the heartbeat and sweep loop until
+// ctx is cancelled or Stop is called.
+func (pd *PresenceDirectory) Start(ctx context.Context) {
+	pd.beat(ctx)
+
+	pd.wg.Add(1)
+	go func() {
+		defer pd.wg.Done()
+
+		// Beat several times per timeout so one slow round doesn't expire us
+		ticker := time.NewTicker(pd.instanceTimeout / 3)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-pd.stopChan:
+				return
+			case <-ticker.C:
+				pd.beat(ctx)
+			}
+		}
+	}()
+
+	pd.logger.Info("Presence directory started", zap.String("instance_id", pd.instanceID))
+}
+
+// Stop ends the heartbeat loop and removes this instance and its clients.
+func (pd *PresenceDirectory) Stop(ctx context.Context) {
+	pd.stopOnce.Do(func() {
+		close(pd.stopChan)
+	})
+	pd.wg.Wait()
+
+	if err := pd.removeInstance(ctx, pd.instanceID); err != nil {
+		pd.logger.Warn("Failed to remove instance presence", zap.Error(err))
+	}
+}
+
+func (pd *PresenceDirectory) beat(ctx context.Context) {
+	now := time.Now()
+	if err := pd.client.client.ZAdd(ctx, instancesKey, redis.Z{
+		Score:  float64(now.UnixMilli()),
+		Member: pd.instanceID,
+	}).Err(); err != nil {
+		pd.logger.Warn("Failed to update instance heartbeat", zap.Error(err))
+		return
+	}
+
+	if removed, err := pd.sweepClients(ctx, pd.instanceID, now.Add(-pd.staleAfter)); err != nil {
+		pd.logger.Warn("Failed to sweep stale clients", zap.Error(err))
+	} else if removed > 0 {
+		pd.logger.Info("Removed stale client presence", zap.Int("count", removed))
+	}
+
+	if err := pd.sweepInstances(ctx, now.Add(-pd.instanceTimeout)); err != nil {
+		pd.logger.Warn("Failed to sweep dead instances", zap.Error(err))
+	}
+}
+
+// Touch records a heartbeat from a client hosted on this instance.
+// tokenHash lets directory callers prove they are the client.
+func (pd *PresenceDirectory) Touch(ctx context.Context, clientID, worldID string, entityID uint32, x, y float64, tokenHash string) error {
+	now := time.Now()
+	lon, lat := pd.toGeo(x, y)
+
+	// Only this instance writes the client, so the read can't go stale
+	previous, err := pd.client.client.HGet(ctx, clientKeyPrefix+clientID, "world").Result()
+	if err != nil && err != redis.Nil {
+		return fmt.Errorf("failed to read presence: %w", err)
+	}
+
+	_, err = pd.client.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZAdd(ctx, instanceKeyPrefix+pd.instanceID, redis.Z{Score: float64(now.UnixMilli()), Member: clientID})
+		pipe.HSet(ctx, clientKeyPrefix+clientID,
+			"instance", pd.instanceID,
+			"world", worldID,
+			"entity_id", entityID,
+			"x", x,
+			"y", y,
+			"last_seen", now.UnixMilli(),
+			"token_hash", tokenHash,
+		)
+		if previous != worldID {
+			pipe.ZRem(ctx, geoKeyPrefix+previous, clientID)
+		}
+		pipe.GeoAdd(ctx, geoKeyPrefix+worldID, &redis.GeoLocation{Name: clientID, Longitude: lon, Latitude: lat})
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update presence: %w", err)
+	}
+
+	return nil
+}
+
+// Remove drops a client that disconnected from this instance.
+func (pd *PresenceDirectory) Remove(ctx context.Context, clientID string) error {
+	if err := pd.removeClients(ctx, pd.instanceID, []string{clientID}); err != nil {
+		return fmt.Errorf("failed to remove presence: %w", err)
+	}
+	return nil
+}
+
+// Locate returns where a client is online, or nil if it isn't.
+func (pd *PresenceDirectory) Locate(ctx context.Context, clientID string) (*PresenceData, error) {
+	fields, err := pd.client.client.HGetAll(ctx, clientKeyPrefix+clientID).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get presence: %w", err)
+	}
+
+	presence := pd.parsePresence(clientID, fields)
+	if presence == nil || time.Since(presence.LastSeen) > pd.staleAfter {
+		return nil, nil
+	}
+
+	return presence, nil
+}
+
+// Nearby returns online clients in a world within radius world units of
+// (x, y), nearest first. limit <= 0 means no limit.
+func (pd *PresenceDirectory) Nearby(ctx context.Context, worldID string, x, y, radius float64, limit int) ([]PresenceData, error) {
+	lon, lat := pd.toGeo(x, y)
+
+	locations, err := pd.client.client.GeoSearchLocation(ctx, geoKeyPrefix+worldID, &redis.GeoSearchLocationQuery{
+		GeoSearchQuery: redis.GeoSearchQuery{
+			Longitude:  lon,
+			Latitude:   lat,
+			Radius:     pd.toMeters(radius),
+			RadiusUnit: "m",
+			Sort:       "ASC",
+			Count:      limit,
+		},
+		WithDist: true,
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to search nearby presence: %w", err)
+	}
+
+	if len(locations) == 0 {
+		return nil, nil
+	}
+
+	pipe := pd.client.client.Pipeline()
+	cmds := make([]*redis.MapStringStringCmd, len(locations))
+	for i, loc := range locations {
+		cmds[i] = pipe.HGetAll(ctx, clientKeyPrefix+loc.Name)
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("failed to load nearby presence: %w", err)
+	}
+
+	results := make([]PresenceData, 0, len(locations))
+	for i, loc := range locations {
+		presence := pd.parsePresence(loc.Name, cmds[i].Val())
+		// Skip entries the sweeper hasn't caught up with yet
+		if presence == nil || time.Since(presence.LastSeen) > pd.staleAfter {
+			continue
+		}
+		presence.Distance = math.Hypot(presence.PositionX-x, presence.PositionY-y)
+		results = append(results, *presence)
+	}
+
+	return results, nil
+}
+
+// InstanceClients returns the IDs of clients online on an instance.
+func (pd *PresenceDirectory) InstanceClients(ctx context.Context, instanceID string) ([]string, error) {
+	cutoff := time.Now().Add(-pd.staleAfter).UnixMilli()
+	clients, err := pd.client.client.ZRangeByScore(ctx, instanceKeyPrefix+instanceID, &redis.ZRangeBy{
+		Min: strconv.FormatInt(cutoff, 10),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get instance clients: %w", err)
+	}
+	return clients, nil
+}
+
+// Instances returns the IDs of live instances.
+func (pd *PresenceDirectory) Instances(ctx context.Context) ([]string, error) {
+	cutoff := time.Now().Add(-pd.instanceTimeout).UnixMilli()
+	instances, err := pd.client.client.ZRangeByScore(ctx, instancesKey, &redis.ZRangeBy{
+		Min: strconv.FormatInt(cutoff, 10),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get instances: %w", err)
+	}
+	return instances, nil
+}
+
+func (pd *PresenceDirectory) sweepClients(ctx context.Context, instanceID string, cutoff time.Time) (int, error) {
+	stale, err := pd.client.client.ZRangeByScore(ctx, instanceKeyPrefix+instanceID, &redis.ZRangeBy{
+		Min: "-inf",
+		Max: strconv.FormatInt(cutoff.UnixMilli(), 10),
+	}).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	if len(stale) == 0 {
+		return 0, nil
+	}
+
+	return len(stale), pd.removeClients(ctx, instanceID, stale)
+}
+
+// sweepInstances removes instances that stopped heartbeating, along with
+// every client they hosted. Any instance may do this; it is idempotent.
+func (pd *PresenceDirectory) sweepInstances(ctx context.Context, cutoff time.Time) error {
+	dead, err := pd.client.client.ZRangeByScore(ctx, instancesKey, &redis.ZRangeBy{
+		Min: "-inf",
+		Max: strconv.FormatInt(cutoff.UnixMilli(), 10),
+	}).Result()
+	if err != nil {
+		return err
+	}
+
+	for _, instanceID := range dead {
+		if err := pd.removeInstance(ctx, instanceID); err != nil {
+			return err
+		}
+		pd.logger.Info("Removed dead instance presence", zap.String("instance_id", instanceID))
+	}
+
+	return nil
+}
+
+func (pd *PresenceDirectory) removeInstance(ctx context.Context, instanceID string) error {
+	clients, err := pd.client.client.ZRange(ctx, instanceKeyPrefix+instanceID, 0, -1).Result()
+	if err != nil {
+		return err
+	}
+
+	if err := pd.removeClients(ctx, instanceID, clients); err != nil {
+		return err
+	}
+
+	return pd.client.client.ZRem(ctx, instancesKey, instanceID).Err()
+}
+
+func (pd *PresenceDirectory) removeClients(ctx context.Context, instanceID string, clientIDs []string) error {
+	if len(clientIDs) == 0 {
+		return nil
+	}
+
+	args := make([]interface{}, 0, len(clientIDs)+3)
+	args = append(args, instanceID, clientKeyPrefix, geoKeyPrefix)
+	for _, id := range clientIDs {
+		args = append(args, id)
+	}
+
+	return removeScript.Run(ctx, pd.client.client, []string{instanceKeyPrefix + instanceID}, args...).Err()
+}
+
+func (pd *PresenceDirectory) parsePresence(clientID string, fields map[string]string) *PresenceData {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	entityID, _ := strconv.ParseUint(fields["entity_id"], 10, 32)
+	x, _ := strconv.ParseFloat(fields["x"], 64)
+	y, _ := strconv.ParseFloat(fields["y"], 64)
+	lastSeen, _ := strconv.ParseInt(fields["last_seen"], 10, 64)
+
+	return &PresenceData{
+		ClientID:   clientID,
+		InstanceID: fields["instance"],
+		WorldID:    fields["world"],
+		EntityID:   uint32(entityID),
+		LastSeen:   time.UnixMilli(lastSeen),
+		PositionX:  x,
+		PositionY:  y,
+		TokenHash:  fields["token_hash"],
+	}
+}
+
+func (pd *PresenceDirectory) toGeo(x, y float64) (lon, lat float64) {
+	return x / pd.unitsPerDegree, y / pd.unitsPerDegree
+}
+
+func (pd *PresenceDirectory) toMeters(units float64) float64 {
+	return units / pd.unitsPerDegree * math.Pi / 180 * geoEarthRadius
+}
+
+// countOnline returns the number of clients online across live instances.
+func (r *RedisClient) countOnline(ctx context.Context) (int64, error) {
+	instances, err := r.client.ZRange(ctx, instancesKey, 0, -1).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	var total int64
+	for _, instanceID := range instances {
+		n, err := r.client.ZCard(ctx, instanceKeyPrefix+instanceID).Result()
+		if err != nil {
+			return 0, err
+		}
+		total += n
+	}
+
+	return total, nil
+}
@@ -0,0 +1,65 @@
+package redis
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestParsePresence(t *testing.T) {
+	pd := &PresenceDirectory{unitsPerDegree: 1000}
+
+	if pd.parsePresence("gone", nil) != nil {
+		t.Error("expected no presence for a missing hash")
+	}
+
+	presence := pd.parsePresence("alice", map[string]string{
+		"instance":   "gw-1",
+		"world":      "arena-2",
+		"entity_id":  "42",
+		"x":          "12.5",
+		"y":          "-7.25",
+		"last_seen":  "1700000000000",
+		"token_hash": "abc123",
+	})
+	want := PresenceData{
+		ClientID:   "alice",
+		InstanceID: "gw-1",
+		WorldID:    "arena-2",
+		EntityID:   42,
+		LastSeen:   time.UnixMilli(1700000000000),
+		PositionX:  12.5,
+		PositionY:  -7.25,
+		TokenHash:  "abc123",
+	}
+	if presence == nil || *presence != want {
+		t.Errorf("expected %+v, got %+v", want, presence)
+	}
+}
+
+func TestPresenceGeoScale(t *testing.T) {
+	pd := &PresenceDirectory{unitsPerDegree: 1000}
+
+	tests := []struct {
+		x, y     float64
+		lon, lat float64
+	}{
+		{0, 0, 0, 0},
+		{1000, -500, 1, -0.5},
+		{-2500, 250, -2.5, 0.25},
+	}
+	for _, tt := range tests {
+		if lon, lat := pd.toGeo(tt.x, tt.y); lon != tt.lon || lat != tt.lat {
+			t.Errorf("toGeo(%v, %v): expected %v, %v, got %v, %v", tt.x, tt.y, tt.lon, tt.lat, lon, lat)
+		}
+	}
+
+	// One degree of arc on Redis' sphere, about 111km
+	oneDegree := geoEarthRadius * math.Pi / 180
+	if got := pd.toMeters(1000); math.Abs(got-oneDegree) > 1e-6 {
+		t.Errorf("expected %v meters per degree, got %v", oneDegree, got)
+	}
+	if got := pd.toMeters(250); math.Abs(got-oneDegree/4) > 1e-6 {
+		t.Errorf("expected distances to scale linearly, got %v", got)
+	}
+}
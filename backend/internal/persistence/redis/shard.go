@@ -0,0 +1,272 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/akarsh-2004/aether/internal/shard"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// Shard keys: a lease per region, a list of pending handoffs per region so
+// none are lost while the owner is busy, and a pub/sub channel for ghosts.
+const (
+	regionKeyPrefix    = "shard:region:"
+	handoffKeyPrefix   = "shard:handoff:"
+	ghostChannelPrefix = "shard:ghosts:"
+)
+
+// renewScript extends a region lease only if we still hold it.
+var renewScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// releaseScript deletes a region lease only if we still hold it.
+var releaseScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+type regionLease struct {
+	InstanceID string `json:"instance_id"`
+	Addr       string `json:"addr"`
+}
+
+// RegionCoordinator implements shard.Coordinator on Redis.
+type RegionCoordinator struct {
+	client     *RedisClient
+	instanceID string
+	lease      time.Duration
+	logger     *zap.Logger
+	mu         sync.Mutex
+	claims     map[string]claim
+}
+
+type claim struct {
+	value  string
+	cancel context.CancelFunc
+}
+
+func NewRegionCoordinator(client *RedisClient, instanceID string, lease time.Duration, logger *zap.Logger) *RegionCoordinator {
+	return &RegionCoordinator{
+		client:     client,
+		instanceID: instanceID,
+		lease:      lease,
+		logger:     logger,
+		claims:     make(map[string]claim),
+	}
+}
+
+// ClaimRegion takes the region's lease and renews it in the background. The
+// returned channel is closed if another instance takes the lease, or if it
+// could not be renewed for a whole lease period and may have expired.
+func (rc *RegionCoordinator) ClaimRegion(ctx context.Context, regionID, advertiseAddr string) (<-chan struct{}, error) {
+	data, err := json.Marshal(regionLease{InstanceID: rc.instanceID, Addr: advertiseAddr})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal region lease: %w", err)
+	}
+	value := string(data)
+	key := regionKeyPrefix + regionID
+
+	acquired, err := rc.client.client.SetNX(ctx, key, value, rc.lease).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim region: %w", err)
+	}
+
+	if !acquired {
+		// A quick restart with the same instance ID may still hold the lease
+		renewed, err := renewScript.Run(ctx, rc.client.client, []string{key}, value, rc.lease.Milliseconds()).Int()
+		if err != nil {
+			return nil, fmt.Errorf("failed to claim region: %w", err)
+		}
+		if renewed == 0 {
+			return nil, fmt.Errorf("%w: %s", shard.ErrRegionOwned, regionID)
+		}
+	}
+
+	renewCtx, cancel := context.WithCancel(context.Background())
+
+	rc.mu.Lock()
+	rc.claims[regionID] = claim{value: value, cancel: cancel}
+	rc.mu.Unlock()
+
+	lost := make(chan struct{})
+	go rc.renewLoop(renewCtx, regionID, key, value, lost)
+
+	rc.logger.Info("Claimed region", zap.String("region", regionID), zap.String("addr", advertiseAddr))
+	return lost, nil
+}
+
+// renewLoop keeps the lease alive and closes lost once it can no longer be
+// sure the lease is held.
+func (rc *RegionCoordinator) renewLoop(ctx context.Context, regionID, key, value string, lost chan<- struct{}) {
+	ticker := time.NewTicker(rc.lease / 3)
+	defer ticker.Stop()
+
+	renewedAt := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			renewed, err := renewScript.Run(ctx, rc.client.client, []string{key}, value, rc.lease.Milliseconds()).Int()
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				if time.Since(renewedAt) < rc.lease {
+					rc.logger.Warn("Failed to renew region lease", zap.String("region", regionID), zap.Error(err))
+					continue
+				}
+				rc.logger.Error("Region lease expired without renewal", zap.String("region", regionID), zap.Error(err))
+				close(lost)
+				return
+			}
+			if renewed == 0 {
+				rc.logger.Error("Lost region lease to another instance", zap.String("region", regionID))
+				close(lost)
+				return
+			}
+			renewedAt = time.Now()
+		}
+	}
+}
+
+func (rc *RegionCoordinator) ReleaseRegion(ctx context.Context, regionID string) error {
+	rc.mu.Lock()
+	held, exists := rc.claims[regionID]
+	delete(rc.claims, regionID)
+	rc.mu.Unlock()
+
+	if !exists {
+		return nil
+	}
+	held.cancel()
+
+	if err := releaseScript.Run(ctx, rc.client.client, []string{regionKeyPrefix + regionID}, held.value).Err(); err != nil {
+		return fmt.Errorf("failed to release region: %w", err)
+	}
+	return nil
+}
+
+func (rc *RegionCoordinator) RegionOwner(ctx context.Context, regionID string) (string, error) {
+	data, err := rc.client.client.Get(ctx, regionKeyPrefix+regionID).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get region owner: %w", err)
+	}
+
+	var lease regionLease
+	if err := json.Unmarshal([]byte(data), &lease); err != nil {
+		return "", fmt.Errorf("failed to unmarshal region lease: %w", err)
+	}
+	return lease.Addr, nil
+}
+
+func (rc *RegionCoordinator) SendHandoff(ctx context.Context, toRegion string, handoff *shard.Handoff) error {
+	data, err := json.Marshal(handoff)
+	if err != nil {
+		return fmt.Errorf("failed to marshal handoff: %w", err)
+	}
+
+	if err := rc.client.client.RPush(ctx, handoffKeyPrefix+toRegion, data).Err(); err != nil {
+		return fmt.Errorf("failed to send handoff: %w", err)
+	}
+	return nil
+}
+
+func (rc *RegionCoordinator) Handoffs(ctx context.Context, regionID string) <-chan *shard.Handoff {
+	out := make(chan *shard.Handoff, 64)
+	key := handoffKeyPrefix + regionID
+
+	go func() {
+		defer close(out)
+
+		for ctx.Err() == nil {
+			result, err := rc.client.client.BLPop(ctx, time.Second, key).Result()
+			if err != nil {
+				if err != redis.Nil && ctx.Err() == nil {
+					rc.logger.Warn("Failed to receive handoff", zap.String("region", regionID), zap.Error(err))
+					time.Sleep(time.Second)
+				}
+				continue
+			}
+
+			// result is [key, value]
+			var handoff shard.Handoff
+			if err := json.Unmarshal([]byte(result[1]), &handoff); err != nil {
+				rc.logger.Error("Dropping malformed handoff", zap.String("region", regionID), zap.Error(err))
+				continue
+			}
+
+			select {
+			case out <- &handoff:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out
+}
+
+func (rc *RegionCoordinator) PublishGhosts(ctx context.Context, toRegion string, batch *shard.GhostBatch) error {
+	data, err := json.Marshal(batch)
+	if err != nil {
+		return fmt.Errorf("failed to marshal ghosts: %w", err)
+	}
+
+	if err := rc.client.client.Publish(ctx, ghostChannelPrefix+toRegion, data).Err(); err != nil {
+		return fmt.Errorf("failed to publish ghosts: %w", err)
+	}
+	return nil
+}
+
+func (rc *RegionCoordinator) Ghosts(ctx context.Context, regionID string) <-chan *shard.GhostBatch {
+	out := make(chan *shard.GhostBatch, 16)
+	pubsub := rc.client.client.Subscribe(ctx, ghostChannelPrefix+regionID)
+
+	go func() {
+		defer close(out)
+		defer pubsub.Close()
+
+		messages := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+
+				var batch shard.GhostBatch
+				if err := json.Unmarshal([]byte(msg.Payload), &batch); err != nil {
+					rc.logger.Warn("Dropping malformed ghost batch", zap.String("region", regionID), zap.Error(err))
+					continue
+				}
+
+				select {
+				case out <- &batch:
+				default:
+					// Behind; a newer batch will follow
+				}
+			}
+		}
+	}()
+
+	return out
+}
+
+var _ shard.Coordinator = (*RegionCoordinator)(nil)
@@ -0,0 +1,86 @@
+package snapshot
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/akarsh-2004/aether/internal/engine"
+	"github.com/akarsh-2004/aether/internal/persistence/postgres"
+	"go.uber.org/zap"
+)
+
+// EventFlusher is a tick handler that writes a world's pending outbox events
+// every interval, for worlds that are not snapshotted. Like the Snapshotter
+// it writes in the background and acknowledges events only once committed,
+// so a failed write is retried with the next batch instead of losing them.
+type EventFlusher struct {
+	pgClient  *postgres.PostgresClient
+	engine    *engine.SpatialEngine
+	logger    *zap.Logger
+	interval  time.Duration
+	lastFlush time.Time
+	inFlight  bool
+	mu        sync.Mutex
+	wg        sync.WaitGroup
+}
+
+func NewEventFlusher(pgClient *postgres.PostgresClient, eng *engine.SpatialEngine, interval time.Duration, logger *zap.Logger) *EventFlusher {
+	return &EventFlusher{
+		pgClient:  pgClient,
+		engine:    eng,
+		logger:    logger,
+		interval:  interval,
+		lastFlush: time.Now(),
+	}
+}
+
+func (f *EventFlusher) OnTick(tickNumber uint64) {
+	if time.Since(f.lastFlush) < f.interval {
+		return
+	}
+
+	f.mu.Lock()
+	if f.inFlight {
+		f.mu.Unlock()
+		return
+	}
+	f.inFlight = true
+	f.mu.Unlock()
+
+	f.lastFlush = time.Now()
+	events := f.engine.PendingEvents()
+
+	f.wg.Add(1)
+	go func() {
+		defer f.wg.Done()
+		defer func() {
+			f.mu.Lock()
+			f.inFlight = false
+			f.mu.Unlock()
+		}()
+
+		f.flush(events)
+	}()
+}
+
+// OnShutdown waits for an in-flight write and then writes what is left.
+func (f *EventFlusher) OnShutdown() {
+	f.wg.Wait()
+	f.flush(f.engine.PendingEvents())
+}
+
+func (f *EventFlusher) flush(events []engine.OutboxEvent) {
+	if len(events) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := f.pgClient.SaveOutboxEvents(ctx, pendingEvents(events)); err != nil {
+		f.logger.Error("Failed to write outbox events", zap.Int("event_count", len(events)), zap.Error(err))
+		return
+	}
+	f.engine.AckEvents(events[len(events)-1].Seq)
+}
@@ -0,0 +1,165 @@
+package snapshot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/akarsh-2004/aether/internal/engine"
+	"github.com/akarsh-2004/aether/internal/engine/entity"
+	"github.com/akarsh-2004/aether/internal/persistence/postgres"
+	"go.uber.org/zap"
+)
+
+// Store persists engine world state to the entity_snapshots table and loads it
+// back on boot.
+type Store struct {
+	pgClient *postgres.PostgresClient
+	logger   *zap.Logger
+	regionID string
+}
+
+func NewStore(pgClient *postgres.PostgresClient, logger *zap.Logger) *Store {
+	return &Store{
+		pgClient: pgClient,
+		logger:   logger,
+	}
+}
+
+// SetRegion keys the snapshots by the region this process simulates. It
+// must be called before the first save or restore when sharding is enabled.
+func (s *Store) SetRegion(regionID string) {
+	s.regionID = regionID
+}
+
+// SaveWorld snapshots the engine and writes it in a single batch, together
+// with the engine's pending outbox events.
+func (s *Store) SaveWorld(ctx context.Context, eng *engine.SpatialEngine) error {
+	return s.save(ctx, eng, eng.SnapshotWorld())
+}
+
+// save writes world and acknowledges its events once they are committed.
+func (s *Store) save(ctx context.Context, eng *engine.SpatialEngine, world engine.WorldSnapshot) error {
+	snapshots := make([]*postgres.EntitySnapshot, 0, len(world.Entities))
+	for i := range world.Entities {
+		snap, err := toSnapshot(&world.Entities[i], world.TickNumber)
+		if err != nil {
+			return err
+		}
+		snapshots = append(snapshots, snap)
+	}
+
+	marker := &postgres.WorldSnapshot{
+		RegionID:     s.regionID,
+		TickNumber:   world.TickNumber,
+		NextEntityID: world.NextEntityID,
+		EntityCount:  len(snapshots),
+	}
+
+	if err := s.pgClient.SaveWorldSnapshot(ctx, marker, snapshots, pendingEvents(world.Events)); err != nil {
+		return fmt.Errorf("failed to save world at tick %d: %w", world.TickNumber, err)
+	}
+	if n := len(world.Events); n > 0 {
+		eng.AckEvents(world.Events[n-1].Seq)
+	}
+
+	s.logger.Debug("World snapshot saved",
+		zap.String("region", s.regionID),
+		zap.Uint64("tick", world.TickNumber),
+		zap.Int("entity_count", len(snapshots)),
+		zap.Int("event_count", len(world.Events)),
+	)
+
+	return nil
+}
+
+// pendingEvents converts engine events for the outbox writer.
+func pendingEvents(events []engine.OutboxEvent) []postgres.PendingEvent {
+	pending := make([]postgres.PendingEvent, len(events))
+	for i, e := range events {
+		pending[i] = postgres.PendingEvent{AggregateKey: e.AggregateKey, Event: e.Event}
+	}
+	return pending
+}
+
+// RestoreWorld loads the latest complete world snapshot into the engine. It
+// must be called before the engine is started.
+func (s *Store) RestoreWorld(ctx context.Context, eng *engine.SpatialEngine) (int, error) {
+	marker, snapshots, err := s.pgClient.GetLatestWorldSnapshot(ctx, s.regionID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load world snapshot: %w", err)
+	}
+
+	if marker == nil {
+		s.logger.Info("No world snapshot found, starting with an empty world")
+		return 0, nil
+	}
+
+	world := engine.WorldSnapshot{
+		TickNumber:   marker.TickNumber,
+		NextEntityID: marker.NextEntityID,
+		Entities:     make([]entity.Entity, 0, len(snapshots)),
+	}
+	for _, snap := range snapshots {
+		ent, err := fromSnapshot(snap)
+		if err != nil {
+			s.logger.Warn("Restoring entity without its attributes", zap.Uint32("entity_id", snap.ID), zap.Error(err))
+		}
+		world.Entities = append(world.Entities, ent)
+	}
+
+	return eng.RestoreWorld(world), nil
+}
+
+func toSnapshot(ent *entity.Entity, tickNumber uint64) (*postgres.EntitySnapshot, error) {
+	attributes := json.RawMessage("{}")
+	if len(ent.Attributes) > 0 {
+		data, err := json.Marshal(ent.Attributes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal attributes of entity %d: %w", ent.ID, err)
+		}
+		attributes = data
+	}
+
+	return &postgres.EntitySnapshot{
+		ID:         ent.ID,
+		Type:       ent.Type,
+		PositionX:  ent.Position.X,
+		PositionY:  ent.Position.Y,
+		VelocityX:  ent.Velocity.X,
+		VelocityY:  ent.Velocity.Y,
+		PositionZ:  ent.Position.Z,
+		VelocityZ:  ent.Velocity.Z,
+		Yaw:        ent.Yaw,
+		ClientID:   ent.ClientID,
+		Avatar:     ent.Avatar,
+		ResumeHash: ent.ResumeHash,
+		LastUpdate: ent.LastUpdate,
+		TickNumber: tickNumber,
+		Attributes: attributes,
+	}, nil
+}
+
+// fromSnapshot rebuilds an entity. If its attributes can't be decoded the
+// entity is returned without them, along with the error.
+func fromSnapshot(snap *postgres.EntitySnapshot) (entity.Entity, error) {
+	ent := entity.Entity{
+		ID:         snap.ID,
+		Type:       snap.Type,
+		Position:   entity.Vector3{X: snap.PositionX, Y: snap.PositionY, Z: snap.PositionZ},
+		Velocity:   entity.Vector3{X: snap.VelocityX, Y: snap.VelocityY, Z: snap.VelocityZ},
+		Yaw:        snap.Yaw,
+		ClientID:   snap.ClientID,
+		Avatar:     snap.Avatar,
+		ResumeHash: snap.ResumeHash,
+		LastUpdate: snap.LastUpdate,
+	}
+
+	if len(snap.Attributes) > 0 {
+		if err := json.Unmarshal(snap.Attributes, &ent.Attributes); err != nil {
+			ent.Attributes = nil
+			return ent, fmt.Errorf("failed to unmarshal attributes: %w", err)
+		}
+	}
+	return ent, nil
+}
@@ -0,0 +1,80 @@
+package snapshot
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/akarsh-2004/aether/internal/engine"
+	"go.uber.org/zap"
+)
+
+// Snapshotter is a tick handler that persists the world every interval ticks.
+// The copy is taken on the tick goroutine; the database write happens in the
+// background so a slow Postgres never stalls the simulation.
+type Snapshotter struct {
+	store     *Store
+	engine    *engine.SpatialEngine
+	logger    *zap.Logger
+	interval  uint64
+	retention time.Duration
+	inFlight  bool
+	mu        sync.Mutex
+	wg        sync.WaitGroup
+}
+
+func NewSnapshotter(store *Store, eng *engine.SpatialEngine, intervalTicks int, retention time.Duration, logger *zap.Logger) *Snapshotter {
+	return &Snapshotter{
+		store:     store,
+		engine:    eng,
+		logger:    logger,
+		interval:  uint64(intervalTicks),
+		retention: retention,
+	}
+}
+
+func (s *Snapshotter) OnTick(tickNumber uint64) {
+	if s.interval == 0 || tickNumber%s.interval != 0 {
+		return
+	}
+
+	s.mu.Lock()
+	if s.inFlight {
+		s.mu.Unlock()
+		s.logger.Warn("Skipping world snapshot, previous write still in progress", zap.Uint64("tick", tickNumber))
+		return
+	}
+	s.inFlight = true
+	s.mu.Unlock()
+
+	world := s.engine.SnapshotWorld()
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer func() {
+			s.mu.Lock()
+			s.inFlight = false
+			s.mu.Unlock()
+		}()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		if err := s.store.save(ctx, s.engine, world); err != nil {
+			s.logger.Error("Periodic world snapshot failed", zap.Error(err))
+			return
+		}
+
+		if s.retention > 0 {
+			if err := s.store.pgClient.CleanupOldSnapshots(ctx, s.retention); err != nil {
+				s.logger.Warn("Failed to clean up old snapshots", zap.Error(err))
+			}
+		}
+	}()
+}
+
+func (s *Snapshotter) OnShutdown() {
+	// Let an in-flight write finish so the final snapshot is not interleaved
+	s.wg.Wait()
+}
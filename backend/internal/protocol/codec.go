@@ -112,6 +112,112 @@ func (c *Codec) ValidateMessage(msg *proto.Message) error {
 			return fmt.Errorf("client_id is required in heartbeat")
 		}
 
+	case proto.MessageType_RECONNECT:
+		if msg.Reconnect == nil {
+			return fmt.Errorf("reconnect payload is required for RECONNECT type")
+		}
+
+	case proto.MessageType_QUEUE_REQUEST:
+		if msg.QueueRequest == nil {
+			return fmt.Errorf("queue_request payload is required for QUEUE_REQUEST type")
+		}
+		if msg.QueueRequest.Queue == "" {
+			return fmt.Errorf("queue is required in queue_request")
+		}
+
+	case proto.MessageType_LEAVE_QUEUE:
+		if msg.LeaveQueue == nil {
+			return fmt.Errorf("leave_queue payload is required for LEAVE_QUEUE type")
+		}
+
+	case proto.MessageType_LOBBY_STATUS:
+		if msg.LobbyStatus == nil {
+			return fmt.Errorf("lobby_status payload is required for LOBBY_STATUS type")
+		}
+
+	case proto.MessageType_CONTROL_REQUEST:
+		if msg.ControlRequest == nil {
+			return fmt.Errorf("control_request payload is required for CONTROL_REQUEST type")
+		}
+		if msg.ControlRequest.EntityId == 0 {
+			return fmt.Errorf("entity_id is required in control_request")
+		}
+
+	case proto.MessageType_CONTROL_UPDATE:
+		if msg.ControlUpdate == nil {
+			return fmt.Errorf("control_update payload is required for CONTROL_UPDATE type")
+		}
+
+	case proto.MessageType_SUBSCRIBE:
+		if msg.Subscribe == nil || msg.Subscribe.Channel == "" {
+			return fmt.Errorf("channel is required in subscribe")
+		}
+
+	case proto.MessageType_UNSUBSCRIBE:
+		if msg.Unsubscribe == nil || msg.Unsubscribe.Channel == "" {
+			return fmt.Errorf("channel is required in unsubscribe")
+		}
+
+	case proto.MessageType_PUBLISH:
+		if msg.Publish == nil || msg.Publish.Channel == "" {
+			return fmt.Errorf("channel is required in publish")
+		}
+
+	case proto.MessageType_CHANNEL_MESSAGE:
+		if msg.ChannelMessage == nil {
+			return fmt.Errorf("channel_message payload is required for CHANNEL_MESSAGE type")
+		}
+
+	case proto.MessageType_CHANNEL_STATUS:
+		if msg.ChannelStatus == nil {
+			return fmt.Errorf("channel_status payload is required for CHANNEL_STATUS type")
+		}
+
+	case proto.MessageType_CHAT_SEND:
+		if msg.ChatSend == nil {
+			return fmt.Errorf("chat_send payload is required for CHAT_SEND type")
+		}
+
+	case proto.MessageType_CHAT_MESSAGE:
+		if msg.ChatMessage == nil {
+			return fmt.Errorf("chat_message payload is required for CHAT_MESSAGE type")
+		}
+
+	case proto.MessageType_CHAT_MUTE:
+		if msg.ChatMute == nil || msg.ChatMute.TargetClientId == "" {
+			return fmt.Errorf("target_client_id is required in chat_mute")
+		}
+
+	case proto.MessageType_INPUT_COMMAND:
+		if msg.InputCommand == nil {
+			return fmt.Errorf("input_command payload is required for INPUT_COMMAND type")
+		}
+
+	case proto.MessageType_INPUT_ACK:
+		if msg.InputAck == nil || msg.InputAck.EntityId == 0 {
+			return fmt.Errorf("entity_id is required in input_ack")
+		}
+
+	case proto.MessageType_TIME_SYNC:
+		if msg.TimeSync == nil {
+			return fmt.Errorf("time_sync payload is required for TIME_SYNC type")
+		}
+
+	case proto.MessageType_FIRE:
+		if msg.Fire == nil || msg.Fire.ProjectileType == "" {
+			return fmt.Errorf("projectile_type is required in fire")
+		}
+
+	case proto.MessageType_SCRIPT_MESSAGE:
+		if msg.ScriptMessage == nil || msg.ScriptMessage.Name == "" {
+			return fmt.Errorf("name is required in script_message")
+		}
+
+	case proto.MessageType_FIRE_RESULT:
+		if msg.FireResult == nil {
+			return fmt.Errorf("fire_result payload is required for FIRE_RESULT type")
+		}
+
 	default:
 		return ErrUnknownType
 	}
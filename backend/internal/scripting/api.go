@@ -0,0 +1,204 @@
+package scripting
+
+import (
+	"math"
+
+	"github.com/akarsh-2004/aether/internal/engine"
+	"github.com/akarsh-2004/aether/internal/engine/entity"
+	lua "github.com/yuin/gopher-lua"
+	"go.uber.org/zap"
+)
+
+// installAPI registers the aether module. aether.on and aether.log work at
+// any time; everything else touches the world and is only available inside
+// hooks.
+func (r *Runtime) installAPI(v *vm) {
+	L := v.state
+
+	// world returns the engine API, raising a Lua error outside hooks
+	world := func(L *lua.LState) *engine.ScriptAPI {
+		if v.api == nil {
+			L.RaiseError("the world can only be changed inside hooks")
+		}
+		return v.api
+	}
+
+	logFn := func(L *lua.LState) int {
+		parts := make([]string, 0, L.GetTop())
+		for i := 1; i <= L.GetTop(); i++ {
+			parts = append(parts, L.Get(i).String())
+		}
+		r.logger.Info("Script log", zap.Strings("message", parts))
+		return 0
+	}
+
+	module := L.SetFuncs(L.NewTable(), map[string]lua.LGFunction{
+		// aether.on(hook, fn) registers fn to run on every hook event
+		"on": func(L *lua.LState) int {
+			hook := L.CheckString(1)
+			fn := L.CheckFunction(2)
+			if !hooks[hook] {
+				L.ArgError(1, "unknown hook "+hook)
+			}
+			v.handlers[hook] = append(v.handlers[hook], fn)
+			return 0
+		},
+
+		"log": logFn,
+
+		"tick": func(L *lua.LState) int {
+			L.Push(lua.LNumber(world(L).Tick()))
+			return 1
+		},
+
+		// aether.entity(id) returns the entity as a table, or nil
+		"entity": func(L *lua.LState) int {
+			ent, exists := world(L).Entity(checkID(L, 1))
+			if !exists {
+				L.Push(lua.LNil)
+				return 1
+			}
+			L.Push(entityTable(L, &ent))
+			return 1
+		},
+
+		// aether.query(x, y, z, radius) returns the IDs of nearby entities
+		"query": func(L *lua.LState) int {
+			ids := world(L).QueryRadius(checkVector(L, 1), float64(L.CheckNumber(4)))
+			tbl := L.CreateTable(len(ids), 0)
+			for i, id := range ids {
+				tbl.RawSetInt(i+1, lua.LNumber(id))
+			}
+			L.Push(tbl)
+			return 1
+		},
+
+		// aether.zones_at(x, y, z) returns the names of the zones there
+		"zones_at": func(L *lua.LState) int {
+			names := world(L).ZonesAt(checkVector(L, 1))
+			tbl := L.CreateTable(len(names), 0)
+			for i, name := range names {
+				tbl.RawSetInt(i+1, lua.LString(name))
+			}
+			L.Push(tbl)
+			return 1
+		},
+
+		"set_position": func(L *lua.LState) int {
+			L.Push(lua.LBool(world(L).SetPosition(checkID(L, 1), checkVector(L, 2))))
+			return 1
+		},
+
+		"set_velocity": func(L *lua.LState) int {
+			L.Push(lua.LBool(world(L).SetVelocity(checkID(L, 1), checkVector(L, 2))))
+			return 1
+		},
+
+		// aether.set_attribute(id, name, value) returns true, or false and
+		// the reason
+		"set_attribute": func(L *lua.LState) int {
+			id, name := checkID(L, 1), L.CheckString(2)
+			if err := world(L).SetAttribute(id, name, fromLua(L.CheckAny(3))); err != nil {
+				L.Push(lua.LFalse)
+				L.Push(lua.LString(err.Error()))
+				return 2
+			}
+			L.Push(lua.LTrue)
+			return 1
+		},
+
+		// aether.spawn(type, x, y, z) returns the new entity's ID, or nil
+		"spawn": func(L *lua.LState) int {
+			id := world(L).Spawn(L.CheckString(1), checkVector(L, 2))
+			if id == 0 {
+				L.Push(lua.LNil)
+				return 1
+			}
+			L.Push(lua.LNumber(id))
+			return 1
+		},
+
+		"despawn": func(L *lua.LState) int {
+			L.Push(lua.LBool(world(L).Despawn(checkID(L, 1), L.OptString(2, "script"))))
+			return 1
+		},
+
+		// aether.send(client_id, name, payload) sends a SCRIPT_MESSAGE
+		"send": func(L *lua.LState) int {
+			world(L).Send(L.CheckString(1), L.CheckString(2), L.OptString(3, ""))
+			return 0
+		},
+	})
+
+	L.SetGlobal("aether", module)
+	L.SetGlobal("print", L.NewFunction(logFn))
+}
+
+func checkID(L *lua.LState, n int) uint32 {
+	id := float64(L.CheckNumber(n))
+	if id < 0 || id > math.MaxUint32 || id != math.Trunc(id) {
+		L.ArgError(n, "invalid entity id")
+	}
+	return uint32(id)
+}
+
+// checkVector reads x, y and z starting at argument n.
+func checkVector(L *lua.LState, n int) entity.Vector3 {
+	return entity.Vector3{
+		X: float64(L.CheckNumber(n)),
+		Y: float64(L.CheckNumber(n + 1)),
+		Z: float64(L.CheckNumber(n + 2)),
+	}
+}
+
+func entityTable(L *lua.LState, ent *entity.Entity) *lua.LTable {
+	tbl := L.NewTable()
+	tbl.RawSetString("id", lua.LNumber(ent.ID))
+	tbl.RawSetString("type", lua.LString(ent.Type))
+	tbl.RawSetString("client_id", lua.LString(ent.ClientID))
+	tbl.RawSetString("x", lua.LNumber(ent.Position.X))
+	tbl.RawSetString("y", lua.LNumber(ent.Position.Y))
+	tbl.RawSetString("z", lua.LNumber(ent.Position.Z))
+	tbl.RawSetString("vx", lua.LNumber(ent.Velocity.X))
+	tbl.RawSetString("vy", lua.LNumber(ent.Velocity.Y))
+	tbl.RawSetString("vz", lua.LNumber(ent.Velocity.Z))
+	tbl.RawSetString("yaw", lua.LNumber(ent.Yaw))
+
+	attrs := L.NewTable()
+	for name, value := range ent.Attributes {
+		attrs.RawSetString(name, toLua(value))
+	}
+	tbl.RawSetString("attributes", attrs)
+	return tbl
+}
+
+func toLua(value entity.Value) lua.LValue {
+	switch value.Kind {
+	case entity.KindInt:
+		return lua.LNumber(value.Int)
+	case entity.KindFloat:
+		return lua.LNumber(value.Float)
+	case entity.KindBool:
+		return lua.LBool(value.Bool)
+	default:
+		return lua.LString(value.String)
+	}
+}
+
+// fromLua converts a Lua value for engine.ToValue. Lua has one number type,
+// so whole numbers become ints, which float attributes also accept.
+func fromLua(value lua.LValue) interface{} {
+	switch x := value.(type) {
+	case lua.LNumber:
+		if f := float64(x); f == math.Trunc(f) && math.Abs(f) < 1<<53 {
+			return int64(f)
+		}
+		return float64(x)
+	case lua.LBool:
+		return bool(x)
+	case lua.LString:
+		return string(x)
+	default:
+		return value // rejected by the schema check
+	}
+}
@@ -0,0 +1,368 @@
+// Package scripting runs game logic written in Lua inside the engine's hooks.
+// Scripts are sandboxed: they get the base, table, string and math libraries
+// and the aether module, but no file, OS or module loading access.
+package scripting
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/akarsh-2004/aether/internal/config"
+	"github.com/akarsh-2004/aether/internal/engine"
+	lua "github.com/yuin/gopher-lua"
+	"go.uber.org/zap"
+)
+
+// Hooks scripts can register with aether.on.
+const (
+	HookSpawn     = "spawn"
+	HookTick      = "tick"
+	HookEnter     = "enter"
+	HookExit      = "exit"
+	HookMessage   = "message"
+	HookZoneEnter = "zone_enter"
+	HookZoneExit  = "zone_exit"
+)
+
+var hooks = map[string]bool{
+	HookSpawn:     true,
+	HookTick:      true,
+	HookEnter:     true,
+	HookExit:      true,
+	HookMessage:   true,
+	HookZoneEnter: true,
+	HookZoneExit:  true,
+}
+
+// Runtime is one world's scripts. It implements engine.ScriptHost. Lua
+// states are not safe for concurrent use, so every world gets its own.
+type Runtime struct {
+	config  config.ScriptingConfig
+	logger  *zap.Logger
+	mu      sync.Mutex
+	vm      *vm
+	version string // fingerprint of the loaded scripts directory
+	errors  uint64
+	reloads uint64
+
+	// A hook that changes the world can raise another hook, e.g.
+	// aether.spawn raising spawn. That one is queued and run after the
+	// outer hook returns, since mu is held while a hook runs.
+	hookMu  sync.Mutex
+	running bool
+	queued  []queuedHook
+}
+
+type queuedHook struct {
+	api  *engine.ScriptAPI
+	hook string
+	args []lua.LValue
+}
+
+// vm is a loaded set of scripts.
+type vm struct {
+	state    *lua.LState
+	handlers map[string][]*lua.LFunction
+	api      *engine.ScriptAPI // set only while a hook runs
+}
+
+// NewRuntime loads the scripts in cfg.Dir. It fails if any of them does not
+// load, so a broken script is noticed at startup rather than in play.
+func NewRuntime(cfg config.ScriptingConfig, logger *zap.Logger) (*Runtime, error) {
+	r := &Runtime{
+		config: cfg,
+		logger: logger,
+	}
+
+	version, err := r.fingerprint()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scripts directory: %w", err)
+	}
+	loaded, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+
+	r.vm = loaded
+	r.version = version
+	return r, nil
+}
+
+// Start reloads the scripts whenever the directory changes, until ctx is
+// done, and then closes the runtime.
+func (r *Runtime) Start(ctx context.Context) {
+	go func() {
+		defer r.Close()
+
+		if r.config.ReloadIntervalMs <= 0 {
+			<-ctx.Done()
+			return
+		}
+
+		ticker := time.NewTicker(time.Duration(r.config.ReloadIntervalMs) * time.Millisecond)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				r.reloadIfChanged()
+			}
+		}
+	}()
+}
+
+// Close releases the Lua state. Hooks do nothing afterwards.
+func (r *Runtime) Close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.vm != nil {
+		r.vm.state.Close()
+		r.vm = nil
+	}
+}
+
+func (r *Runtime) reloadIfChanged() {
+	version, err := r.fingerprint()
+	if err != nil {
+		r.logger.Warn("Failed to read scripts directory", zap.String("dir", r.config.Dir), zap.Error(err))
+		return
+	}
+
+	r.mu.Lock()
+	unchanged := version == r.version
+	r.mu.Unlock()
+	if unchanged {
+		return
+	}
+
+	// Load outside the lock so the engine keeps ticking on the old scripts
+	loaded, err := r.load()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.version = version
+	if err != nil {
+		r.logger.Error("Script reload failed, keeping previous scripts", zap.Error(err))
+		return
+	}
+	if r.vm == nil {
+		loaded.state.Close() // closed while loading
+		return
+	}
+
+	r.vm.state.Close()
+	r.vm = loaded
+	r.reloads++
+	r.logger.Info("Scripts reloaded", zap.String("dir", r.config.Dir))
+}
+
+// scriptFiles returns the *.lua files in the scripts directory in name order.
+func (r *Runtime) scriptFiles() ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(r.config.Dir, "*.lua"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// fingerprint identifies the current contents of the scripts directory by
+// file names, sizes and modification times.
+func (r *Runtime) fingerprint() (string, error) {
+	files, err := r.scriptFiles()
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&b, "%s:%d:%d;", file, info.Size(), info.ModTime().UnixNano())
+	}
+	return b.String(), nil
+}
+
+func (r *Runtime) load() (*vm, error) {
+	files, err := r.scriptFiles()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scripts: %w", err)
+	}
+
+	loaded := &vm{
+		state:    newSandbox(),
+		handlers: make(map[string][]*lua.LFunction),
+	}
+	r.installAPI(loaded)
+
+	for _, file := range files {
+		if err := r.run(loaded, func() error { return runFile(loaded.state, file) }); err != nil {
+			loaded.state.Close()
+			return nil, fmt.Errorf("failed to load script %s: %w", filepath.Base(file), err)
+		}
+	}
+
+	r.logger.Info("Scripts loaded", zap.String("dir", r.config.Dir), zap.Int("files", len(files)))
+	return loaded, nil
+}
+
+func runFile(L *lua.LState, file string) error {
+	fn, err := L.LoadFile(file)
+	if err != nil {
+		return err
+	}
+	L.Push(fn)
+	return L.PCall(0, lua.MultRet, nil)
+}
+
+// run calls fn with the hook timeout in force.
+func (r *Runtime) run(v *vm, fn func() error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(r.config.HookTimeoutMs)*time.Millisecond)
+	defer cancel()
+
+	v.state.SetContext(ctx)
+	defer v.state.RemoveContext()
+
+	return fn()
+}
+
+// newSandbox returns a Lua state with only the safe standard libraries.
+func newSandbox() *lua.LState {
+	L := lua.NewState(lua.Options{SkipOpenLibs: true})
+	for _, lib := range []struct {
+		name string
+		open lua.LGFunction
+	}{
+		{lua.BaseLibName, lua.OpenBase},
+		{lua.TabLibName, lua.OpenTable},
+		{lua.StringLibName, lua.OpenString},
+		{lua.MathLibName, lua.OpenMath},
+	} {
+		L.Push(L.NewFunction(lib.open))
+		L.Push(lua.LString(lib.name))
+		L.Call(1, 0)
+	}
+
+	// The base library can still reach the file system
+	for _, name := range []string{"dofile", "loadfile", "load", "loadstring", "require", "module"} {
+		L.SetGlobal(name, lua.LNil)
+	}
+	return L
+}
+
+// call runs every handler registered for hook, followed by any hooks raised
+// while they ran.
+func (r *Runtime) call(api *engine.ScriptAPI, hook string, args ...lua.LValue) {
+	r.hookMu.Lock()
+	if r.running {
+		r.queued = append(r.queued, queuedHook{api: api, hook: hook, args: args})
+		r.hookMu.Unlock()
+		return
+	}
+	r.running = true
+	r.hookMu.Unlock()
+
+	next := queuedHook{api: api, hook: hook, args: args}
+	for {
+		r.runHook(next.api, next.hook, next.args...)
+
+		r.hookMu.Lock()
+		if len(r.queued) == 0 {
+			r.running = false
+			r.hookMu.Unlock()
+			return
+		}
+		next = r.queued[0]
+		r.queued = r.queued[1:]
+		r.hookMu.Unlock()
+	}
+}
+
+// runHook runs the handlers for one hook. A failing handler is logged and
+// does not stop the others, unless the hook ran out of time.
+func (r *Runtime) runHook(api *engine.ScriptAPI, hook string, args ...lua.LValue) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.vm == nil || len(r.vm.handlers[hook]) == 0 {
+		return
+	}
+
+	v := r.vm
+	v.api = api
+	defer func() { v.api = nil }()
+
+	r.run(v, func() error {
+		for _, fn := range v.handlers[hook] {
+			err := v.state.CallByParam(lua.P{Fn: fn, NRet: 0, Protect: true}, args...)
+			if err == nil {
+				continue
+			}
+
+			r.errors++
+			r.logger.Warn("Script hook failed", zap.String("hook", hook), zap.Error(err))
+			if ctx := v.state.Context(); ctx != nil && ctx.Err() != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *Runtime) OnSpawn(api *engine.ScriptAPI, entityID uint32) {
+	r.call(api, HookSpawn, lua.LNumber(entityID))
+}
+
+func (r *Runtime) OnTick(api *engine.ScriptAPI, tick uint64) {
+	r.call(api, HookTick, lua.LNumber(tick))
+}
+
+func (r *Runtime) OnEnter(api *engine.ScriptAPI, entityID, otherID uint32) {
+	r.call(api, HookEnter, lua.LNumber(entityID), lua.LNumber(otherID))
+}
+
+func (r *Runtime) OnExit(api *engine.ScriptAPI, entityID, otherID uint32) {
+	r.call(api, HookExit, lua.LNumber(entityID), lua.LNumber(otherID))
+}
+
+func (r *Runtime) OnMessage(api *engine.ScriptAPI, clientID string, entityID uint32, name, payload string) {
+	r.call(api, HookMessage, lua.LString(clientID), lua.LNumber(entityID), lua.LString(name), lua.LString(payload))
+}
+
+func (r *Runtime) OnZoneEnter(api *engine.ScriptAPI, entityID uint32, zone string) {
+	r.call(api, HookZoneEnter, lua.LNumber(entityID), lua.LString(zone))
+}
+
+func (r *Runtime) OnZoneExit(api *engine.ScriptAPI, entityID uint32, zone string) {
+	r.call(api, HookZoneExit, lua.LNumber(entityID), lua.LString(zone))
+}
+
+func (r *Runtime) GetStats() map[string]interface{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	handlers := 0
+	if r.vm != nil {
+		for _, fns := range r.vm.handlers {
+			handlers += len(fns)
+		}
+	}
+
+	return map[string]interface{}{
+		"handlers": handlers,
+		"errors":   r.errors,
+		"reloads":  r.reloads,
+	}
+}
@@ -0,0 +1,64 @@
+package scripting
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/akarsh-2004/aether/internal/config"
+	"github.com/akarsh-2004/aether/internal/engine"
+	"go.uber.org/zap"
+)
+
+func TestRuntime_SpawnFromHook(t *testing.T) {
+	dir := t.TempDir()
+	script := `
+aether.on("tick", function(tick)
+  if tick == 1 then aether.spawn("npc", 1, 1, 0) end
+end)
+aether.on("spawn", function(id)
+  aether.set_velocity(id, 1, 0, 0)
+end)
+`
+	if err := os.WriteFile(filepath.Join(dir, "spawn.lua"), []byte(script), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.Default()
+	cfg.Engine.Scripting = config.ScriptingConfig{Enabled: true, Dir: dir, HookTimeoutMs: 1000}
+
+	runtime, err := NewRuntime(cfg.Engine.Scripting, zap.NewNop())
+	if err != nil {
+		t.Fatalf("load scripts: %v", err)
+	}
+
+	se := engine.NewSpatialEngine(cfg.Engine, zap.NewNop())
+	se.SetScriptHost(runtime)
+
+	// The spawn hook is raised from inside the tick hook; it must not deadlock
+	done := make(chan struct{})
+	go func() {
+		se.OnTick(1)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("tick hook spawning an entity deadlocked")
+	}
+	defer runtime.Close() // would block behind a stuck hook
+
+	entities := se.LocalEntities()
+	if len(entities) != 1 {
+		t.Fatalf("expected 1 entity, got %d", len(entities))
+	}
+
+	// The spawn hook was queued and ran once the tick hook returned
+	if errors := runtime.GetStats()["errors"].(uint64); errors != 0 {
+		t.Fatalf("expected no script errors, got %d", errors)
+	}
+	if entities[0].Velocity.X != 1 {
+		t.Errorf("expected the spawn hook to set the velocity, got %+v", entities[0].Velocity)
+	}
+}
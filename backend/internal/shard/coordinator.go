@@ -0,0 +1,192 @@
+package shard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/akarsh-2004/aether/internal/engine/entity"
+)
+
+var ErrRegionOwned = errors.New("region is owned by another instance")
+
+// EntityState is the wire form of an entity crossing process boundaries.
+type EntityState struct {
+	ID           uint32  `json:"id"`
+	Type         string  `json:"type"`
+	X            float64 `json:"x"`
+	Y            float64 `json:"y"`
+	VelocityX    float64 `json:"velocity_x"`
+	VelocityY    float64 `json:"velocity_y"`
+	Z            float64 `json:"z,omitempty"`
+	VelocityZ    float64 `json:"velocity_z,omitempty"`
+	Yaw          float64 `json:"yaw,omitempty"`
+	ClientID     string  `json:"client_id,omitempty"`
+	Avatar       bool    `json:"avatar,omitempty"`
+	ResumeHash   []byte  `json:"resume_hash,omitempty"`
+	LastSequence uint64  `json:"last_sequence"`
+
+	Attributes map[string]entity.Value `json:"attributes,omitempty"`
+}
+
+// Handoff transfers ownership of an entity to the region it moved into.
+type Handoff struct {
+	FromRegion string      `json:"from_region"`
+	Entity     EntityState `json:"entity"`
+}
+
+// GhostBatch is the full set of a region's entities inside a neighbour's
+// border band. Receivers drop ghosts missing from the latest batch.
+type GhostBatch struct {
+	FromRegion string        `json:"from_region"`
+	Tick       uint64        `json:"tick"`
+	Ghosts     []EntityState `json:"ghosts"`
+}
+
+// Coordinator connects region nodes: it tracks which instance owns each
+// region and carries handoffs and ghost updates between them. Handoffs must
+// be delivered reliably; ghost batches may be dropped.
+type Coordinator interface {
+	// ClaimRegion takes ownership of a region and keeps it until ctx is done
+	// or ReleaseRegion is called. advertiseAddr is where clients reconnect.
+	// The returned channel is closed if the claim is lost before then.
+	ClaimRegion(ctx context.Context, regionID, advertiseAddr string) (<-chan struct{}, error)
+	ReleaseRegion(ctx context.Context, regionID string) error
+	// RegionOwner returns the owner's advertise address, or "" if unowned.
+	RegionOwner(ctx context.Context, regionID string) (string, error)
+
+	SendHandoff(ctx context.Context, toRegion string, handoff *Handoff) error
+	Handoffs(ctx context.Context, regionID string) <-chan *Handoff
+
+	PublishGhosts(ctx context.Context, toRegion string, batch *GhostBatch) error
+	Ghosts(ctx context.Context, regionID string) <-chan *GhostBatch
+}
+
+func toState(ent entity.Entity) EntityState {
+	return EntityState{
+		ID:           ent.ID,
+		Type:         ent.Type,
+		X:            ent.Position.X,
+		Y:            ent.Position.Y,
+		VelocityX:    ent.Velocity.X,
+		VelocityY:    ent.Velocity.Y,
+		Z:            ent.Position.Z,
+		VelocityZ:    ent.Velocity.Z,
+		Yaw:          ent.Yaw,
+		ClientID:     ent.ClientID,
+		Avatar:       ent.Avatar,
+		ResumeHash:   ent.ResumeHash,
+		LastSequence: ent.LastSequence,
+		Attributes:   ent.Attributes,
+	}
+}
+
+func (s EntityState) toEntity() entity.Entity {
+	return entity.Entity{
+		ID:           s.ID,
+		Type:         s.Type,
+		Position:     entity.Vector3{X: s.X, Y: s.Y, Z: s.Z},
+		Velocity:     entity.Vector3{X: s.VelocityX, Y: s.VelocityY, Z: s.VelocityZ},
+		Yaw:          s.Yaw,
+		ClientID:     s.ClientID,
+		Avatar:       s.Avatar,
+		ResumeHash:   s.ResumeHash,
+		LastSequence: s.LastSequence,
+		Attributes:   s.Attributes,
+	}
+}
+
+// LocalCoordinator connects nodes running in the same process, for tests and
+// single-machine multi-engine setups.
+type LocalCoordinator struct {
+	mu       sync.Mutex
+	owners   map[string]string
+	handoffs map[string]chan *Handoff
+	ghosts   map[string]chan *GhostBatch
+}
+
+func NewLocalCoordinator() *LocalCoordinator {
+	return &LocalCoordinator{
+		owners:   make(map[string]string),
+		handoffs: make(map[string]chan *Handoff),
+		ghosts:   make(map[string]chan *GhostBatch),
+	}
+}
+
+// ClaimRegion claims a region in memory. Local claims are never lost.
+func (lc *LocalCoordinator) ClaimRegion(ctx context.Context, regionID, advertiseAddr string) (<-chan struct{}, error) {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+
+	if owner, owned := lc.owners[regionID]; owned && owner != advertiseAddr {
+		return nil, fmt.Errorf("%w: %s", ErrRegionOwned, regionID)
+	}
+	lc.owners[regionID] = advertiseAddr
+	return make(chan struct{}), nil
+}
+
+func (lc *LocalCoordinator) ReleaseRegion(ctx context.Context, regionID string) error {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+
+	delete(lc.owners, regionID)
+	return nil
+}
+
+func (lc *LocalCoordinator) RegionOwner(ctx context.Context, regionID string) (string, error) {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+
+	return lc.owners[regionID], nil
+}
+
+func (lc *LocalCoordinator) SendHandoff(ctx context.Context, toRegion string, handoff *Handoff) error {
+	select {
+	case lc.handoffChan(toRegion) <- handoff:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (lc *LocalCoordinator) Handoffs(ctx context.Context, regionID string) <-chan *Handoff {
+	return lc.handoffChan(regionID)
+}
+
+func (lc *LocalCoordinator) PublishGhosts(ctx context.Context, toRegion string, batch *GhostBatch) error {
+	select {
+	case lc.ghostChan(toRegion) <- batch:
+	default:
+		// Ghosts are best effort; the next batch supersedes this one
+	}
+	return nil
+}
+
+func (lc *LocalCoordinator) Ghosts(ctx context.Context, regionID string) <-chan *GhostBatch {
+	return lc.ghostChan(regionID)
+}
+
+func (lc *LocalCoordinator) handoffChan(regionID string) chan *Handoff {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+
+	ch, exists := lc.handoffs[regionID]
+	if !exists {
+		ch = make(chan *Handoff, 256)
+		lc.handoffs[regionID] = ch
+	}
+	return ch
+}
+
+func (lc *LocalCoordinator) ghostChan(regionID string) chan *GhostBatch {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+
+	ch, exists := lc.ghosts[regionID]
+	if !exists {
+		ch = make(chan *GhostBatch, 64)
+		lc.ghosts[regionID] = ch
+	}
+	return ch
+}
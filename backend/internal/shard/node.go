@@ -0,0 +1,394 @@
+package shard
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/akarsh-2004/aether/internal/config"
+	"github.com/akarsh-2004/aether/internal/engine"
+	"go.uber.org/zap"
+)
+
+// HandoffNotifier tells a connected client that its entity moved to another
+// region and it should reconnect to reconnectTo.
+type HandoffNotifier func(clientID string, entityID uint32, reconnectTo string)
+
+// retryUnreachableAfter is how long a node stops handing entities to a
+// region after a failed handoff.
+const retryUnreachableAfter = time.Second
+
+// Node runs one region: it owns an engine whose bounds cover the region plus
+// a border margin, hands entities that leave the region to their new owner,
+// and keeps ghosts of neighbouring entities near its borders.
+type Node struct {
+	region      Region
+	grid        *Grid
+	engine      *engine.SpatialEngine
+	coordinator Coordinator
+	config      config.ShardingConfig
+	margin      float64
+	ghostTTL    time.Duration
+	logger      *zap.Logger
+	notify      HandoffNotifier
+
+	outbound    chan func(ctx context.Context)
+	ghostsFrom  map[string]*neighborGhosts // guarded by ghostMu
+	ghostMu     sync.Mutex
+	unreachable map[string]time.Time // guarded by mu
+	mu          sync.Mutex
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	lost   chan struct{} // closed once the region is lost to another instance
+	wg     sync.WaitGroup
+}
+
+type neighborGhosts struct {
+	ids      map[uint32]struct{}
+	received time.Time
+}
+
+// NewNode creates the engine for cfg.Region and the node that connects it to
+// its neighbours.
+func NewNode(cfg config.ShardingConfig, engineCfg config.EngineConfig, coordinator Coordinator, logger *zap.Logger) (*Node, error) {
+	grid := NewGrid(engineCfg.WorldBounds, cfg.Columns, cfg.Rows)
+	region, ok := grid.Region(cfg.Region)
+	if !ok {
+		return nil, fmt.Errorf("region %q is not in the %dx%d grid", cfg.Region, cfg.Columns, cfg.Rows)
+	}
+
+	margin := cfg.GhostMargin
+	if margin == 0 {
+		margin = engineCfg.AOIRadius
+	}
+
+	regionCfg := engineCfg
+	regionCfg.WorldBounds = grid.EngineBounds(region, margin)
+
+	logger = logger.With(zap.String("region", region.ID))
+
+	return &Node{
+		region:      region,
+		grid:        grid,
+		engine:      engine.NewSpatialEngine(regionCfg, logger),
+		coordinator: coordinator,
+		config:      cfg,
+		margin:      margin,
+		ghostTTL:    3*time.Duration(cfg.GhostInterval*engineCfg.TickRateMs)*time.Millisecond + time.Second,
+		logger:      logger,
+		outbound:    make(chan func(ctx context.Context), 1024),
+		ghostsFrom:  make(map[string]*neighborGhosts),
+		unreachable: make(map[string]time.Time),
+		lost:        make(chan struct{}),
+	}, nil
+}
+
+func (n *Node) Region() Region {
+	return n.region
+}
+
+func (n *Node) Engine() *engine.SpatialEngine {
+	return n.engine
+}
+
+// Context is cancelled when the node stops or loses its region. The engine
+// must be started with it, so it stops simulating a region another instance
+// has taken over. It is only valid after Start.
+func (n *Node) Context() context.Context {
+	return n.ctx
+}
+
+// Lost is closed when the region's claim is lost. By then the node's context
+// is cancelled; the caller should shut down without saving the world, since
+// the region's state now belongs to its new owner.
+func (n *Node) Lost() <-chan struct{} {
+	return n.lost
+}
+
+// SetHandoffNotifier installs the callback that redirects clients after
+// their entity is handed off. It should be called before Start.
+func (n *Node) SetHandoffNotifier(notify HandoffNotifier) {
+	n.notify = notify
+}
+
+// Start claims the region and begins exchanging handoffs and ghosts. The
+// engine itself is started by the caller, with Context.
+func (n *Node) Start(ctx context.Context) error {
+	claimLost, err := n.coordinator.ClaimRegion(ctx, n.region.ID, n.config.AdvertiseAddr)
+	if err != nil {
+		return fmt.Errorf("failed to claim region %s: %w", n.region.ID, err)
+	}
+
+	// Keep entity IDs unique across regions
+	n.engine.SetNextEntityID(n.region.EntityIDBase())
+	n.engine.AddTickHandler(n)
+
+	n.ctx, n.cancel = context.WithCancel(ctx)
+	ctx = n.ctx
+
+	n.wg.Add(4)
+	go n.watchClaim(ctx, claimLost)
+	go n.outboundLoop(ctx)
+	go n.handoffLoop(ctx)
+	go n.ghostLoop(ctx)
+
+	n.logger.Info("Region node started",
+		zap.Float64("min_x", n.region.Bounds.MinX),
+		zap.Float64("min_y", n.region.Bounds.MinY),
+		zap.Float64("max_x", n.region.Bounds.MaxX),
+		zap.Float64("max_y", n.region.Bounds.MaxY),
+		zap.Int("neighbors", len(n.grid.Neighbors(n.region.ID))),
+	)
+
+	return nil
+}
+
+// Stop stops the node's loops and releases the region.
+func (n *Node) Stop(ctx context.Context) {
+	if n.cancel != nil {
+		n.cancel()
+	}
+	n.wg.Wait()
+
+	if err := n.coordinator.ReleaseRegion(ctx, n.region.ID); err != nil {
+		n.logger.Warn("Failed to release region", zap.Error(err))
+	}
+}
+
+// OnTick runs after the engine's own tick processing.
+func (n *Node) OnTick(tickNumber uint64) {
+	entities := n.engine.LocalEntities()
+
+	for _, ent := range entities {
+		if n.region.Contains(ent.Position.X, ent.Position.Y) {
+			continue
+		}
+
+		target, ok := n.grid.RegionAt(ent.Position.X, ent.Position.Y)
+		if !ok || target.ID == n.region.ID || !n.reachable(target.ID) {
+			continue
+		}
+
+		n.handOff(ent.ID, target)
+	}
+
+	if tickNumber%uint64(n.config.GhostInterval) == 0 {
+		n.publishGhosts(tickNumber)
+	}
+}
+
+func (n *Node) OnShutdown() {}
+
+func (n *Node) handOff(entityID uint32, target Region) {
+	ent, ok := n.engine.ExtractEntity(entityID)
+	if !ok {
+		return
+	}
+
+	queued := n.enqueue(func(ctx context.Context) {
+		owner, err := n.coordinator.RegionOwner(ctx, target.ID)
+		if err == nil && owner == "" {
+			err = fmt.Errorf("region %s has no owner", target.ID)
+		}
+		if err == nil {
+			err = n.coordinator.SendHandoff(ctx, target.ID, &Handoff{FromRegion: n.region.ID, Entity: toState(ent)})
+		}
+
+		if err != nil {
+			// Keep simulating the entity in our border band until the
+			// neighbour is reachable again
+			n.logger.Warn("Entity handoff failed, keeping entity",
+				zap.Uint32("entity_id", ent.ID),
+				zap.String("target_region", target.ID),
+				zap.Error(err),
+			)
+			n.markUnreachable(target.ID)
+			n.engine.AdoptEntity(ent, false)
+			return
+		}
+
+		n.logger.Info("Entity handed off",
+			zap.Uint32("entity_id", ent.ID),
+			zap.String("client_id", ent.ClientID),
+			zap.String("target_region", target.ID),
+		)
+
+		if ent.ClientID != "" && n.notify != nil {
+			n.notify(ent.ClientID, ent.ID, owner)
+		}
+	})
+
+	if !queued {
+		n.engine.AdoptEntity(ent, false)
+	}
+}
+
+func (n *Node) publishGhosts(tickNumber uint64) {
+	entities := n.engine.LocalEntities()
+
+	for _, neighbor := range n.grid.Neighbors(n.region.ID) {
+		batch := &GhostBatch{FromRegion: n.region.ID, Tick: tickNumber}
+		for _, ent := range entities {
+			if Near(neighbor, ent.Position.X, ent.Position.Y, n.margin) {
+				batch.Ghosts = append(batch.Ghosts, toState(ent))
+			}
+		}
+
+		// Empty batches are sent too so the neighbour drops stale ghosts
+		toRegion := neighbor.ID
+		n.enqueue(func(ctx context.Context) {
+			if err := n.coordinator.PublishGhosts(ctx, toRegion, batch); err != nil {
+				n.logger.Debug("Failed to publish ghosts", zap.String("target_region", toRegion), zap.Error(err))
+			}
+		})
+	}
+}
+
+func (n *Node) applyGhosts(batch *GhostBatch) {
+	n.ghostMu.Lock()
+	defer n.ghostMu.Unlock()
+
+	previous := n.ghostsFrom[batch.FromRegion]
+	current := &neighborGhosts{ids: make(map[uint32]struct{}, len(batch.Ghosts)), received: time.Now()}
+
+	for _, state := range batch.Ghosts {
+		n.engine.UpsertGhost(state.toEntity())
+		current.ids[state.ID] = struct{}{}
+	}
+
+	if previous != nil {
+		for id := range previous.ids {
+			if _, still := current.ids[id]; !still {
+				n.engine.RemoveGhost(id)
+			}
+		}
+	}
+
+	n.ghostsFrom[batch.FromRegion] = current
+}
+
+// expireGhosts drops ghosts of neighbours that stopped sending updates.
+func (n *Node) expireGhosts() {
+	n.ghostMu.Lock()
+	defer n.ghostMu.Unlock()
+
+	cutoff := time.Now().Add(-n.ghostTTL)
+	for regionID, ghosts := range n.ghostsFrom {
+		if ghosts.received.After(cutoff) {
+			continue
+		}
+		for id := range ghosts.ids {
+			n.engine.RemoveGhost(id)
+		}
+		delete(n.ghostsFrom, regionID)
+		n.logger.Warn("Neighbour stopped sending ghosts", zap.String("neighbor_region", regionID))
+	}
+}
+
+// watchClaim stops the node, and with it the engine, as soon as the region
+// is lost, so two instances never simulate it at once.
+func (n *Node) watchClaim(ctx context.Context, claimLost <-chan struct{}) {
+	defer n.wg.Done()
+
+	select {
+	case <-ctx.Done():
+	case <-claimLost:
+		n.logger.Error("Region claim lost, stopping region engine")
+		close(n.lost)
+		n.cancel()
+	}
+}
+
+func (n *Node) handoffLoop(ctx context.Context) {
+	defer n.wg.Done()
+
+	handoffs := n.coordinator.Handoffs(ctx, n.region.ID)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case handoff, ok := <-handoffs:
+			if !ok {
+				return
+			}
+			if !n.engine.AdoptEntity(handoff.Entity.toEntity(), true) {
+				n.logger.Error("Failed to adopt handed off entity",
+					zap.Uint32("entity_id", handoff.Entity.ID),
+					zap.String("from_region", handoff.FromRegion),
+				)
+			}
+		}
+	}
+}
+
+func (n *Node) ghostLoop(ctx context.Context) {
+	defer n.wg.Done()
+
+	ticker := time.NewTicker(n.ghostTTL)
+	defer ticker.Stop()
+
+	batches := n.coordinator.Ghosts(ctx, n.region.ID)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case batch, ok := <-batches:
+			if !ok {
+				return
+			}
+			n.applyGhosts(batch)
+		case <-ticker.C:
+			n.expireGhosts()
+		}
+	}
+}
+
+// outboundLoop performs coordinator calls off the tick goroutine.
+func (n *Node) outboundLoop(ctx context.Context) {
+	defer n.wg.Done()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case send := <-n.outbound:
+			sendCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+			send(sendCtx)
+			cancel()
+		}
+	}
+}
+
+func (n *Node) enqueue(send func(ctx context.Context)) bool {
+	select {
+	case n.outbound <- send:
+		return true
+	default:
+		n.logger.Warn("Region outbound queue full, dropping update")
+		return false
+	}
+}
+
+func (n *Node) reachable(regionID string) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	until, marked := n.unreachable[regionID]
+	if !marked {
+		return true
+	}
+	if time.Now().After(until) {
+		delete(n.unreachable, regionID)
+		return true
+	}
+	return false
+}
+
+func (n *Node) markUnreachable(regionID string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.unreachable[regionID] = time.Now().Add(retryUnreachableAfter)
+}
@@ -0,0 +1,60 @@
+package shard
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/akarsh-2004/aether/internal/config"
+	"go.uber.org/zap"
+)
+
+// losableCoordinator hands out claims that the test can take away.
+type losableCoordinator struct {
+	*LocalCoordinator
+	lost chan struct{}
+}
+
+func (c *losableCoordinator) ClaimRegion(ctx context.Context, regionID, advertiseAddr string) (<-chan struct{}, error) {
+	if _, err := c.LocalCoordinator.ClaimRegion(ctx, regionID, advertiseAddr); err != nil {
+		return nil, err
+	}
+	return c.lost, nil
+}
+
+func TestNode_StopsWhenClaimLost(t *testing.T) {
+	cfg := config.Default()
+	cfg.Sharding.Enabled = true
+	cfg.Sharding.Region = "r0-0"
+
+	coordinator := &losableCoordinator{LocalCoordinator: NewLocalCoordinator(), lost: make(chan struct{})}
+	node, err := NewNode(cfg.Sharding, cfg.Engine, coordinator, zap.NewNop())
+	if err != nil {
+		t.Fatalf("create node: %v", err)
+	}
+	if err := node.Start(context.Background()); err != nil {
+		t.Fatalf("start node: %v", err)
+	}
+
+	engineStopped := make(chan error, 1)
+	go func() { engineStopped <- node.Engine().Start(node.Context()) }()
+
+	close(coordinator.lost)
+
+	select {
+	case <-node.Lost():
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected Lost to be closed after the claim was lost")
+	}
+
+	select {
+	case err := <-engineStopped:
+		if err != context.Canceled {
+			t.Errorf("expected engine to stop with context.Canceled, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected engine to stop after the claim was lost")
+	}
+
+	node.Stop(context.Background())
+}
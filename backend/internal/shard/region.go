@@ -0,0 +1,155 @@
+package shard
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/akarsh-2004/aether/internal/config"
+)
+
+// Region is one cell of the world grid, owned by exactly one engine.
+type Region struct {
+	ID     string
+	Column int
+	Row    int
+	Index  int // Row-major position in the grid, used to partition entity IDs
+	Bounds config.Bounds
+}
+
+// Contains reports whether (x, y) lies in the region. Cells are half-open so
+// every point of the world belongs to exactly one region.
+func (r Region) Contains(x, y float64) bool {
+	return x >= r.Bounds.MinX && x < r.Bounds.MaxX &&
+		y >= r.Bounds.MinY && y < r.Bounds.MaxY
+}
+
+// EntityIDBase is the first entity ID this region hands out. Each region gets
+// its own 2^24 block so IDs stay unique across the cluster through handoffs.
+func (r Region) EntityIDBase() uint32 {
+	return uint32(r.Index)<<24 + 1
+}
+
+// Grid partitions the world bounds into Columns x Rows equal regions.
+type Grid struct {
+	world   config.Bounds
+	columns int
+	rows    int
+	cellW   float64
+	cellH   float64
+}
+
+func NewGrid(world config.Bounds, columns, rows int) *Grid {
+	return &Grid{
+		world:   world,
+		columns: columns,
+		rows:    rows,
+		cellW:   (world.MaxX - world.MinX) / float64(columns),
+		cellH:   (world.MaxY - world.MinY) / float64(rows),
+	}
+}
+
+func RegionID(column, row int) string {
+	return fmt.Sprintf("r%d-%d", column, row)
+}
+
+// Region returns a region by ID.
+func (g *Grid) Region(id string) (Region, bool) {
+	var column, row int
+	if _, err := fmt.Sscanf(id, "r%d-%d", &column, &row); err != nil {
+		return Region{}, false
+	}
+	return g.cell(column, row)
+}
+
+// RegionAt returns the region containing (x, y). Points on the outer max
+// edges belong to the last row or column.
+func (g *Grid) RegionAt(x, y float64) (Region, bool) {
+	if x < g.world.MinX || x > g.world.MaxX || y < g.world.MinY || y > g.world.MaxY {
+		return Region{}, false
+	}
+
+	column := int(math.Min(float64(g.columns-1), math.Floor((x-g.world.MinX)/g.cellW)))
+	row := int(math.Min(float64(g.rows-1), math.Floor((y-g.world.MinY)/g.cellH)))
+	return g.cell(column, row)
+}
+
+// Regions returns every region in row-major order.
+func (g *Grid) Regions() []Region {
+	regions := make([]Region, 0, g.columns*g.rows)
+	for row := 0; row < g.rows; row++ {
+		for column := 0; column < g.columns; column++ {
+			region, _ := g.cell(column, row)
+			regions = append(regions, region)
+		}
+	}
+	return regions
+}
+
+// Neighbors returns the up to eight regions adjacent to id, including
+// diagonals, since AOI circles reach across corners.
+func (g *Grid) Neighbors(id string) []Region {
+	region, ok := g.Region(id)
+	if !ok {
+		return nil
+	}
+
+	var neighbors []Region
+	for dy := -1; dy <= 1; dy++ {
+		for dx := -1; dx <= 1; dx++ {
+			if dx == 0 && dy == 0 {
+				continue
+			}
+			if neighbor, ok := g.cell(region.Column+dx, region.Row+dy); ok {
+				neighbors = append(neighbors, neighbor)
+			}
+		}
+	}
+	return neighbors
+}
+
+// EngineBounds returns the bounds the region's engine should simulate: the
+// region grown by margin on each inner side, so entities can step over the
+// border before being handed off and ghosts fit in the spatial index.
+func (g *Grid) EngineBounds(region Region, margin float64) config.Bounds {
+	return config.Bounds{
+		MinX: math.Max(g.world.MinX, region.Bounds.MinX-margin),
+		MinY: math.Max(g.world.MinY, region.Bounds.MinY-margin),
+		MaxX: math.Min(g.world.MaxX, region.Bounds.MaxX+margin),
+		MaxY: math.Min(g.world.MaxY, region.Bounds.MaxY+margin),
+		MinZ: g.world.MinZ,
+		MaxZ: g.world.MaxZ,
+	}
+}
+
+// Near reports whether (x, y) is within margin of region's bounds.
+func Near(region Region, x, y, margin float64) bool {
+	return x >= region.Bounds.MinX-margin && x <= region.Bounds.MaxX+margin &&
+		y >= region.Bounds.MinY-margin && y <= region.Bounds.MaxY+margin
+}
+
+func (g *Grid) cell(column, row int) (Region, bool) {
+	if column < 0 || column >= g.columns || row < 0 || row >= g.rows {
+		return Region{}, false
+	}
+
+	minX := g.world.MinX + float64(column)*g.cellW
+	minY := g.world.MinY + float64(row)*g.cellH
+	maxX := minX + g.cellW
+	maxY := minY + g.cellH
+
+	// Let the last column and row include the world's outer edge
+	if column == g.columns-1 {
+		maxX = math.Nextafter(g.world.MaxX, math.Inf(1))
+	}
+	if row == g.rows-1 {
+		maxY = math.Nextafter(g.world.MaxY, math.Inf(1))
+	}
+
+	return Region{
+		ID:     RegionID(column, row),
+		Column: column,
+		Row:    row,
+		Index:  row*g.columns + column,
+		Bounds: config.Bounds{MinX: minX, MinY: minY, MaxX: maxX, MaxY: maxY},
+	}, true
+}
@@ -0,0 +1,595 @@
+package world
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/akarsh-2004/aether/internal/config"
+	"github.com/akarsh-2004/aether/internal/engine"
+	"github.com/akarsh-2004/aether/internal/engine/tick"
+	"github.com/akarsh-2004/aether/internal/utils"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrWorldNotFound   = errors.New("world not found")
+	ErrWorldExists     = errors.New("world already exists")
+	ErrWorldFull       = errors.New("world is full")
+	ErrWorldOccupied   = errors.New("world has clients or reserved places")
+	ErrWorldPersistent = errors.New("world cannot be removed")
+	ErrTooManyWorlds   = errors.New("world limit reached")
+	ErrUnknownTemplate = errors.New("unknown world template")
+)
+
+// World is one isolated simulation: its own engine, bounds, tick rate and
+// entity cap. Entity IDs are only unique within a world.
+type World struct {
+	ID        string
+	Template  string // empty for worlds added with AddWorld
+	Engine    *engine.SpatialEngine
+	Config    config.EngineConfig
+	CreatedAt time.Time
+
+	persistent bool
+	private    bool // reserved by the lobby; never picked by template
+	cancel     context.CancelFunc
+	done       chan struct{}
+
+	// guarded by Manager.mu
+	players       int
+	reserved      int // places held for ticket holders
+	reservedUntil time.Time
+	emptySince    time.Time
+}
+
+// WorldInfo is the public description of a world returned by List.
+type WorldInfo struct {
+	ID          string        `json:"id"`
+	Template    string        `json:"template,omitempty"`
+	Players     int           `json:"players"`
+	Reserved    int           `json:"reserved"`
+	Entities    int           `json:"entities"`
+	MaxEntities int           `json:"max_entities"`
+	TickRateMs  int           `json:"tick_rate_ms"`
+	Bounds      config.Bounds `json:"bounds"`
+	Persistent  bool          `json:"persistent"`
+	CreatedAt   time.Time     `json:"created_at"`
+}
+
+// Manager hosts many worlds in one process. The default world is added by
+// the caller, who also runs it; on-demand worlds are created from templates,
+// run by the manager and removed once they have been empty for
+// IdleTimeoutSec.
+type Manager struct {
+	config    config.WorldsConfig
+	engineCfg config.EngineConfig
+	logger    *zap.Logger
+	flushers  EventFlusherFactory
+	onControl engine.ControlListener
+	sender    engine.Sender
+	scripts   ScriptFactory
+	worlds    map[string]*World
+	mu        sync.Mutex
+	ctx       context.Context
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+}
+
+func NewManager(cfg config.WorldsConfig, engineCfg config.EngineConfig, logger *zap.Logger) *Manager {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Manager{
+		config:    cfg,
+		engineCfg: engineCfg,
+		logger:    logger,
+		worlds:    make(map[string]*World),
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+}
+
+// EventFlusherFactory enables a new world's outbox and returns the tick
+// handler that writes its events. On-demand worlds are not snapshotted, so
+// their events are written on their own.
+type EventFlusherFactory func(eng *engine.SpatialEngine) tick.TickHandler
+
+// SetEventFlusher installs the outbox writer for worlds created from now on.
+// It should be called before Start.
+func (m *Manager) SetEventFlusher(factory EventFlusherFactory) {
+	m.flushers = factory
+}
+
+// ScriptFactory creates the game logic scripts for a new world. The scripts
+// should stop when ctx is done.
+type ScriptFactory func(ctx context.Context, worldID string) (engine.ScriptHost, error)
+
+// SetScriptFactory installs the scripts for worlds created from now on. It
+// should be called before Start.
+func (m *Manager) SetScriptFactory(factory ScriptFactory) {
+	m.scripts = factory
+}
+
+// SetControlListener installs the control change callback on every world,
+// current and future. It should be called before Start.
+func (m *Manager) SetControlListener(listener engine.ControlListener) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.onControl = listener
+	for _, w := range m.worlds {
+		w.Engine.SetControlListener(listener)
+	}
+}
+
+// SetSender installs the client delivery callback on every world, current
+// and future. It should be called before Start.
+func (m *Manager) SetSender(send engine.Sender) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.sender = send
+	for _, w := range m.worlds {
+		w.Engine.SetSender(send)
+	}
+}
+
+// AddWorld registers an engine the caller runs itself, such as the default
+// world. Added worlds are never reaped or removed.
+func (m *Manager) AddWorld(id string, eng *engine.SpatialEngine, cfg config.EngineConfig) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.worlds[id]; exists {
+		return fmt.Errorf("%w: %s", ErrWorldExists, id)
+	}
+
+	if m.onControl != nil {
+		eng.SetControlListener(m.onControl)
+	}
+	if m.sender != nil {
+		eng.SetSender(m.sender)
+	}
+
+	m.worlds[id] = &World{
+		ID:         id,
+		Engine:     eng,
+		Config:     cfg,
+		CreatedAt:  time.Now(),
+		persistent: true,
+	}
+	return nil
+}
+
+// Start begins reaping idle worlds.
+func (m *Manager) Start(ctx context.Context) {
+	if m.config.IdleTimeoutSec <= 0 {
+		return
+	}
+
+	m.wg.Add(1)
+	go m.reapLoop(ctx)
+}
+
+// Shutdown stops every world the manager runs. Worlds added with AddWorld
+// are left to their owner.
+func (m *Manager) Shutdown(ctx context.Context) error {
+	m.cancel()
+
+	m.mu.Lock()
+	running := make([]*World, 0, len(m.worlds))
+	for id, w := range m.worlds {
+		if !w.persistent {
+			running = append(running, w)
+			delete(m.worlds, id)
+		}
+	}
+	m.mu.Unlock()
+
+	var firstErr error
+	for _, w := range running {
+		if err := m.stopWorld(ctx, w); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		m.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return firstErr
+}
+
+// Create starts a new world from a template. An empty id generates one.
+func (m *Manager) Create(id, template string) (*World, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.createLocked(id, template)
+}
+
+func (m *Manager) createLocked(id, template string) (*World, error) {
+	tmpl, ok := m.config.Templates[template]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownTemplate, template)
+	}
+
+	if id == "" {
+		id = template + "-" + utils.GenerateID()
+	}
+	if _, exists := m.worlds[id]; exists {
+		return nil, fmt.Errorf("%w: %s", ErrWorldExists, id)
+	}
+	if len(m.worlds) >= m.config.MaxWorlds {
+		return nil, ErrTooManyWorlds
+	}
+
+	cfg := tmpl.Apply(m.engineCfg)
+	eng := engine.NewSpatialEngine(cfg, m.logger.With(zap.String("world_id", id)))
+	if m.flushers != nil {
+		eng.AddTickHandler(m.flushers(eng))
+	}
+	if m.onControl != nil {
+		eng.SetControlListener(m.onControl)
+	}
+	if m.sender != nil {
+		eng.SetSender(m.sender)
+	}
+
+	ctx, cancel := context.WithCancel(m.ctx)
+	if m.scripts != nil {
+		host, err := m.scripts(ctx, id)
+		if err != nil {
+			cancel()
+			return nil, fmt.Errorf("failed to load scripts for world %s: %w", id, err)
+		}
+		eng.SetScriptHost(host)
+	}
+	w := &World{
+		ID:         id,
+		Template:   template,
+		Engine:     eng,
+		Config:     cfg,
+		CreatedAt:  time.Now(),
+		cancel:     cancel,
+		done:       make(chan struct{}),
+		emptySince: time.Now(),
+	}
+
+	go func() {
+		defer close(w.done)
+		if err := eng.Start(ctx); err != nil && !errors.Is(err, context.Canceled) {
+			m.logger.Error("World engine stopped with error", zap.String("world_id", id), zap.Error(err))
+		}
+	}()
+
+	m.worlds[id] = w
+	m.logger.Info("World created",
+		zap.String("world_id", id),
+		zap.String("template", template),
+		zap.Int("tick_rate_ms", cfg.TickRateMs),
+		zap.Int("max_entities", cfg.MaxEntities),
+	)
+	return w, nil
+}
+
+// Remove stops and removes an on-demand world. It refuses worlds that still
+// have clients or reserved places.
+func (m *Manager) Remove(ctx context.Context, id string) error {
+	m.mu.Lock()
+	w, exists := m.worlds[id]
+	if !exists {
+		m.mu.Unlock()
+		return fmt.Errorf("%w: %s", ErrWorldNotFound, id)
+	}
+	if w.persistent {
+		m.mu.Unlock()
+		return fmt.Errorf("%w: %s", ErrWorldPersistent, id)
+	}
+	if w.players > 0 || w.reservedLocked(time.Now()) > 0 {
+		m.mu.Unlock()
+		return fmt.Errorf("%w: %s", ErrWorldOccupied, id)
+	}
+	delete(m.worlds, id)
+	m.mu.Unlock()
+
+	return m.stopWorld(ctx, w)
+}
+
+func (m *Manager) stopWorld(ctx context.Context, w *World) error {
+	err := w.Engine.Shutdown(ctx)
+	w.cancel()
+
+	select {
+	case <-w.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	m.logger.Info("World removed", zap.String("world_id", w.ID))
+	if err != nil {
+		return fmt.Errorf("failed to stop world %s: %w", w.ID, err)
+	}
+	return nil
+}
+
+// Get returns a world by ID.
+func (m *Manager) Get(id string) (*World, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	w, exists := m.worlds[id]
+	return w, exists
+}
+
+// Default returns the default world, if it has been added.
+func (m *Manager) Default() (*World, bool) {
+	return m.Get(m.config.Default)
+}
+
+// Join reserves a place for one client. A worldID picks that world; else a
+// template picks the fullest open world of that template, creating one if
+// all are full; else the client joins the default world. Every successful
+// Join must be paired with a Leave.
+func (m *Manager) Join(worldID, template string) (*World, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var w *World
+	switch {
+	case worldID != "":
+		// Private worlds are entered only with a ticket, through JoinReserved
+		found, exists := m.worlds[worldID]
+		if !exists || found.private {
+			return nil, fmt.Errorf("%w: %s", ErrWorldNotFound, worldID)
+		}
+		if m.fullLocked(found) {
+			return nil, fmt.Errorf("%w: %s", ErrWorldFull, worldID)
+		}
+		w = found
+
+	case template != "":
+		// Fill existing rooms before opening new ones
+		for _, candidate := range m.worlds {
+			if candidate.Template != template || candidate.private || m.fullLocked(candidate) {
+				continue
+			}
+			if w == nil || candidate.players > w.players {
+				w = candidate
+			}
+		}
+		if w == nil {
+			created, err := m.createLocked("", template)
+			if err != nil {
+				return nil, err
+			}
+			w = created
+		}
+
+	default:
+		found, exists := m.worlds[m.config.Default]
+		if !exists {
+			return nil, fmt.Errorf("%w: %s", ErrWorldNotFound, m.config.Default)
+		}
+		if m.fullLocked(found) {
+			return nil, fmt.Errorf("%w: %s", ErrWorldFull, found.ID)
+		}
+		w = found
+	}
+
+	w.players++
+	w.emptySince = time.Time{}
+	return w, nil
+}
+
+// Reserve creates a private world from template and holds slots places in it
+// for ttl. Reserved places are taken with JoinReserved; the world is not
+// offered to other clients and is not reaped while the reservation lasts.
+func (m *Manager) Reserve(template string, slots int, ttl time.Duration) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	w, err := m.createLocked("", template)
+	if err != nil {
+		return "", err
+	}
+	if slots > w.Config.SoftMaxEntities() {
+		slots = w.Config.SoftMaxEntities()
+	}
+
+	w.private = true
+	w.reserved = slots
+	w.reservedUntil = time.Now().Add(ttl)
+	return w.ID, nil
+}
+
+// JoinReserved takes a place reserved with Reserve. Once the reservation has
+// lapsed it behaves like joining by world ID.
+func (m *Manager) JoinReserved(worldID string) (*World, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	w, exists := m.worlds[worldID]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrWorldNotFound, worldID)
+	}
+
+	if w.reservedLocked(time.Now()) > 0 {
+		w.reserved--
+	} else if m.fullLocked(w) {
+		return nil, fmt.Errorf("%w: %s", ErrWorldFull, worldID)
+	}
+
+	w.players++
+	w.emptySince = time.Time{}
+	return w, nil
+}
+
+// Release drops any places still reserved in a world, letting it be reaped
+// once empty.
+func (m *Manager) Release(worldID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if w, exists := m.worlds[worldID]; exists {
+		w.reserved = 0
+	}
+}
+
+func (w *World) reservedLocked(now time.Time) int {
+	if now.After(w.reservedUntil) {
+		return 0
+	}
+	return w.reserved
+}
+
+// Leave releases a place reserved by Join.
+func (m *Manager) Leave(w *World) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if w.players > 0 {
+		w.players--
+	}
+	if w.players == 0 {
+		w.emptySince = time.Now()
+	}
+}
+
+func (m *Manager) fullLocked(w *World) bool {
+	count := w.Engine.EntityCount()
+	if w.players > count {
+		count = w.players // spawns not yet in the engine
+	}
+	return count+w.reservedLocked(time.Now()) >= w.Config.SoftMaxEntities()
+}
+
+// ApplyConfig passes a reloaded engine configuration to every world, with
+// the world's template applied, and keeps it for worlds created later. Each
+// engine takes only the settings that can change while it runs.
+func (m *Manager) ApplyConfig(engineCfg config.EngineConfig) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.engineCfg = m.engineCfg.Reloaded(engineCfg)
+	for _, w := range m.worlds {
+		next := engineCfg
+		if tmpl, ok := m.config.Templates[w.Template]; ok && w.Template != "" {
+			next = tmpl.Apply(engineCfg)
+		}
+
+		w.Config = w.Config.Reloaded(next)
+		w.Engine.ApplyConfig(w.Config)
+	}
+}
+
+// List describes every world except private ones, sorted by ID.
+func (m *Manager) List() []WorldInfo {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	infos := make([]WorldInfo, 0, len(m.worlds))
+	for _, w := range m.worlds {
+		if w.private {
+			continue
+		}
+		infos = append(infos, m.infoLocked(w))
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].ID < infos[j].ID })
+	return infos
+}
+
+// Info describes one world. Private worlds are reported as not found.
+func (m *Manager) Info(id string) (WorldInfo, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	w, exists := m.worlds[id]
+	if !exists || w.private {
+		return WorldInfo{}, false
+	}
+	return m.infoLocked(w), true
+}
+
+func (m *Manager) infoLocked(w *World) WorldInfo {
+	return WorldInfo{
+		ID:          w.ID,
+		Template:    w.Template,
+		Players:     w.players,
+		Reserved:    w.reservedLocked(time.Now()),
+		Entities:    w.Engine.EntityCount(),
+		MaxEntities: w.Config.MaxEntities,
+		TickRateMs:  w.Config.TickRateMs,
+		Bounds:      w.Config.WorldBounds,
+		Persistent:  w.persistent,
+		CreatedAt:   w.CreatedAt,
+	}
+}
+
+func (m *Manager) reapLoop(ctx context.Context) {
+	defer m.wg.Done()
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-m.ctx.Done():
+			return
+		case <-ticker.C:
+			m.reapIdle()
+		}
+	}
+}
+
+func (m *Manager) reapIdle() {
+	cutoff := time.Now().Add(-time.Duration(m.config.IdleTimeoutSec) * time.Second)
+
+	now := time.Now()
+
+	m.mu.Lock()
+	var idle []*World
+	for id, w := range m.worlds {
+		if w.persistent || w.players > 0 || w.reservedLocked(now) > 0 || w.emptySince.After(cutoff) {
+			continue
+		}
+		idle = append(idle, w)
+		delete(m.worlds, id)
+	}
+	m.mu.Unlock()
+
+	for _, w := range idle {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		if err := m.stopWorld(ctx, w); err != nil {
+			m.logger.Error("Failed to stop idle world", zap.String("world_id", w.ID), zap.Error(err))
+		}
+		cancel()
+	}
+}
+
+func (m *Manager) GetStats() map[string]interface{} {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	players := 0
+	for _, w := range m.worlds {
+		players += w.players
+	}
+
+	return map[string]interface{}{
+		"world_count":  len(m.worlds),
+		"max_worlds":   m.config.MaxWorlds,
+		"player_count": players,
+	}
+}
@@ -0,0 +1,45 @@
+package world
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/akarsh-2004/aether/internal/config"
+	"go.uber.org/zap"
+)
+
+func TestManager_PrivateWorldsNeedTicket(t *testing.T) {
+	cfg := config.Default()
+	cfg.Worlds.Templates = map[string]config.WorldTemplate{"arena": {}}
+
+	m := NewManager(cfg.Worlds, cfg.Engine, zap.NewNop())
+	m.Start(context.Background())
+	defer m.Shutdown(context.Background())
+
+	worldID, err := m.Reserve("arena", 2, time.Minute)
+	if err != nil {
+		t.Fatalf("reserve: %v", err)
+	}
+
+	if _, err := m.Join(worldID, ""); !errors.Is(err, ErrWorldNotFound) {
+		t.Errorf("expected joining a private world by ID to fail with ErrWorldNotFound, got %v", err)
+	}
+	if _, err := m.Join("", "arena"); err != nil {
+		t.Fatalf("join by template: %v", err)
+	}
+
+	for _, info := range m.List() {
+		if info.ID == worldID {
+			t.Error("expected private world to be left out of List")
+		}
+	}
+	if _, exists := m.Info(worldID); exists {
+		t.Error("expected Info to hide the private world")
+	}
+
+	if _, err := m.JoinReserved(worldID); err != nil {
+		t.Errorf("expected a ticket holder to join, got %v", err)
+	}
+}
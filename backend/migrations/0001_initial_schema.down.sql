@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS game_sessions;
+DROP TABLE IF EXISTS outbox_events;
+DROP TABLE IF EXISTS entity_snapshots;
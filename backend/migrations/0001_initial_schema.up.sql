@@ -0,0 +1,46 @@
+-- Baseline schema. Uses IF NOT EXISTS so databases created by the old
+-- inline schema bootstrap are adopted without changes.
+
+CREATE TABLE IF NOT EXISTS entity_snapshots (
+    id SERIAL PRIMARY KEY,
+    entity_id INTEGER NOT NULL,
+    entity_type VARCHAR(50) NOT NULL,
+    position_x DOUBLE PRECISION NOT NULL,
+    position_y DOUBLE PRECISION NOT NULL,
+    velocity_x DOUBLE PRECISION NOT NULL,
+    velocity_y DOUBLE PRECISION NOT NULL,
+    client_id VARCHAR(100) NOT NULL,
+    last_update TIMESTAMP WITH TIME ZONE NOT NULL,
+    tick_number BIGINT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_entity_snapshots_entity_id ON entity_snapshots(entity_id);
+CREATE INDEX IF NOT EXISTS idx_entity_snapshots_client_id ON entity_snapshots(client_id);
+CREATE INDEX IF NOT EXISTS idx_entity_snapshots_created_at ON entity_snapshots(created_at);
+
+CREATE TABLE IF NOT EXISTS outbox_events (
+    id SERIAL PRIMARY KEY,
+    event_type VARCHAR(50) NOT NULL,
+    payload JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    processed BOOLEAN DEFAULT FALSE,
+    processed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_events_processed ON outbox_events(processed, created_at);
+CREATE INDEX IF NOT EXISTS idx_outbox_events_event_type ON outbox_events(event_type);
+
+CREATE TABLE IF NOT EXISTS game_sessions (
+    id SERIAL PRIMARY KEY,
+    session_id VARCHAR(100) UNIQUE NOT NULL,
+    client_id VARCHAR(100) NOT NULL,
+    entity_id INTEGER,
+    start_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    end_time TIMESTAMP WITH TIME ZONE,
+    duration_seconds INTEGER,
+    metadata JSONB
+);
+
+CREATE INDEX IF NOT EXISTS idx_game_sessions_client_id ON game_sessions(client_id);
+CREATE INDEX IF NOT EXISTS idx_game_sessions_start_time ON game_sessions(start_time);
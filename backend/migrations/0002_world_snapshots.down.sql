@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS world_snapshots;
+DROP INDEX IF EXISTS idx_entity_snapshots_tick_number;
@@ -0,0 +1,10 @@
+-- Marker rows for complete world snapshots written by the snapshotter.
+
+CREATE INDEX IF NOT EXISTS idx_entity_snapshots_tick_number ON entity_snapshots(tick_number);
+
+CREATE TABLE IF NOT EXISTS world_snapshots (
+    tick_number BIGINT PRIMARY KEY,
+    next_entity_id INTEGER NOT NULL,
+    entity_count INTEGER NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
@@ -0,0 +1,11 @@
+DROP TABLE IF EXISTS outbox_dead_letters;
+DROP INDEX IF EXISTS idx_outbox_events_aggregate_pending;
+DROP INDEX IF EXISTS idx_outbox_events_pending;
+
+ALTER TABLE outbox_events
+    DROP COLUMN IF EXISTS last_error,
+    DROP COLUMN IF EXISTS locked_until,
+    DROP COLUMN IF EXISTS locked_by,
+    DROP COLUMN IF EXISTS next_attempt_at,
+    DROP COLUMN IF EXISTS attempts,
+    DROP COLUMN IF EXISTS aggregate_key;
@@ -0,0 +1,28 @@
+-- Claim-based leasing, retries and dead-lettering for the outbox.
+
+ALTER TABLE outbox_events
+    ADD COLUMN IF NOT EXISTS aggregate_key VARCHAR(100) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS attempts INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    ADD COLUMN IF NOT EXISTS locked_by VARCHAR(100),
+    ADD COLUMN IF NOT EXISTS locked_until TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS last_error TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_outbox_events_pending
+    ON outbox_events(next_attempt_at, id) WHERE processed = FALSE;
+CREATE INDEX IF NOT EXISTS idx_outbox_events_aggregate_pending
+    ON outbox_events(aggregate_key, id) WHERE processed = FALSE;
+
+CREATE TABLE IF NOT EXISTS outbox_dead_letters (
+    id SERIAL PRIMARY KEY,
+    event_id INTEGER NOT NULL,
+    event_type VARCHAR(50) NOT NULL,
+    aggregate_key VARCHAR(100) NOT NULL,
+    payload JSONB NOT NULL,
+    attempts INTEGER NOT NULL,
+    last_error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    dead_lettered_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_dead_letters_event_type ON outbox_dead_letters(event_type);
@@ -0,0 +1,2 @@
+ALTER TABLE outbox_dead_letters DROP COLUMN IF EXISTS schema_version;
+ALTER TABLE outbox_events DROP COLUMN IF EXISTS schema_version;
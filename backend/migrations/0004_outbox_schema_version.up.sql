@@ -0,0 +1,8 @@
+-- Typed outbox payloads carry a schema version. Rows written before this
+-- migration hold legacy untyped payloads and are marked as version 1.
+
+ALTER TABLE outbox_events
+    ADD COLUMN IF NOT EXISTS schema_version INTEGER NOT NULL DEFAULT 1;
+
+ALTER TABLE outbox_dead_letters
+    ADD COLUMN IF NOT EXISTS schema_version INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,18 @@
+-- Audit log of proximity chat, including messages rejected by moderation.
+
+CREATE TABLE IF NOT EXISTS chat_messages (
+    id BIGSERIAL PRIMARY KEY,
+    world_id TEXT NOT NULL,
+    sender_client_id TEXT NOT NULL,
+    sender_entity_id INTEGER NOT NULL,
+    position_x DOUBLE PRECISION NOT NULL,
+    position_y DOUBLE PRECISION NOT NULL,
+    text TEXT NOT NULL,
+    original_text TEXT NOT NULL,
+    rejected_by TEXT NOT NULL DEFAULT '',
+    recipients INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_chat_messages_world_created ON chat_messages(world_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_chat_messages_sender_created ON chat_messages(sender_client_id, created_at DESC);
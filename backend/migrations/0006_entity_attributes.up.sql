@@ -0,0 +1,3 @@
+-- Replicated entity attributes, stored with each entity snapshot.
+
+ALTER TABLE entity_snapshots ADD COLUMN IF NOT EXISTS attributes JSONB NOT NULL DEFAULT '{}'::jsonb;
@@ -0,0 +1,3 @@
+ALTER TABLE entity_snapshots DROP COLUMN IF EXISTS yaw;
+ALTER TABLE entity_snapshots DROP COLUMN IF EXISTS velocity_z;
+ALTER TABLE entity_snapshots DROP COLUMN IF EXISTS position_z;
@@ -0,0 +1,5 @@
+-- Height, vertical velocity and heading, stored with each entity snapshot.
+
+ALTER TABLE entity_snapshots ADD COLUMN IF NOT EXISTS position_z DOUBLE PRECISION NOT NULL DEFAULT 0;
+ALTER TABLE entity_snapshots ADD COLUMN IF NOT EXISTS velocity_z DOUBLE PRECISION NOT NULL DEFAULT 0;
+ALTER TABLE entity_snapshots ADD COLUMN IF NOT EXISTS yaw DOUBLE PRECISION NOT NULL DEFAULT 0;
@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS idx_entity_snapshots_region_tick;
+CREATE INDEX IF NOT EXISTS idx_entity_snapshots_tick_number ON entity_snapshots(tick_number);
+
+DELETE FROM world_snapshots WHERE region_id <> '';
+DELETE FROM entity_snapshots WHERE region_id <> '';
+
+ALTER TABLE world_snapshots DROP CONSTRAINT IF EXISTS world_snapshots_pkey;
+ALTER TABLE world_snapshots ADD PRIMARY KEY (tick_number);
+
+ALTER TABLE world_snapshots DROP COLUMN IF EXISTS region_id;
+ALTER TABLE entity_snapshots DROP COLUMN IF EXISTS region_id;
@@ -0,0 +1,11 @@
+-- Key world snapshots by region, so sharded regions sharing a database never
+-- overwrite or restore each other's snapshots. Unsharded servers use ''.
+
+ALTER TABLE entity_snapshots ADD COLUMN IF NOT EXISTS region_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE world_snapshots ADD COLUMN IF NOT EXISTS region_id TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE world_snapshots DROP CONSTRAINT IF EXISTS world_snapshots_pkey;
+ALTER TABLE world_snapshots ADD PRIMARY KEY (region_id, tick_number);
+
+DROP INDEX IF EXISTS idx_entity_snapshots_tick_number;
+CREATE INDEX IF NOT EXISTS idx_entity_snapshots_region_tick ON entity_snapshots(region_id, tick_number);
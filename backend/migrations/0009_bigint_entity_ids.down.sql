@@ -0,0 +1,9 @@
+UPDATE chat_messages SET sender_entity_id = sender_entity_id - 4294967296 WHERE sender_entity_id > 2147483647;
+UPDATE game_sessions SET entity_id = entity_id - 4294967296 WHERE entity_id > 2147483647;
+UPDATE world_snapshots SET next_entity_id = next_entity_id - 4294967296 WHERE next_entity_id > 2147483647;
+UPDATE entity_snapshots SET entity_id = entity_id - 4294967296 WHERE entity_id > 2147483647;
+
+ALTER TABLE chat_messages ALTER COLUMN sender_entity_id TYPE INTEGER;
+ALTER TABLE game_sessions ALTER COLUMN entity_id TYPE INTEGER;
+ALTER TABLE world_snapshots ALTER COLUMN next_entity_id TYPE INTEGER;
+ALTER TABLE entity_snapshots ALTER COLUMN entity_id TYPE INTEGER;
@@ -0,0 +1,13 @@
+-- Entity IDs are unsigned 32-bit; region blocks from index 128 up do not fit
+-- in INTEGER. IDs already stored as negative numbers are moved back into the
+-- unsigned range.
+
+ALTER TABLE entity_snapshots ALTER COLUMN entity_id TYPE BIGINT;
+ALTER TABLE world_snapshots ALTER COLUMN next_entity_id TYPE BIGINT;
+ALTER TABLE game_sessions ALTER COLUMN entity_id TYPE BIGINT;
+ALTER TABLE chat_messages ALTER COLUMN sender_entity_id TYPE BIGINT;
+
+UPDATE entity_snapshots SET entity_id = entity_id + 4294967296 WHERE entity_id < 0;
+UPDATE world_snapshots SET next_entity_id = next_entity_id + 4294967296 WHERE next_entity_id < 0;
+UPDATE game_sessions SET entity_id = entity_id + 4294967296 WHERE entity_id < 0;
+UPDATE chat_messages SET sender_entity_id = sender_entity_id + 4294967296 WHERE sender_entity_id < 0;
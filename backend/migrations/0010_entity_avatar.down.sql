@@ -0,0 +1 @@
+ALTER TABLE entity_snapshots DROP COLUMN IF EXISTS avatar;
@@ -0,0 +1,4 @@
+-- Marks the entity a client spawned as its own, so a resuming client gets its
+-- avatar back rather than whichever of its entities has the lowest ID.
+
+ALTER TABLE entity_snapshots ADD COLUMN IF NOT EXISTS avatar BOOLEAN NOT NULL DEFAULT FALSE;
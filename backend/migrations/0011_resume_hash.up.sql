@@ -0,0 +1,4 @@
+-- Hash of the avatar's resume token, so a reconnecting client has to prove it
+-- owns the session it resumes.
+
+ALTER TABLE entity_snapshots ADD COLUMN IF NOT EXISTS resume_hash BYTEA;
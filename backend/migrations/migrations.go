@@ -0,0 +1,9 @@
+// Package migrations embeds the versioned SQL migrations applied by the
+// postgres migrator. Files are named NNNN_description.up.sql and
+// NNNN_description.down.sql; versions must be unique and are applied in order.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
@@ -14,14 +14,17 @@ const (
 	worldWidth       = 2000
 	worldHeight      = 2000
 	quadtreeCapacity = 4
+	spawnAttempts    = 16
 )
 
 func main() {
 	registry := engine.NewRegistry()
+	worldBoundary := engine.NewAABB(worldWidth/2, worldHeight/2, worldWidth/2)
+
 	hub := websocket.NewHub(registry)
+	hub.SetSpawner(engine.NewSpawner(registry, worldBoundary, spawnAttempts))
 	go hub.Run()
 
-	worldBoundary := engine.NewAABB(worldWidth/2, worldHeight/2, worldWidth/2)
 	gameEngine := engine.NewGameEngine(registry, hub, worldBoundary, quadtreeCapacity)
 	gameEngine.Start(tickRate)
 	defer gameEngine.Stop()
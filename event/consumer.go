@@ -0,0 +1,237 @@
+package event
+
+import (
+	"bufio"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// HandlerFunc handles a decoded event. model is one of the typed models in
+// this package.
+type HandlerFunc func(env *Envelope, model interface{}) error
+
+// dedupeWindow is how many recent event IDs a Consumer remembers to drop
+// redeliveries.
+const dedupeWindow = 4096
+
+// Consumer dispatches envelopes to handlers registered per event type and
+// drops duplicate deliveries. It is safe for concurrent use.
+type Consumer struct {
+	mu       sync.Mutex
+	handlers map[string][]HandlerFunc
+	seen     map[int64]struct{}
+	order    []int64
+}
+
+// NewConsumer creates an empty Consumer.
+func NewConsumer() *Consumer {
+	return &Consumer{
+		handlers: make(map[string][]HandlerFunc),
+		seen:     make(map[int64]struct{}),
+	}
+}
+
+// Handle registers fn for eventType. Several handlers may share a type.
+func (c *Consumer) Handle(eventType string, fn HandlerFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.handlers[eventType] = append(c.handlers[eventType], fn)
+}
+
+// OnEntitySpawned registers a typed handler for entity_spawned events.
+func (c *Consumer) OnEntitySpawned(fn func(*EntitySpawned) error) {
+	c.Handle(TypeEntitySpawned, func(_ *Envelope, m interface{}) error { return fn(m.(*EntitySpawned)) })
+}
+
+// OnEntityDespawned registers a typed handler for entity_despawned events.
+func (c *Consumer) OnEntityDespawned(fn func(*EntityDespawned) error) {
+	c.Handle(TypeEntityDespawned, func(_ *Envelope, m interface{}) error { return fn(m.(*EntityDespawned)) })
+}
+
+// OnMovementCorrection registers a typed handler for movement_correction events.
+func (c *Consumer) OnMovementCorrection(fn func(*MovementCorrection) error) {
+	c.Handle(TypeMovementCorrection, func(_ *Envelope, m interface{}) error { return fn(m.(*MovementCorrection)) })
+}
+
+// OnEntityControlChanged registers a typed handler for entity_control_changed events.
+func (c *Consumer) OnEntityControlChanged(fn func(*EntityControlChanged) error) {
+	c.Handle(TypeEntityControlChanged, func(_ *Envelope, m interface{}) error { return fn(m.(*EntityControlChanged)) })
+}
+
+// OnProjectileHit registers a typed handler for projectile_hit events.
+func (c *Consumer) OnProjectileHit(fn func(*ProjectileHit) error) {
+	c.Handle(TypeProjectileHit, func(_ *Envelope, m interface{}) error { return fn(m.(*ProjectileHit)) })
+}
+
+// OnZoneEntered registers a typed handler for zone_entered events.
+func (c *Consumer) OnZoneEntered(fn func(*ZoneEntered) error) {
+	c.Handle(TypeZoneEntered, func(_ *Envelope, m interface{}) error { return fn(m.(*ZoneEntered)) })
+}
+
+// OnZoneExited registers a typed handler for zone_exited events.
+func (c *Consumer) OnZoneExited(fn func(*ZoneExited) error) {
+	c.Handle(TypeZoneExited, func(_ *Envelope, m interface{}) error { return fn(m.(*ZoneExited)) })
+}
+
+// OnSessionStarted registers a typed handler for session_started events.
+func (c *Consumer) OnSessionStarted(fn func(*SessionStarted) error) {
+	c.Handle(TypeSessionStarted, func(_ *Envelope, m interface{}) error { return fn(m.(*SessionStarted)) })
+}
+
+// OnSessionEnded registers a typed handler for session_ended events.
+func (c *Consumer) OnSessionEnded(fn func(*SessionEnded) error) {
+	c.Handle(TypeSessionEnded, func(_ *Envelope, m interface{}) error { return fn(m.(*SessionEnded)) })
+}
+
+// Dispatch decodes env and runs its handlers. Events without handlers and
+// already-seen event IDs are ignored. An event whose handler fails is not
+// marked as seen, so a redelivery is processed again.
+func (c *Consumer) Dispatch(env *Envelope) error {
+	c.mu.Lock()
+	if _, dup := c.seen[env.ID]; dup {
+		c.mu.Unlock()
+		return nil
+	}
+	handlers := append([]HandlerFunc(nil), c.handlers[env.Type]...)
+	c.mu.Unlock()
+
+	if len(handlers) == 0 {
+		return nil
+	}
+
+	model, err := env.Decode()
+	if err != nil {
+		return err
+	}
+
+	for _, h := range handlers {
+		if err := h(env, model); err != nil {
+			return fmt.Errorf("handle %s event %d: %w", env.Type, env.ID, err)
+		}
+	}
+
+	c.markSeen(env.ID)
+	return nil
+}
+
+func (c *Consumer) markSeen(id int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.seen[id] = struct{}{}
+	c.order = append(c.order, id)
+	if len(c.order) > dedupeWindow {
+		delete(c.seen, c.order[0])
+		c.order = c.order[1:]
+	}
+}
+
+// Consume dispatches every JSON line in r until EOF.
+func (c *Consumer) Consume(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if err := c.dispatchLine(scanner.Bytes()); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// FollowFile dispatches events from a JSONL file written by the server's file
+// sink, polling for new lines until ctx is cancelled.
+func (c *Consumer) FollowFile(ctx context.Context, path string, pollInterval time.Duration) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	reader := bufio.NewReader(f)
+	var partial []byte
+	for {
+		line, err := reader.ReadBytes('\n')
+		partial = append(partial, line...)
+
+		if err == nil {
+			if dispatchErr := c.dispatchLine(partial); dispatchErr != nil {
+				return dispatchErr
+			}
+			partial = partial[:0]
+			continue
+		}
+		if !errors.Is(err, io.EOF) {
+			return err
+		}
+
+		// Reached the end of what has been written so far; wait for more
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// WebhookHandler returns an http.Handler that receives events from the
+// server's webhook sink. If secret is non-empty, the X-Aether-Signature header
+// must carry a valid HMAC-SHA256 of the body. Handler errors return 500 so the
+// server retries the delivery.
+func (c *Consumer) WebhookHandler(secret string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, 1024*1024))
+		if err != nil {
+			http.Error(w, "read body", http.StatusBadRequest)
+			return
+		}
+
+		if secret != "" {
+			mac := hmac.New(sha256.New, []byte(secret))
+			mac.Write(body)
+			expected := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+			if !hmac.Equal([]byte(expected), []byte(r.Header.Get("X-Aether-Signature"))) {
+				http.Error(w, "invalid signature", http.StatusUnauthorized)
+				return
+			}
+		}
+
+		var env Envelope
+		if err := json.Unmarshal(body, &env); err != nil {
+			http.Error(w, "invalid envelope", http.StatusBadRequest)
+			return
+		}
+
+		if err := c.Dispatch(&env); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+func (c *Consumer) dispatchLine(line []byte) error {
+	if len(line) == 0 || (len(line) == 1 && line[0] == '\n') {
+		return nil
+	}
+
+	var env Envelope
+	if err := json.Unmarshal(line, &env); err != nil {
+		return fmt.Errorf("decode envelope: %w", err)
+	}
+	return c.Dispatch(&env)
+}
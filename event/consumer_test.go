@@ -0,0 +1,39 @@
+package event
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestConsumer_DispatchesTypedAndDedupes(t *testing.T) {
+	var buf bytes.Buffer
+	pub := NewWriterPublisher(&buf)
+
+	if err := pub.Publish(TypeEntitySpawned, "entity:7", EntitySpawned{EntityID: 7, EntityType: "player", X: 1, Y: 2}); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	if err := pub.Publish(TypeSessionEnded, "", SessionEnded{SessionID: "s1", DurationSeconds: 30}); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+
+	// Replay the same lines to simulate an at-least-once redelivery
+	stream := buf.String() + buf.String()
+
+	c := NewConsumer()
+	spawned := 0
+	c.OnEntitySpawned(func(e *EntitySpawned) error {
+		spawned++
+		if e.EntityID != 7 || e.X != 1 || e.Y != 2 {
+			t.Errorf("unexpected spawn event %+v", e)
+		}
+		return nil
+	})
+
+	if err := c.Consume(bytes.NewBufferString(stream)); err != nil {
+		t.Fatalf("read: %v", err)
+	}
+
+	if spawned != 1 {
+		t.Errorf("expected 1 spawn event after dedupe, got %d", spawned)
+	}
+}
@@ -0,0 +1,165 @@
+// Package event contains the world event models emitted by the Aether server
+// outbox, plus helpers for publishing and consuming them in downstream
+// services.
+package event
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Event types emitted by the server.
+const (
+	TypeEntitySpawned        = "entity_spawned"
+	TypeEntityDespawned      = "entity_despawned"
+	TypeMovementCorrection   = "movement_correction"
+	TypeEntityControlChanged = "entity_control_changed"
+	TypeProjectileHit        = "projectile_hit"
+	TypeZoneEntered          = "zone_entered"
+	TypeZoneExited           = "zone_exited"
+	TypeSessionStarted       = "session_started"
+	TypeSessionEnded         = "session_ended"
+)
+
+// SchemaVersion is the payload schema version these models describe.
+const SchemaVersion = 2
+
+// Envelope is the wire format of every event: one JSON object per event, as
+// written by the server's file, NATS, Kafka and webhook sinks. Delivery is
+// at-least-once, so consumers should deduplicate on ID. The server upgrades
+// payloads to the current SchemaVersion before delivering them.
+type Envelope struct {
+	ID            int64           `json:"id"`
+	Type          string          `json:"type"`
+	AggregateKey  string          `json:"aggregate_key,omitempty"`
+	SchemaVersion int             `json:"schema_version"`
+	CreatedAt     time.Time       `json:"created_at"`
+	Payload       json.RawMessage `json:"payload"`
+}
+
+// EntitySpawned is emitted when an entity enters the world.
+type EntitySpawned struct {
+	EntityID   uint32  `json:"entity_id"`
+	EntityType string  `json:"entity_type"`
+	ClientID   string  `json:"client_id"`
+	X          float64 `json:"x"`
+	Y          float64 `json:"y"`
+	Z          float64 `json:"z"`
+}
+
+// EntityDespawned is emitted when an entity leaves the world.
+type EntityDespawned struct {
+	EntityID uint32  `json:"entity_id"`
+	ClientID string  `json:"client_id"`
+	Reason   string  `json:"reason"`
+	X        float64 `json:"x"`
+	Y        float64 `json:"y"`
+	Z        float64 `json:"z"`
+}
+
+// MovementCorrection is emitted when the server overrides a client's
+// predicted position.
+type MovementCorrection struct {
+	EntityID    uint32  `json:"entity_id"`
+	ClientID    string  `json:"client_id"`
+	X           float64 `json:"x"`
+	Y           float64 `json:"y"`
+	Z           float64 `json:"z"`
+	AckSequence uint64  `json:"ack_sequence,string"` // 64-bit ints are JSON strings in the proto mapping
+}
+
+// EntityControlChanged is emitted when control of an entity moves between
+// clients. An empty ClientID means nobody controls the entity any more.
+type EntityControlChanged struct {
+	EntityID         uint32 `json:"entity_id"`
+	ClientID         string `json:"client_id"`
+	PreviousClientID string `json:"previous_client_id"`
+	Reason           string `json:"reason"` // possessed, released, transferred or revoked
+}
+
+// ProjectileHit is emitted when a projectile hits an entity. X, Y and Z are
+// where it struck.
+type ProjectileHit struct {
+	ProjectileID    uint32  `json:"projectile_id"`
+	ProjectileType  string  `json:"projectile_type"`
+	ShooterEntityID uint32  `json:"shooter_entity_id"`
+	ShooterClientID string  `json:"shooter_client_id"`
+	TargetEntityID  uint32  `json:"target_entity_id"`
+	TargetClientID  string  `json:"target_client_id"`
+	X               float64 `json:"x"`
+	Y               float64 `json:"y"`
+	Z               float64 `json:"z"`
+	Damage          int32   `json:"damage"`
+}
+
+// ZoneEntered is emitted when an entity moves into a zone.
+type ZoneEntered struct {
+	EntityID uint32  `json:"entity_id"`
+	ClientID string  `json:"client_id"`
+	Zone     string  `json:"zone"`
+	X        float64 `json:"x"`
+	Y        float64 `json:"y"`
+	Z        float64 `json:"z"`
+}
+
+// ZoneExited is emitted when an entity moves out of a zone. Entities that
+// despawn inside a zone do not exit it.
+type ZoneExited struct {
+	EntityID uint32  `json:"entity_id"`
+	ClientID string  `json:"client_id"`
+	Zone     string  `json:"zone"`
+	X        float64 `json:"x"`
+	Y        float64 `json:"y"`
+	Z        float64 `json:"z"`
+}
+
+// SessionStarted is emitted when a client connects.
+type SessionStarted struct {
+	SessionID string `json:"session_id"`
+	ClientID  string `json:"client_id"`
+}
+
+// SessionEnded is emitted when a client disconnects.
+type SessionEnded struct {
+	SessionID       string `json:"session_id"`
+	ClientID        string `json:"client_id"`
+	DurationSeconds int    `json:"duration_seconds"`
+}
+
+// Decode unmarshals the payload into the typed model for the envelope's
+// type. It returns one of the model pointers above.
+func (e *Envelope) Decode() (interface{}, error) {
+	if e.SchemaVersion > SchemaVersion {
+		return nil, fmt.Errorf("%s event has schema version %d, newer than supported version %d", e.Type, e.SchemaVersion, SchemaVersion)
+	}
+
+	var model interface{}
+	switch e.Type {
+	case TypeEntitySpawned:
+		model = &EntitySpawned{}
+	case TypeEntityDespawned:
+		model = &EntityDespawned{}
+	case TypeMovementCorrection:
+		model = &MovementCorrection{}
+	case TypeEntityControlChanged:
+		model = &EntityControlChanged{}
+	case TypeProjectileHit:
+		model = &ProjectileHit{}
+	case TypeZoneEntered:
+		model = &ZoneEntered{}
+	case TypeZoneExited:
+		model = &ZoneExited{}
+	case TypeSessionStarted:
+		model = &SessionStarted{}
+	case TypeSessionEnded:
+		model = &SessionEnded{}
+	default:
+		return nil, fmt.Errorf("unknown event type %q", e.Type)
+	}
+
+	if err := json.Unmarshal(e.Payload, model); err != nil {
+		return nil, fmt.Errorf("decode %s payload: %w", e.Type, err)
+	}
+	return model, nil
+}
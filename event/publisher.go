@@ -0,0 +1,55 @@
+package event
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// Publisher emits events in the envelope wire format.
+type Publisher interface {
+	Publish(eventType, aggregateKey string, payload interface{}) error
+}
+
+// WriterPublisher writes envelopes as JSON lines to an io.Writer. It produces
+// the same format as the server's file sink, which makes it handy for feeding
+// a Consumer in tests and local tools.
+type WriterPublisher struct {
+	mu     sync.Mutex
+	w      io.Writer
+	nextID int64
+}
+
+// NewWriterPublisher creates a publisher that writes to w.
+func NewWriterPublisher(w io.Writer) *WriterPublisher {
+	return &WriterPublisher{w: w}
+}
+
+// Publish marshals payload into an envelope and writes it as one line.
+func (p *WriterPublisher) Publish(eventType, aggregateKey string, payload interface{}) error {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal %s payload: %w", eventType, err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.nextID++
+	line, err := json.Marshal(Envelope{
+		ID:            p.nextID,
+		Type:          eventType,
+		AggregateKey:  aggregateKey,
+		SchemaVersion: SchemaVersion,
+		CreatedAt:     time.Now().UTC(),
+		Payload:       raw,
+	})
+	if err != nil {
+		return fmt.Errorf("marshal envelope: %w", err)
+	}
+
+	_, err = p.w.Write(append(line, '\n'))
+	return err
+}
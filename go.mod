@@ -3,6 +3,6 @@ module github.com/yourname/aethersync
 go 1.25.5
 
 require (
-	github.com/gorilla/websocket v1.5.3 // indirect
-	google.golang.org/protobuf v1.36.11 // indirect
+	github.com/gorilla/websocket v1.5.3
+	google.golang.org/protobuf v1.36.11
 )
@@ -0,0 +1,60 @@
+package engine
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Spawner picks where new entities appear. It tries a number of random
+// positions inside the world and keeps the one farthest from every existing
+// entity, so clients don't spawn on top of each other.
+type Spawner struct {
+	registry *Registry
+	boundary *AABB
+	attempts int
+
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+// NewSpawner creates a Spawner over the given world boundary.
+func NewSpawner(registry *Registry, boundary *AABB, attempts int) *Spawner {
+	if attempts < 1 {
+		attempts = 1
+	}
+	return &Spawner{
+		registry: registry,
+		boundary: boundary,
+		attempts: attempts,
+		rand:     rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Next returns a spawn position inside the boundary.
+func (s *Spawner) Next() Vec2 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entities := s.registry.All()
+
+	var best Vec2
+	bestRoom := -1.0
+	for i := 0; i < s.attempts; i++ {
+		candidate := Vec2{
+			X: s.boundary.CenterX - s.boundary.HalfDim + s.rand.Float64()*2*s.boundary.HalfDim,
+			Y: s.boundary.CenterY - s.boundary.HalfDim + s.rand.Float64()*2*s.boundary.HalfDim,
+		}
+
+		room := math.Inf(1)
+		for _, e := range entities {
+			room = math.Min(room, math.Hypot(e.Position.X-candidate.X, e.Position.Y-candidate.Y))
+		}
+
+		if room > bestRoom {
+			best, bestRoom = candidate, room
+		}
+	}
+	return best
+}
@@ -0,0 +1,34 @@
+package engine
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSpawner_StaysInsideBoundary(t *testing.T) {
+	boundary := NewAABB(1000, 1000, 1000)
+	spawner := NewSpawner(NewRegistry(), boundary, 8)
+
+	for i := 0; i < 100; i++ {
+		pos := spawner.Next()
+		if !boundary.ContainsPoint(&pos) {
+			t.Fatalf("spawn position %v is outside the boundary", pos)
+		}
+	}
+}
+
+func TestSpawner_AvoidsExistingEntities(t *testing.T) {
+	registry := NewRegistry()
+	boundary := NewAABB(0, 0, 100)
+	spawner := NewSpawner(registry, boundary, 32)
+
+	// Crowd one corner; spawns should land well away from it
+	for i := 0; i < 10; i++ {
+		registry.Add(&Entity{ID: string(rune('a' + i)), Position: Vec2{X: -90, Y: -90}})
+	}
+
+	pos := spawner.Next()
+	if d := math.Hypot(pos.X+90, pos.Y+90); d < 50 {
+		t.Errorf("expected spawn far from the crowd, got %v (distance %.1f)", pos, d)
+	}
+}
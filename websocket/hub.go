@@ -32,6 +32,10 @@ type Hub struct {
 
 	// nextEntityID is used to assign unique IDs to new entities.
 	nextEntityID uint64
+
+	// spawner picks where new entities appear. Without one they appear at
+	// the origin.
+	spawner *engine.Spawner
 }
 
 // NewHub creates a new Hub.
@@ -46,6 +50,19 @@ func NewHub(registry *engine.Registry) *Hub {
 	}
 }
 
+// SetSpawner sets the spawn policy for new clients. It must be called
+// before Run.
+func (h *Hub) SetSpawner(spawner *engine.Spawner) {
+	h.spawner = spawner
+}
+
+func (h *Hub) spawnPosition() engine.Vec2 {
+	if h.spawner == nil {
+		return engine.Vec2{X: 0, Y: 0}
+	}
+	return h.spawner.Next()
+}
+
 // BroadcastTo sends a message to a specific client by its entity ID.
 func (h *Hub) BroadcastTo(entityID string, payload []byte) {
 	if client, ok := h.clientsByEntityID[entityID]; ok {
@@ -71,7 +88,7 @@ func (h *Hub) Run() {
 			h.clientsByEntityID[client.entityID] = client
 			entity := &engine.Entity{
 				ID:         client.entityID,
-				Position:   engine.Vec2{X: 0, Y: 0}, // Default position
+				Position:   h.spawnPosition(),
 				LastUpdate: time.Now(),
 			}
 			h.entityRegistry.Add(entity)